@@ -0,0 +1,533 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	Reconcile(ctx context.Context, in *ReconcileRequest, opts ...grpc.CallOption) (*ReconcileResponse, error)
+	GetReconcileReport(ctx context.Context, in *GetReconcileReportRequest, opts ...grpc.CallOption) (*ReconcileResponse, error)
+	GetActorStats(ctx context.Context, in *GetActorStatsRequest, opts ...grpc.CallOption) (*ActorStatsResponse, error)
+	ListActors(ctx context.Context, in *ListActorsRequest, opts ...grpc.CallOption) (*ListActorsResponse, error)
+	GetActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*ActorResponse, error)
+	FlushActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*ActorResponse, error)
+	DetachActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*DetachActorResponse, error)
+	ReloadActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*ActorResponse, error)
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	ListDeletedEntities(ctx context.Context, in *ListDeletedEntitiesRequest, opts ...grpc.CallOption) (*ListDeletedEntitiesResponse, error)
+	PurgeEntities(ctx context.Context, in *PurgeEntitiesRequest, opts ...grpc.CallOption) (*PurgeJobResponse, error)
+	ListPurgeJobs(ctx context.Context, in *ListPurgeJobsRequest, opts ...grpc.CallOption) (*ListPurgeJobsResponse, error)
+	GetPurgeJob(ctx context.Context, in *GetPurgeJobRequest, opts ...grpc.CallOption) (*PurgeJobResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) Reconcile(ctx context.Context, in *ReconcileRequest, opts ...grpc.CallOption) (*ReconcileResponse, error) {
+	out := new(ReconcileResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/Reconcile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetReconcileReport(ctx context.Context, in *GetReconcileReportRequest, opts ...grpc.CallOption) (*ReconcileResponse, error) {
+	out := new(ReconcileResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/GetReconcileReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetActorStats(ctx context.Context, in *GetActorStatsRequest, opts ...grpc.CallOption) (*ActorStatsResponse, error) {
+	out := new(ActorStatsResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/GetActorStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListActors(ctx context.Context, in *ListActorsRequest, opts ...grpc.CallOption) (*ListActorsResponse, error) {
+	out := new(ListActorsResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/ListActors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*ActorResponse, error) {
+	out := new(ActorResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/GetActor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) FlushActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*ActorResponse, error) {
+	out := new(ActorResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/FlushActor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DetachActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*DetachActorResponse, error) {
+	out := new(DetachActorResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/DetachActor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ReloadActor(ctx context.Context, in *ActorRequest, opts ...grpc.CallOption) (*ActorResponse, error) {
+	out := new(ActorResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/ReloadActor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/ListAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListDeletedEntities(ctx context.Context, in *ListDeletedEntitiesRequest, opts ...grpc.CallOption) (*ListDeletedEntitiesResponse, error) {
+	out := new(ListDeletedEntitiesResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/ListDeletedEntities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) PurgeEntities(ctx context.Context, in *PurgeEntitiesRequest, opts ...grpc.CallOption) (*PurgeJobResponse, error) {
+	out := new(PurgeJobResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/PurgeEntities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListPurgeJobs(ctx context.Context, in *ListPurgeJobsRequest, opts ...grpc.CallOption) (*ListPurgeJobsResponse, error) {
+	out := new(ListPurgeJobsResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/ListPurgeJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetPurgeJob(ctx context.Context, in *GetPurgeJobRequest, opts ...grpc.CallOption) (*PurgeJobResponse, error) {
+	out := new(PurgeJobResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Admin/GetPurgeJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	Reconcile(context.Context, *ReconcileRequest) (*ReconcileResponse, error)
+	GetReconcileReport(context.Context, *GetReconcileReportRequest) (*ReconcileResponse, error)
+	GetActorStats(context.Context, *GetActorStatsRequest) (*ActorStatsResponse, error)
+	ListActors(context.Context, *ListActorsRequest) (*ListActorsResponse, error)
+	GetActor(context.Context, *ActorRequest) (*ActorResponse, error)
+	FlushActor(context.Context, *ActorRequest) (*ActorResponse, error)
+	DetachActor(context.Context, *ActorRequest) (*DetachActorResponse, error)
+	ReloadActor(context.Context, *ActorRequest) (*ActorResponse, error)
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	ListDeletedEntities(context.Context, *ListDeletedEntitiesRequest) (*ListDeletedEntitiesResponse, error)
+	PurgeEntities(context.Context, *PurgeEntitiesRequest) (*PurgeJobResponse, error)
+	ListPurgeJobs(context.Context, *ListPurgeJobsRequest) (*ListPurgeJobsResponse, error)
+	GetPurgeJob(context.Context, *GetPurgeJobRequest) (*PurgeJobResponse, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) Reconcile(context.Context, *ReconcileRequest) (*ReconcileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reconcile not implemented")
+}
+func (UnimplementedAdminServer) GetReconcileReport(context.Context, *GetReconcileReportRequest) (*ReconcileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReconcileReport not implemented")
+}
+func (UnimplementedAdminServer) GetActorStats(context.Context, *GetActorStatsRequest) (*ActorStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActorStats not implemented")
+}
+func (UnimplementedAdminServer) ListActors(context.Context, *ListActorsRequest) (*ListActorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActors not implemented")
+}
+func (UnimplementedAdminServer) GetActor(context.Context, *ActorRequest) (*ActorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActor not implemented")
+}
+func (UnimplementedAdminServer) FlushActor(context.Context, *ActorRequest) (*ActorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushActor not implemented")
+}
+func (UnimplementedAdminServer) DetachActor(context.Context, *ActorRequest) (*DetachActorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetachActor not implemented")
+}
+func (UnimplementedAdminServer) ReloadActor(context.Context, *ActorRequest) (*ActorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadActor not implemented")
+}
+func (UnimplementedAdminServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedAdminServer) ListDeletedEntities(context.Context, *ListDeletedEntitiesRequest) (*ListDeletedEntitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedEntities not implemented")
+}
+func (UnimplementedAdminServer) PurgeEntities(context.Context, *PurgeEntitiesRequest) (*PurgeJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeEntities not implemented")
+}
+func (UnimplementedAdminServer) ListPurgeJobs(context.Context, *ListPurgeJobsRequest) (*ListPurgeJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPurgeJobs not implemented")
+}
+func (UnimplementedAdminServer) GetPurgeJob(context.Context, *GetPurgeJobRequest) (*PurgeJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPurgeJob not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_Reconcile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconcileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Reconcile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/Reconcile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Reconcile(ctx, req.(*ReconcileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetReconcileReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReconcileReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetReconcileReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/GetReconcileReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetReconcileReport(ctx, req.(*GetReconcileReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetActorStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActorStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetActorStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/GetActorStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetActorStats(ctx, req.(*GetActorStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListActors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListActors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/ListActors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListActors(ctx, req.(*ListActorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/GetActor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetActor(ctx, req.(*ActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_FlushActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).FlushActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/FlushActor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).FlushActor(ctx, req.(*ActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DetachActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DetachActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/DetachActor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DetachActor(ctx, req.(*ActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ReloadActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ReloadActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/ReloadActor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ReloadActor(ctx, req.(*ActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/ListAuditEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListDeletedEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedEntitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListDeletedEntities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/ListDeletedEntities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListDeletedEntities(ctx, req.(*ListDeletedEntitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_PurgeEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeEntitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).PurgeEntities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/PurgeEntities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).PurgeEntities(ctx, req.(*PurgeEntitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListPurgeJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPurgeJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListPurgeJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/ListPurgeJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListPurgeJobs(ctx, req.(*ListPurgeJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetPurgeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPurgeJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetPurgeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Admin/GetPurgeJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetPurgeJob(ctx, req.(*GetPurgeJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.core.v1.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Reconcile",
+			Handler:    _Admin_Reconcile_Handler,
+		},
+		{
+			MethodName: "GetReconcileReport",
+			Handler:    _Admin_GetReconcileReport_Handler,
+		},
+		{
+			MethodName: "GetActorStats",
+			Handler:    _Admin_GetActorStats_Handler,
+		},
+		{
+			MethodName: "ListActors",
+			Handler:    _Admin_ListActors_Handler,
+		},
+		{
+			MethodName: "GetActor",
+			Handler:    _Admin_GetActor_Handler,
+		},
+		{
+			MethodName: "FlushActor",
+			Handler:    _Admin_FlushActor_Handler,
+		},
+		{
+			MethodName: "DetachActor",
+			Handler:    _Admin_DetachActor_Handler,
+		},
+		{
+			MethodName: "ReloadActor",
+			Handler:    _Admin_ReloadActor_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _Admin_ListAuditEvents_Handler,
+		},
+		{
+			MethodName: "ListDeletedEntities",
+			Handler:    _Admin_ListDeletedEntities_Handler,
+		},
+		{
+			MethodName: "PurgeEntities",
+			Handler:    _Admin_PurgeEntities_Handler,
+		},
+		{
+			MethodName: "ListPurgeJobs",
+			Handler:    _Admin_ListPurgeJobs_Handler,
+		},
+		{
+			MethodName: "GetPurgeJob",
+			Handler:    _Admin_GetPurgeJob_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/core/v1/admin.proto",
+}
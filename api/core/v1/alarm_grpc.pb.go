@@ -0,0 +1,245 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AlarmClient is the client API for Alarm service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AlarmClient interface {
+	CreateAlarm(ctx context.Context, in *CreateAlarmRequest, opts ...grpc.CallOption) (*AlarmResponse, error)
+	DeleteAlarm(ctx context.Context, in *DeleteAlarmRequest, opts ...grpc.CallOption) (*DeleteAlarmResponse, error)
+	GetAlarm(ctx context.Context, in *GetAlarmRequest, opts ...grpc.CallOption) (*AlarmResponse, error)
+	ListAlarms(ctx context.Context, in *ListAlarmsRequest, opts ...grpc.CallOption) (*ListAlarmsResponse, error)
+	AcknowledgeAlarm(ctx context.Context, in *AcknowledgeAlarmRequest, opts ...grpc.CallOption) (*AlarmResponse, error)
+}
+
+type alarmClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAlarmClient(cc grpc.ClientConnInterface) AlarmClient {
+	return &alarmClient{cc}
+}
+
+func (c *alarmClient) CreateAlarm(ctx context.Context, in *CreateAlarmRequest, opts ...grpc.CallOption) (*AlarmResponse, error) {
+	out := new(AlarmResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Alarm/CreateAlarm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alarmClient) DeleteAlarm(ctx context.Context, in *DeleteAlarmRequest, opts ...grpc.CallOption) (*DeleteAlarmResponse, error) {
+	out := new(DeleteAlarmResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Alarm/DeleteAlarm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alarmClient) GetAlarm(ctx context.Context, in *GetAlarmRequest, opts ...grpc.CallOption) (*AlarmResponse, error) {
+	out := new(AlarmResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Alarm/GetAlarm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alarmClient) ListAlarms(ctx context.Context, in *ListAlarmsRequest, opts ...grpc.CallOption) (*ListAlarmsResponse, error) {
+	out := new(ListAlarmsResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Alarm/ListAlarms", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alarmClient) AcknowledgeAlarm(ctx context.Context, in *AcknowledgeAlarmRequest, opts ...grpc.CallOption) (*AlarmResponse, error) {
+	out := new(AlarmResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Alarm/AcknowledgeAlarm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AlarmServer is the server API for Alarm service.
+// All implementations must embed UnimplementedAlarmServer
+// for forward compatibility
+type AlarmServer interface {
+	CreateAlarm(context.Context, *CreateAlarmRequest) (*AlarmResponse, error)
+	DeleteAlarm(context.Context, *DeleteAlarmRequest) (*DeleteAlarmResponse, error)
+	GetAlarm(context.Context, *GetAlarmRequest) (*AlarmResponse, error)
+	ListAlarms(context.Context, *ListAlarmsRequest) (*ListAlarmsResponse, error)
+	AcknowledgeAlarm(context.Context, *AcknowledgeAlarmRequest) (*AlarmResponse, error)
+	mustEmbedUnimplementedAlarmServer()
+}
+
+// UnimplementedAlarmServer must be embedded to have forward compatible implementations.
+type UnimplementedAlarmServer struct {
+}
+
+func (UnimplementedAlarmServer) CreateAlarm(context.Context, *CreateAlarmRequest) (*AlarmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAlarm not implemented")
+}
+func (UnimplementedAlarmServer) DeleteAlarm(context.Context, *DeleteAlarmRequest) (*DeleteAlarmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlarm not implemented")
+}
+func (UnimplementedAlarmServer) GetAlarm(context.Context, *GetAlarmRequest) (*AlarmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAlarm not implemented")
+}
+func (UnimplementedAlarmServer) ListAlarms(context.Context, *ListAlarmsRequest) (*ListAlarmsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlarms not implemented")
+}
+func (UnimplementedAlarmServer) AcknowledgeAlarm(context.Context, *AcknowledgeAlarmRequest) (*AlarmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcknowledgeAlarm not implemented")
+}
+func (UnimplementedAlarmServer) mustEmbedUnimplementedAlarmServer() {}
+
+// UnsafeAlarmServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AlarmServer will
+// result in compilation errors.
+type UnsafeAlarmServer interface {
+	mustEmbedUnimplementedAlarmServer()
+}
+
+func RegisterAlarmServer(s grpc.ServiceRegistrar, srv AlarmServer) {
+	s.RegisterService(&Alarm_ServiceDesc, srv)
+}
+
+func _Alarm_CreateAlarm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAlarmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlarmServer).CreateAlarm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Alarm/CreateAlarm",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlarmServer).CreateAlarm(ctx, req.(*CreateAlarmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Alarm_DeleteAlarm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAlarmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlarmServer).DeleteAlarm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Alarm/DeleteAlarm",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlarmServer).DeleteAlarm(ctx, req.(*DeleteAlarmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Alarm_GetAlarm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAlarmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlarmServer).GetAlarm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Alarm/GetAlarm",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlarmServer).GetAlarm(ctx, req.(*GetAlarmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Alarm_ListAlarms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlarmsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlarmServer).ListAlarms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Alarm/ListAlarms",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlarmServer).ListAlarms(ctx, req.(*ListAlarmsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Alarm_AcknowledgeAlarm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcknowledgeAlarmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlarmServer).AcknowledgeAlarm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Alarm/AcknowledgeAlarm",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlarmServer).AcknowledgeAlarm(ctx, req.(*AcknowledgeAlarmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Alarm_ServiceDesc is the grpc.ServiceDesc for Alarm service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Alarm_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.core.v1.Alarm",
+	HandlerType: (*AlarmServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAlarm",
+			Handler:    _Alarm_CreateAlarm_Handler,
+		},
+		{
+			MethodName: "DeleteAlarm",
+			Handler:    _Alarm_DeleteAlarm_Handler,
+		},
+		{
+			MethodName: "GetAlarm",
+			Handler:    _Alarm_GetAlarm_Handler,
+		},
+		{
+			MethodName: "ListAlarms",
+			Handler:    _Alarm_ListAlarms_Handler,
+		},
+		{
+			MethodName: "AcknowledgeAlarm",
+			Handler:    _Alarm_AcknowledgeAlarm_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/core/v1/alarm.proto",
+}
@@ -23,6 +23,7 @@ type EntityClient interface {
 	PatchEntity(ctx context.Context, in *PatchEntityRequest, opts ...grpc.CallOption) (*EntityResponse, error)
 	PatchEntityZ(ctx context.Context, in *PatchEntityRequest, opts ...grpc.CallOption) (*EntityResponse, error)
 	DeleteEntity(ctx context.Context, in *DeleteEntityRequest, opts ...grpc.CallOption) (*DeleteEntityResponse, error)
+	RestoreEntity(ctx context.Context, in *RestoreEntityRequest, opts ...grpc.CallOption) (*EntityResponse, error)
 	GetEntity(ctx context.Context, in *GetEntityRequest, opts ...grpc.CallOption) (*EntityResponse, error)
 	ListEntity(ctx context.Context, in *ListEntityRequest, opts ...grpc.CallOption) (*ListEntityResponse, error)
 	AppendMapper(ctx context.Context, in *AppendMapperRequest, opts ...grpc.CallOption) (*EntityResponse, error)
@@ -32,6 +33,17 @@ type EntityClient interface {
 	RemoveConfigs(ctx context.Context, in *RemoveConfigsRequest, opts ...grpc.CallOption) (*EntityResponse, error)
 	QueryConfigs(ctx context.Context, in *QueryConfigsRequest, opts ...grpc.CallOption) (*EntityResponse, error)
 	PatchConfigs(ctx context.Context, in *PatchConfigsRequest, opts ...grpc.CallOption) (*EntityResponse, error)
+	AddRelationship(ctx context.Context, in *AddRelationshipRequest, opts ...grpc.CallOption) (*EntityResponse, error)
+	RemoveRelationship(ctx context.Context, in *RemoveRelationshipRequest, opts ...grpc.CallOption) (*EntityResponse, error)
+	ListRelationships(ctx context.Context, in *ListRelationshipsRequest, opts ...grpc.CallOption) (*ListRelationshipsResponse, error)
+	TraverseRelationships(ctx context.Context, in *TraverseRelationshipsRequest, opts ...grpc.CallOption) (*TraverseRelationshipsResponse, error)
+	FindRelationshipPath(ctx context.Context, in *FindRelationshipPathRequest, opts ...grpc.CallOption) (*FindRelationshipPathResponse, error)
+	SetTimer(ctx context.Context, in *SetTimerRequest, opts ...grpc.CallOption) (*Timer, error)
+	DeleteTimer(ctx context.Context, in *DeleteTimerRequest, opts ...grpc.CallOption) (*Timer, error)
+	ListTimers(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (*ListTimersResponse, error)
+	BatchCreateEntities(ctx context.Context, in *BatchCreateEntitiesRequest, opts ...grpc.CallOption) (*BatchEntitiesResponse, error)
+	BatchUpdateProperties(ctx context.Context, in *BatchUpdatePropertiesRequest, opts ...grpc.CallOption) (*BatchEntitiesResponse, error)
+	BatchDeleteEntities(ctx context.Context, in *BatchDeleteEntitiesRequest, opts ...grpc.CallOption) (*BatchEntitiesResponse, error)
 	GetEntityProps(ctx context.Context, in *GetEntityPropsRequest, opts ...grpc.CallOption) (*EntityResponse, error)
 }
 
@@ -88,6 +100,15 @@ func (c *entityClient) DeleteEntity(ctx context.Context, in *DeleteEntityRequest
 	return out, nil
 }
 
+func (c *entityClient) RestoreEntity(ctx context.Context, in *RestoreEntityRequest, opts ...grpc.CallOption) (*EntityResponse, error) {
+	out := new(EntityResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/RestoreEntity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *entityClient) GetEntity(ctx context.Context, in *GetEntityRequest, opts ...grpc.CallOption) (*EntityResponse, error) {
 	out := new(EntityResponse)
 	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/GetEntity", in, out, opts...)
@@ -169,6 +190,105 @@ func (c *entityClient) PatchConfigs(ctx context.Context, in *PatchConfigsRequest
 	return out, nil
 }
 
+func (c *entityClient) AddRelationship(ctx context.Context, in *AddRelationshipRequest, opts ...grpc.CallOption) (*EntityResponse, error) {
+	out := new(EntityResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/AddRelationship", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) RemoveRelationship(ctx context.Context, in *RemoveRelationshipRequest, opts ...grpc.CallOption) (*EntityResponse, error) {
+	out := new(EntityResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/RemoveRelationship", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) ListRelationships(ctx context.Context, in *ListRelationshipsRequest, opts ...grpc.CallOption) (*ListRelationshipsResponse, error) {
+	out := new(ListRelationshipsResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/ListRelationships", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) TraverseRelationships(ctx context.Context, in *TraverseRelationshipsRequest, opts ...grpc.CallOption) (*TraverseRelationshipsResponse, error) {
+	out := new(TraverseRelationshipsResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/TraverseRelationships", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) FindRelationshipPath(ctx context.Context, in *FindRelationshipPathRequest, opts ...grpc.CallOption) (*FindRelationshipPathResponse, error) {
+	out := new(FindRelationshipPathResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/FindRelationshipPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) SetTimer(ctx context.Context, in *SetTimerRequest, opts ...grpc.CallOption) (*Timer, error) {
+	out := new(Timer)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/SetTimer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) DeleteTimer(ctx context.Context, in *DeleteTimerRequest, opts ...grpc.CallOption) (*Timer, error) {
+	out := new(Timer)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/DeleteTimer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) ListTimers(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (*ListTimersResponse, error) {
+	out := new(ListTimersResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/ListTimers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) BatchCreateEntities(ctx context.Context, in *BatchCreateEntitiesRequest, opts ...grpc.CallOption) (*BatchEntitiesResponse, error) {
+	out := new(BatchEntitiesResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/BatchCreateEntities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) BatchUpdateProperties(ctx context.Context, in *BatchUpdatePropertiesRequest, opts ...grpc.CallOption) (*BatchEntitiesResponse, error) {
+	out := new(BatchEntitiesResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/BatchUpdateProperties", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityClient) BatchDeleteEntities(ctx context.Context, in *BatchDeleteEntitiesRequest, opts ...grpc.CallOption) (*BatchEntitiesResponse, error) {
+	out := new(BatchEntitiesResponse)
+	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/BatchDeleteEntities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *entityClient) GetEntityProps(ctx context.Context, in *GetEntityPropsRequest, opts ...grpc.CallOption) (*EntityResponse, error) {
 	out := new(EntityResponse)
 	err := c.cc.Invoke(ctx, "/api.core.v1.Entity/GetEntityProps", in, out, opts...)
@@ -187,6 +307,7 @@ type EntityServer interface {
 	PatchEntity(context.Context, *PatchEntityRequest) (*EntityResponse, error)
 	PatchEntityZ(context.Context, *PatchEntityRequest) (*EntityResponse, error)
 	DeleteEntity(context.Context, *DeleteEntityRequest) (*DeleteEntityResponse, error)
+	RestoreEntity(context.Context, *RestoreEntityRequest) (*EntityResponse, error)
 	GetEntity(context.Context, *GetEntityRequest) (*EntityResponse, error)
 	ListEntity(context.Context, *ListEntityRequest) (*ListEntityResponse, error)
 	AppendMapper(context.Context, *AppendMapperRequest) (*EntityResponse, error)
@@ -196,6 +317,17 @@ type EntityServer interface {
 	RemoveConfigs(context.Context, *RemoveConfigsRequest) (*EntityResponse, error)
 	QueryConfigs(context.Context, *QueryConfigsRequest) (*EntityResponse, error)
 	PatchConfigs(context.Context, *PatchConfigsRequest) (*EntityResponse, error)
+	AddRelationship(context.Context, *AddRelationshipRequest) (*EntityResponse, error)
+	RemoveRelationship(context.Context, *RemoveRelationshipRequest) (*EntityResponse, error)
+	ListRelationships(context.Context, *ListRelationshipsRequest) (*ListRelationshipsResponse, error)
+	TraverseRelationships(context.Context, *TraverseRelationshipsRequest) (*TraverseRelationshipsResponse, error)
+	FindRelationshipPath(context.Context, *FindRelationshipPathRequest) (*FindRelationshipPathResponse, error)
+	SetTimer(context.Context, *SetTimerRequest) (*Timer, error)
+	DeleteTimer(context.Context, *DeleteTimerRequest) (*Timer, error)
+	ListTimers(context.Context, *ListTimersRequest) (*ListTimersResponse, error)
+	BatchCreateEntities(context.Context, *BatchCreateEntitiesRequest) (*BatchEntitiesResponse, error)
+	BatchUpdateProperties(context.Context, *BatchUpdatePropertiesRequest) (*BatchEntitiesResponse, error)
+	BatchDeleteEntities(context.Context, *BatchDeleteEntitiesRequest) (*BatchEntitiesResponse, error)
 	GetEntityProps(context.Context, *GetEntityPropsRequest) (*EntityResponse, error)
 	mustEmbedUnimplementedEntityServer()
 }
@@ -219,6 +351,9 @@ func (UnimplementedEntityServer) PatchEntityZ(context.Context, *PatchEntityReque
 func (UnimplementedEntityServer) DeleteEntity(context.Context, *DeleteEntityRequest) (*DeleteEntityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteEntity not implemented")
 }
+func (UnimplementedEntityServer) RestoreEntity(context.Context, *RestoreEntityRequest) (*EntityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEntity not implemented")
+}
 func (UnimplementedEntityServer) GetEntity(context.Context, *GetEntityRequest) (*EntityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEntity not implemented")
 }
@@ -246,6 +381,39 @@ func (UnimplementedEntityServer) QueryConfigs(context.Context, *QueryConfigsRequ
 func (UnimplementedEntityServer) PatchConfigs(context.Context, *PatchConfigsRequest) (*EntityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PatchConfigs not implemented")
 }
+func (UnimplementedEntityServer) AddRelationship(context.Context, *AddRelationshipRequest) (*EntityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRelationship not implemented")
+}
+func (UnimplementedEntityServer) RemoveRelationship(context.Context, *RemoveRelationshipRequest) (*EntityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRelationship not implemented")
+}
+func (UnimplementedEntityServer) ListRelationships(context.Context, *ListRelationshipsRequest) (*ListRelationshipsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRelationships not implemented")
+}
+func (UnimplementedEntityServer) TraverseRelationships(context.Context, *TraverseRelationshipsRequest) (*TraverseRelationshipsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TraverseRelationships not implemented")
+}
+func (UnimplementedEntityServer) FindRelationshipPath(context.Context, *FindRelationshipPathRequest) (*FindRelationshipPathResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindRelationshipPath not implemented")
+}
+func (UnimplementedEntityServer) SetTimer(context.Context, *SetTimerRequest) (*Timer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTimer not implemented")
+}
+func (UnimplementedEntityServer) DeleteTimer(context.Context, *DeleteTimerRequest) (*Timer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTimer not implemented")
+}
+func (UnimplementedEntityServer) ListTimers(context.Context, *ListTimersRequest) (*ListTimersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTimers not implemented")
+}
+func (UnimplementedEntityServer) BatchCreateEntities(context.Context, *BatchCreateEntitiesRequest) (*BatchEntitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateEntities not implemented")
+}
+func (UnimplementedEntityServer) BatchUpdateProperties(context.Context, *BatchUpdatePropertiesRequest) (*BatchEntitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdateProperties not implemented")
+}
+func (UnimplementedEntityServer) BatchDeleteEntities(context.Context, *BatchDeleteEntitiesRequest) (*BatchEntitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteEntities not implemented")
+}
 func (UnimplementedEntityServer) GetEntityProps(context.Context, *GetEntityPropsRequest) (*EntityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEntityProps not implemented")
 }
@@ -352,6 +520,24 @@ func _Entity_DeleteEntity_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Entity_RestoreEntity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEntityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).RestoreEntity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/RestoreEntity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).RestoreEntity(ctx, req.(*RestoreEntityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Entity_GetEntity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetEntityRequest)
 	if err := dec(in); err != nil {
@@ -514,6 +700,204 @@ func _Entity_PatchConfigs_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Entity_AddRelationship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRelationshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).AddRelationship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/AddRelationship",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).AddRelationship(ctx, req.(*AddRelationshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_RemoveRelationship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRelationshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).RemoveRelationship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/RemoveRelationship",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).RemoveRelationship(ctx, req.(*RemoveRelationshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_ListRelationships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRelationshipsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).ListRelationships(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/ListRelationships",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).ListRelationships(ctx, req.(*ListRelationshipsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_TraverseRelationships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraverseRelationshipsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).TraverseRelationships(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/TraverseRelationships",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).TraverseRelationships(ctx, req.(*TraverseRelationshipsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_FindRelationshipPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindRelationshipPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).FindRelationshipPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/FindRelationshipPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).FindRelationshipPath(ctx, req.(*FindRelationshipPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_SetTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).SetTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/SetTimer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).SetTimer(ctx, req.(*SetTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_DeleteTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).DeleteTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/DeleteTimer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).DeleteTimer(ctx, req.(*DeleteTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_ListTimers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTimersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).ListTimers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/ListTimers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).ListTimers(ctx, req.(*ListTimersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_BatchCreateEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateEntitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).BatchCreateEntities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/BatchCreateEntities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).BatchCreateEntities(ctx, req.(*BatchCreateEntitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_BatchUpdateProperties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdatePropertiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).BatchUpdateProperties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/BatchUpdateProperties",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).BatchUpdateProperties(ctx, req.(*BatchUpdatePropertiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Entity_BatchDeleteEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteEntitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServer).BatchDeleteEntities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.core.v1.Entity/BatchDeleteEntities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServer).BatchDeleteEntities(ctx, req.(*BatchDeleteEntitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Entity_GetEntityProps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetEntityPropsRequest)
 	if err := dec(in); err != nil {
@@ -559,6 +943,10 @@ var Entity_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteEntity",
 			Handler:    _Entity_DeleteEntity_Handler,
 		},
+		{
+			MethodName: "RestoreEntity",
+			Handler:    _Entity_RestoreEntity_Handler,
+		},
 		{
 			MethodName: "GetEntity",
 			Handler:    _Entity_GetEntity_Handler,
@@ -595,6 +983,50 @@ var Entity_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PatchConfigs",
 			Handler:    _Entity_PatchConfigs_Handler,
 		},
+		{
+			MethodName: "AddRelationship",
+			Handler:    _Entity_AddRelationship_Handler,
+		},
+		{
+			MethodName: "RemoveRelationship",
+			Handler:    _Entity_RemoveRelationship_Handler,
+		},
+		{
+			MethodName: "ListRelationships",
+			Handler:    _Entity_ListRelationships_Handler,
+		},
+		{
+			MethodName: "TraverseRelationships",
+			Handler:    _Entity_TraverseRelationships_Handler,
+		},
+		{
+			MethodName: "FindRelationshipPath",
+			Handler:    _Entity_FindRelationshipPath_Handler,
+		},
+		{
+			MethodName: "SetTimer",
+			Handler:    _Entity_SetTimer_Handler,
+		},
+		{
+			MethodName: "DeleteTimer",
+			Handler:    _Entity_DeleteTimer_Handler,
+		},
+		{
+			MethodName: "ListTimers",
+			Handler:    _Entity_ListTimers_Handler,
+		},
+		{
+			MethodName: "BatchCreateEntities",
+			Handler:    _Entity_BatchCreateEntities_Handler,
+		},
+		{
+			MethodName: "BatchUpdateProperties",
+			Handler:    _Entity_BatchUpdateProperties_Handler,
+		},
+		{
+			MethodName: "BatchDeleteEntities",
+			Handler:    _Entity_BatchDeleteEntities_Handler,
+		},
 		{
 			MethodName: "GetEntityProps",
 			Handler:    _Entity_GetEntityProps_Handler,
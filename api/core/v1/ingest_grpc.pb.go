@@ -0,0 +1,137 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// IngestClient is the client API for Ingest service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IngestClient interface {
+	// Stream accepts batches of property updates, every batch is acked in order.
+	// a client may have at most `credits` batches unacked, further batches are blocked.
+	Stream(ctx context.Context, opts ...grpc.CallOption) (Ingest_StreamClient, error)
+}
+
+type ingestClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIngestClient(cc grpc.ClientConnInterface) IngestClient {
+	return &ingestClient{cc}
+}
+
+func (c *ingestClient) Stream(ctx context.Context, opts ...grpc.CallOption) (Ingest_StreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Ingest_ServiceDesc.Streams[0], "/api.core.v1.Ingest/Stream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ingestStreamClient{stream}
+	return x, nil
+}
+
+type Ingest_StreamClient interface {
+	Send(*IngestBatch) error
+	Recv() (*IngestAck, error)
+	grpc.ClientStream
+}
+
+type ingestStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *ingestStreamClient) Send(m *IngestBatch) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *ingestStreamClient) Recv() (*IngestAck, error) {
+	m := new(IngestAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// IngestServer is the server API for Ingest service.
+// All implementations must embed UnimplementedIngestServer
+// for forward compatibility
+type IngestServer interface {
+	// Stream accepts batches of property updates, every batch is acked in order.
+	// a client may have at most `credits` batches unacked, further batches are blocked.
+	Stream(Ingest_StreamServer) error
+	mustEmbedUnimplementedIngestServer()
+}
+
+// UnimplementedIngestServer must be embedded to have forward compatible implementations.
+type UnimplementedIngestServer struct {
+}
+
+func (UnimplementedIngestServer) Stream(Ingest_StreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
+func (UnimplementedIngestServer) mustEmbedUnimplementedIngestServer() {}
+
+// UnsafeIngestServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IngestServer will
+// result in compilation errors.
+type UnsafeIngestServer interface {
+	mustEmbedUnimplementedIngestServer()
+}
+
+func RegisterIngestServer(s grpc.ServiceRegistrar, srv IngestServer) {
+	s.RegisterService(&Ingest_ServiceDesc, srv)
+}
+
+func _Ingest_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IngestServer).Stream(&ingestStreamServer{stream})
+}
+
+type Ingest_StreamServer interface {
+	Send(*IngestAck) error
+	Recv() (*IngestBatch, error)
+	grpc.ServerStream
+}
+
+type ingestStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *ingestStreamServer) Send(m *IngestAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *ingestStreamServer) Recv() (*IngestBatch, error) {
+	m := new(IngestBatch)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Ingest_ServiceDesc is the grpc.ServiceDesc for Ingest service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Ingest_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.core.v1.Ingest",
+	HandlerType: (*IngestServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _Ingest_Stream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api/core/v1/ingest.proto",
+}
@@ -0,0 +1,202 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TransferClient is the client API for Transfer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TransferClient interface {
+	// Export streams entities as ndjson or a tar archive in chunks.
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (Transfer_ExportClient, error)
+	// Import reads entities exported before, mode and dry_run are read from the first chunk.
+	Import(ctx context.Context, opts ...grpc.CallOption) (Transfer_ImportClient, error)
+}
+
+type transferClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransferClient(cc grpc.ClientConnInterface) TransferClient {
+	return &transferClient{cc}
+}
+
+func (c *transferClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (Transfer_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Transfer_ServiceDesc.Streams[0], "/api.core.v1.Transfer/Export", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transferExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Transfer_ExportClient interface {
+	Recv() (*ExportChunk, error)
+	grpc.ClientStream
+}
+
+type transferExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *transferExportClient) Recv() (*ExportChunk, error) {
+	m := new(ExportChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transferClient) Import(ctx context.Context, opts ...grpc.CallOption) (Transfer_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Transfer_ServiceDesc.Streams[1], "/api.core.v1.Transfer/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transferImportClient{stream}
+	return x, nil
+}
+
+type Transfer_ImportClient interface {
+	Send(*ImportChunk) error
+	CloseAndRecv() (*ImportResponse, error)
+	grpc.ClientStream
+}
+
+type transferImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *transferImportClient) Send(m *ImportChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *transferImportClient) CloseAndRecv() (*ImportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TransferServer is the server API for Transfer service.
+// All implementations must embed UnimplementedTransferServer
+// for forward compatibility
+type TransferServer interface {
+	// Export streams entities as ndjson or a tar archive in chunks.
+	Export(*ExportRequest, Transfer_ExportServer) error
+	// Import reads entities exported before, mode and dry_run are read from the first chunk.
+	Import(Transfer_ImportServer) error
+	mustEmbedUnimplementedTransferServer()
+}
+
+// UnimplementedTransferServer must be embedded to have forward compatible implementations.
+type UnimplementedTransferServer struct {
+}
+
+func (UnimplementedTransferServer) Export(*ExportRequest, Transfer_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedTransferServer) Import(Transfer_ImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (UnimplementedTransferServer) mustEmbedUnimplementedTransferServer() {}
+
+// UnsafeTransferServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransferServer will
+// result in compilation errors.
+type UnsafeTransferServer interface {
+	mustEmbedUnimplementedTransferServer()
+}
+
+func RegisterTransferServer(s grpc.ServiceRegistrar, srv TransferServer) {
+	s.RegisterService(&Transfer_ServiceDesc, srv)
+}
+
+func _Transfer_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransferServer).Export(m, &transferExportServer{stream})
+}
+
+type Transfer_ExportServer interface {
+	Send(*ExportChunk) error
+	grpc.ServerStream
+}
+
+type transferExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *transferExportServer) Send(m *ExportChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Transfer_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TransferServer).Import(&transferImportServer{stream})
+}
+
+type Transfer_ImportServer interface {
+	SendAndClose(*ImportResponse) error
+	Recv() (*ImportChunk, error)
+	grpc.ServerStream
+}
+
+type transferImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *transferImportServer) SendAndClose(m *ImportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *transferImportServer) Recv() (*ImportChunk, error) {
+	m := new(ImportChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Transfer_ServiceDesc is the grpc.ServiceDesc for Transfer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Transfer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.core.v1.Transfer",
+	HandlerType: (*TransferServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Export",
+			Handler:       _Transfer_Export_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Import",
+			Handler:       _Transfer_Import_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api/core/v1/transfer.proto",
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func newActorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "actors",
+		Short: "Inspect actor runtime of a running core",
+	}
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show statistics of actor runtime",
+		Args:  cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewAdminClient(conn).GetActorStats(ctx, &corev1.GetActorStatsRequest{})
+			if nil != err {
+				return errors.Wrap(err, "get actor stats")
+			}
+
+			stats := out.GetStats()
+			return print.Output(os.Stdout, _output, stats, func() print.Table {
+				table := print.Table{
+					Header: []string{"name", "value"},
+					Rows: [][]string{
+						{"state machines", fmt.Sprint(stats.GetStateMachines())},
+						{"inflight messages", fmt.Sprint(stats.GetInflight())},
+						{"message queue", fmt.Sprintf("%d/%d", stats.GetMsgQueueLength(), stats.GetMsgQueueCapacity())},
+						{"dispose queue", fmt.Sprintf("%d/%d", stats.GetDisposeQueueLength(), stats.GetDisposeQueueCapacity())},
+						{"coroutines", fmt.Sprintf("%d/%d", stats.GetCoroutinesRunning(), stats.GetCoroutinesCapacity())},
+					},
+				}
+				for _, container := range stats.GetContainers() {
+					table.Rows = append(table.Rows, []string{"container " + container.Id, fmt.Sprint(container.StateMachines)})
+				}
+				return table
+			})
+		}),
+	}
+
+	var containerID string
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List state machines loaded in runtime",
+		Args:  cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewAdminClient(conn).ListActors(ctx, &corev1.ListActorsRequest{Container: containerID})
+			if nil != err {
+				return errors.Wrap(err, "list actors")
+			}
+			return printActors(out, out.Items...)
+		}),
+	}
+	listCmd.Flags().StringVar(&containerID, "container", "", "container id, all containers if empty.")
+
+	actorCmd := func(use, short string, call func(context.Context, corev1.AdminClient, *corev1.ActorRequest) (*corev1.ActorResponse, error)) *cobra.Command {
+		return &cobra.Command{
+			Use:   use + " <entity-id>",
+			Short: short,
+			Args:  cobra.ExactArgs(1),
+			RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+				out, err := call(ctx, corev1.NewAdminClient(conn), &corev1.ActorRequest{Id: args[0]})
+				if nil != err {
+					return errors.Wrapf(err, "%s actor", use)
+				}
+				return printActors(out, out.Actor)
+			}),
+		}
+	}
+
+	getCmd := actorCmd("get", "Show runtime information of a state machine",
+		func(ctx context.Context, c corev1.AdminClient, req *corev1.ActorRequest) (*corev1.ActorResponse, error) {
+			return c.GetActor(ctx, req)
+		})
+	flushCmd := actorCmd("flush", "Force flush a state machine",
+		func(ctx context.Context, c corev1.AdminClient, req *corev1.ActorRequest) (*corev1.ActorResponse, error) {
+			return c.FlushActor(ctx, req)
+		})
+	reloadCmd := actorCmd("reload", "Reload a state machine from state store",
+		func(ctx context.Context, c corev1.AdminClient, req *corev1.ActorRequest) (*corev1.ActorResponse, error) {
+			return c.ReloadActor(ctx, req)
+		})
+
+	detachCmd := &cobra.Command{
+		Use:   "detach <entity-id>",
+		Short: "Detach a state machine from runtime, it is loaded again on next message",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewAdminClient(conn).DetachActor(ctx, &corev1.ActorRequest{Id: args[0]})
+			if nil != err {
+				return errors.Wrap(err, "detach actor")
+			}
+			return print.Output(os.Stdout, _output, out, func() print.Table {
+				return print.Table{
+					Header: []string{"id", "status"},
+					Rows:   [][]string{{out.Id, out.Status}},
+				}
+			})
+		}),
+	}
+
+	cmd.AddCommand(statsCmd, listCmd, getCmd, flushCmd, detachCmd, reloadCmd)
+	return cmd
+}
+
+// printActors prints v in output format, state machines are rows of table.
+func printActors(v interface{}, actors ...*corev1.ActorInfo) error {
+	return print.Output(os.Stdout, _output, v, func() print.Table {
+		table := print.Table{Header: []string{"id", "type", "container", "status", "runtime", "tentacles", "mappers", "last flush"}}
+		for _, actor := range actors {
+			lastFlush := "-"
+			if actor.LastFlushTime > 0 {
+				lastFlush = time.Unix(0, actor.LastFlushTime*int64(time.Millisecond)).Format(time.RFC3339)
+			}
+			table.Rows = append(table.Rows, []string{actor.Id, actor.Type, actor.Container, actor.Status, actor.RuntimeStatus,
+				fmt.Sprint(len(actor.Tentacles)), fmt.Sprint(len(actor.Mappers)), lastFlush})
+		}
+		return table
+	})
+}
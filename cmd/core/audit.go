@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query entity lifecycle events",
+	}
+
+	var (
+		req   corev1.ListAuditEventsRequest
+		since time.Duration
+		until time.Duration
+	)
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List lifecycle events, latest first",
+		Args:  cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			now := time.Now()
+			if since > 0 {
+				req.StartTime = now.Add(-since).UnixNano() / int64(time.Millisecond)
+			}
+			if until > 0 {
+				req.EndTime = now.Add(-until).UnixNano() / int64(time.Millisecond)
+			}
+
+			out, err := corev1.NewAdminClient(conn).ListAuditEvents(ctx, &req)
+			if nil != err {
+				return errors.Wrap(err, "list audit events")
+			}
+			return print.Output(os.Stdout, _output, out, func() print.Table {
+				table := print.Table{Header: []string{"time", "type", "entity", "actor", "version", "changes"}}
+				for _, event := range out.Items {
+					var paths []string
+					for _, change := range event.GetDiff().GetListValue().GetValues() {
+						paths = append(paths, change.GetStructValue().GetFields()["path"].GetStringValue())
+					}
+					table.Rows = append(table.Rows, []string{
+						time.Unix(0, event.Time*int64(time.Millisecond)).Format(time.RFC3339),
+						strings.TrimPrefix(event.Type, "io.tkeel.core."),
+						event.EntityId,
+						event.Actor,
+						fmt.Sprint(event.Version),
+						strings.Join(paths, ","),
+					})
+				}
+				return table
+			})
+		}),
+	}
+	listCmd.Flags().StringVar(&req.EntityId, "entity", "", "entity id, all entities if empty.")
+	listCmd.Flags().StringVar(&req.Actor, "actor", "", "who performed operations.")
+	listCmd.Flags().StringVar(&req.Type, "type", "", "event type, e.g. io.tkeel.core.entity.configs.changed.")
+	listCmd.Flags().DurationVar(&since, "since", 0, "list events newer than this duration ago.")
+	listCmd.Flags().DurationVar(&until, "until", 0, "list events older than this duration ago.")
+	listCmd.Flags().Int32Var(&req.Limit, "limit", 100, "max number of events.")
+
+	cmd.AddCommand(listCmd)
+	return cmd
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newEntityBatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Create, update or delete entities in a batch",
+	}
+
+	var (
+		filename string
+		failFast bool
+	)
+	addFlags := func(c *cobra.Command) {
+		c.Flags().StringVarP(&filename, "file", "f", "", "file of items, json or yaml array.")
+		c.Flags().BoolVar(&failFast, "fail-fast", false, "stop starting items after the first failure.")
+		c.MarkFlagRequired("file") //nolint
+	}
+
+	createCmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Create entities in a batch",
+		Example: `core entity batch create --owner admin -f devices.yaml  # items: [{id: device1, type: DEVICE, properties: {temp: 20}}]`,
+		Args:    cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			req := &corev1.BatchCreateEntitiesRequest{FailFast: failFast}
+			err := readBatchItems(filename, func() proto.Message {
+				item := &corev1.CreateEntityRequest{Owner: _owner, Source: _source}
+				req.Entities = append(req.Entities, item)
+				return item
+			})
+			if nil != err {
+				return errors.Wrap(err, "batch create entities")
+			}
+
+			out, err := corev1.NewEntityClient(conn).BatchCreateEntities(ctx, req)
+			if nil != err {
+				return errors.Wrap(err, "batch create entities")
+			}
+			return printBatch(out)
+		}),
+	}
+	addFlags(createCmd)
+
+	updateCmd := &cobra.Command{
+		Use:     "update",
+		Short:   "Update properties of entities in a batch",
+		Example: `core entity batch update --owner admin -f properties.yaml  # items: [{id: device1, properties: {temp: 25}}]`,
+		Args:    cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			req := &corev1.BatchUpdatePropertiesRequest{FailFast: failFast}
+			err := readBatchItems(filename, func() proto.Message {
+				item := &corev1.UpdateEntityRequest{Owner: _owner, Source: _source}
+				req.Entities = append(req.Entities, item)
+				return item
+			})
+			if nil != err {
+				return errors.Wrap(err, "batch update properties")
+			}
+
+			out, err := corev1.NewEntityClient(conn).BatchUpdateProperties(ctx, req)
+			if nil != err {
+				return errors.Wrap(err, "batch update properties")
+			}
+			return printBatch(out)
+		}),
+	}
+	addFlags(updateCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:     "delete",
+		Short:   "Delete entities in a batch, restorable until purged",
+		Example: `core entity batch delete --owner admin -f ids.yaml  # items: [device1, device2]`,
+		Args:    cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			req := &corev1.BatchDeleteEntitiesRequest{FailFast: failFast}
+			err := readBatchItems(filename, func() proto.Message {
+				item := &corev1.DeleteEntityRequest{Owner: _owner, Source: _source}
+				req.Entities = append(req.Entities, item)
+				return item
+			})
+			if nil != err {
+				return errors.Wrap(err, "batch delete entities")
+			}
+
+			out, err := corev1.NewEntityClient(conn).BatchDeleteEntities(ctx, req)
+			if nil != err {
+				return errors.Wrap(err, "batch delete entities")
+			}
+			return printBatch(out)
+		}),
+	}
+	addFlags(deleteCmd)
+
+	cmd.AddCommand(createCmd, updateCmd, deleteCmd)
+	return cmd
+}
+
+// readBatchItems reads array of items from file, an item is an object of request fields or an entity id.
+func readBatchItems(filename string, newItem func() proto.Message) error {
+	val, err := readValue("", filename)
+	if nil != err {
+		return err
+	}
+
+	list := val.GetListValue()
+	if nil == list {
+		return errors.New("items should be an array")
+	}
+
+	for index, item := range list.Values {
+		if id, ok := item.Kind.(*structpb.Value_StringValue); ok {
+			item = structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+				"id": structpb.NewStringValue(id.StringValue),
+			}})
+		}
+
+		bytes, err := protojson.Marshal(item)
+		if nil != err {
+			return errors.Wrapf(err, "item %d", index)
+		}
+
+		// fields absent in item keep the defaults, e.g. owner.
+		msg := newItem()
+		fields := proto.Clone(msg)
+		proto.Reset(fields)
+		if err = protojson.Unmarshal(bytes, fields); nil != err {
+			return errors.Wrapf(err, "item %d", index)
+		}
+		proto.Merge(msg, fields)
+	}
+	return nil
+}
+
+// printBatch prints result of every item and a summary.
+func printBatch(out *corev1.BatchEntitiesResponse) error {
+	err := print.Output(os.Stdout, _output, out, func() print.Table {
+		table := print.Table{Header: []string{"id", "status", "error"}}
+		for _, result := range out.Results {
+			table.Rows = append(table.Rows, []string{result.Id, result.Status, result.Error})
+		}
+		return table
+	})
+	if nil == err && _output == print.OutputTable {
+		fmt.Printf("\n%d succeeded, %d failed, %d skipped.\n", out.Succeeded, out.Failed, out.Skipped)
+	}
+	return err
+}
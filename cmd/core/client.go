@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gopkg.in/yaml.v3"
+)
+
+// flags of admin subcommands.
+var (
+	_serverAddr string
+	_output     string
+	_owner      string
+	_source     string
+	_timeout    time.Duration
+)
+
+var errInputRequired = errors.New("inline value or --file required")
+
+// adminCommands returns subcommands which talk to a running core over gRPC.
+func adminCommands() []*cobra.Command {
+	commands := []*cobra.Command{
+		newEntityCmd(),
+		newMapperCmd(),
+		newSubscriptionCmd(),
+		newConfigCmd(),
+		newActorsCmd(),
+		newAuditCmd(),
+		newPurgeCmd(),
+		newRelationshipCmd(),
+		newExportCmd(),
+		newImportCmd(),
+	}
+
+	for _, cmd := range commands {
+		cmd.PersistentFlags().StringVar(&_serverAddr, "server", "localhost:31233", "grpc address of core.")
+		cmd.PersistentFlags().StringVarP(&_output, "output", "o", print.OutputTable, "output format, one of json|yaml|table.")
+		cmd.PersistentFlags().DurationVar(&_timeout, "timeout", 10*time.Second, "timeout of request.")
+	}
+	return commands
+}
+
+// addOwnerFlags adds flags identifying the owner and source of resources.
+func addOwnerFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&_owner, "owner", "", "owner of resources.")
+	cmd.PersistentFlags().StringVar(&_source, "source", "", "source of resources.")
+}
+
+// withConn dials core, fn is called with the connection and a request context.
+func withConn(fn func(ctx context.Context, conn *grpc.ClientConn, args []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), _timeout)
+		defer cancel()
+
+		conn, err := grpc.DialContext(ctx, _serverAddr, grpc.WithInsecure(), grpc.WithBlock(),
+			grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+			grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()))
+		if nil != err {
+			return errors.Wrapf(err, "dial core %s", _serverAddr)
+		}
+		defer conn.Close()
+
+		return fn(ctx, conn, args)
+	}
+}
+
+// readValue parses inline value or content of file, both json and yaml are accepted.
+func readValue(inline, filename string) (*structpb.Value, error) {
+	var err error
+	var data = []byte(inline)
+	if filename != "" {
+		if data, err = ioutil.ReadFile(filename); nil != err {
+			return nil, errors.Wrap(err, "read file")
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, errInputRequired
+	}
+
+	var v interface{}
+	if err = yaml.Unmarshal(data, &v); nil != err {
+		return nil, errors.Wrap(err, "parse value")
+	}
+
+	val, err := structpb.NewValue(v)
+	return val, errors.Wrap(err, "parse value")
+}
+
+// compact returns compact json of value for table output.
+func compact(val *structpb.Value) string {
+	if nil == val {
+		return ""
+	}
+	bytes, _ := json.Marshal(val.AsInterface())
+	return string(bytes)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"sort"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Export and import property configs of entities",
+	}
+	addOwnerFlags(cmd)
+
+	var (
+		entityType string
+		filename   string
+	)
+	exportCmd := &cobra.Command{
+		Use:   "export <entity-id>",
+		Short: "Export property configs of an entity",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).GetEntity(ctx, &corev1.GetEntityRequest{
+				Id:     args[0],
+				Type:   entityType,
+				Owner:  _owner,
+				Source: _source,
+			})
+			if nil != err {
+				return errors.Wrap(err, "export configs")
+			}
+
+			var w io.Writer = os.Stdout
+			if filename != "" {
+				f, err := os.Create(filename)
+				if nil != err {
+					return errors.Wrap(err, "export configs")
+				}
+				defer f.Close()
+				w = f
+			}
+			return printConfigs(w, out.Configs)
+		}),
+	}
+	exportCmd.Flags().StringVar(&entityType, "type", "", "entity type.")
+	exportCmd.Flags().StringVarP(&filename, "file", "f", "", "write configs into file instead of stdout.")
+
+	importCmd := &cobra.Command{
+		Use:   "import <entity-id>",
+		Short: "Import property configs into an entity, existing configs are replaced",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			configs, err := readValue("", filename)
+			if nil != err {
+				return errors.Wrap(err, "import configs")
+			}
+
+			out, err := corev1.NewEntityClient(conn).SetConfigs(ctx, &corev1.SetConfigsRequest{
+				Id:      args[0],
+				Type:    entityType,
+				Owner:   _owner,
+				Source:  _source,
+				Configs: configs,
+			})
+			if nil != err {
+				return errors.Wrap(err, "import configs")
+			}
+			return printConfigs(os.Stdout, out.Configs)
+		}),
+	}
+	importCmd.Flags().StringVar(&entityType, "type", "", "entity type.")
+	importCmd.Flags().StringVarP(&filename, "file", "f", "", "file of configs, json or yaml.")
+	_ = importCmd.MarkFlagRequired("file")
+
+	cmd.AddCommand(exportCmd, importCmd)
+	return cmd
+}
+
+// printConfigs prints configs in output format, properties are rows of table.
+func printConfigs(w io.Writer, configs *structpb.Value) error {
+	return print.Output(w, _output, configs, func() print.Table {
+		fields := configs.GetStructValue().GetFields()
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		table := print.Table{Header: []string{"property", "config"}}
+		for _, key := range keys {
+			table.Rows = append(table.Rows, []string{key, compact(fields[key])})
+		}
+		return table
+	})
+}
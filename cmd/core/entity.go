@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func newEntityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "entity",
+		Short: "Manage entities of a running core",
+	}
+	addOwnerFlags(cmd)
+
+	var entityType string
+	getCmd := &cobra.Command{
+		Use:   "get <entity-id>",
+		Short: "Get an entity",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).GetEntity(ctx, &corev1.GetEntityRequest{
+				Id:     args[0],
+				Type:   entityType,
+				Owner:  _owner,
+				Source: _source,
+			})
+			if nil != err {
+				return errors.Wrap(err, "get entity")
+			}
+			return printEntities(out, out)
+		}),
+	}
+	getCmd.Flags().StringVar(&entityType, "type", "", "entity type.")
+
+	var (
+		query    string
+		pageNum  int32
+		pageSize int32
+	)
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List entities",
+		Args:  cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).ListEntity(ctx, &corev1.ListEntityRequest{
+				Owner:    _owner,
+				Source:   _source,
+				Query:    query,
+				PageNum:  pageNum,
+				PageSize: pageSize,
+			})
+			if nil != err {
+				return errors.Wrap(err, "list entities")
+			}
+			return printEntities(out, out.Items...)
+		}),
+	}
+	listCmd.Flags().StringVarP(&query, "query", "q", "", "search keyword.")
+	listCmd.Flags().Int32Var(&pageNum, "page-num", 1, "page number.")
+	listCmd.Flags().Int32Var(&pageSize, "page-size", 20, "page size.")
+
+	var (
+		from       string
+		properties string
+		filename   string
+	)
+	createCmd := &cobra.Command{
+		Use:   "create [entity-id]",
+		Short: "Create an entity, id is generated if absent",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			req := &corev1.CreateEntityRequest{
+				From:   from,
+				Type:   entityType,
+				Owner:  _owner,
+				Source: _source,
+			}
+			if len(args) > 0 {
+				req.Id = args[0]
+			}
+			if properties != "" || filename != "" {
+				var err error
+				if req.Properties, err = readValue(properties, filename); nil != err {
+					return errors.Wrap(err, "create entity")
+				}
+			}
+
+			out, err := corev1.NewEntityClient(conn).CreateEntity(ctx, req)
+			if nil != err {
+				return errors.Wrap(err, "create entity")
+			}
+			return printEntities(out, out)
+		}),
+	}
+	createCmd.Flags().StringVar(&entityType, "type", "", "entity type.")
+	createCmd.Flags().StringVar(&from, "from", "", "template entity id.")
+	createCmd.Flags().StringVarP(&properties, "properties", "p", "", "entity properties, json or yaml object.")
+	createCmd.Flags().StringVarP(&filename, "file", "f", "", "file of entity properties.")
+
+	patchCmd := &cobra.Command{
+		Use:     "patch <entity-id>",
+		Short:   "Patch properties of an entity",
+		Example: `core entity patch device1 --owner admin -p '[{"path":"temp","operator":"replace","value":25}]'`,
+		Args:    cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			patches, err := readValue(properties, filename)
+			if nil != err {
+				return errors.Wrap(err, "patch entity")
+			}
+
+			out, err := corev1.NewEntityClient(conn).PatchEntity(ctx, &corev1.PatchEntityRequest{
+				Id:         args[0],
+				Type:       entityType,
+				Owner:      _owner,
+				Source:     _source,
+				Properties: patches,
+			})
+			if nil != err {
+				return errors.Wrap(err, "patch entity")
+			}
+			return printEntities(out, out)
+		}),
+	}
+	patchCmd.Flags().StringVar(&entityType, "type", "", "entity type.")
+	patchCmd.Flags().StringVarP(&properties, "properties", "p", "", "patch operations, json or yaml array.")
+	patchCmd.Flags().StringVarP(&filename, "file", "f", "", "file of patch operations.")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <entity-id>",
+		Short: "Delete an entity, restorable until purged",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).DeleteEntity(ctx, &corev1.DeleteEntityRequest{
+				Id:     args[0],
+				Type:   entityType,
+				Owner:  _owner,
+				Source: _source,
+			})
+			if nil != err {
+				return errors.Wrap(err, "delete entity")
+			}
+			return print.Output(os.Stdout, _output, out, func() print.Table {
+				return print.Table{
+					Header: []string{"id", "status", "purge time"},
+					Rows:   [][]string{{out.Id, out.Status, formatMilli(out.PurgeTime)}},
+				}
+			})
+		}),
+	}
+	deleteCmd.Flags().StringVar(&entityType, "type", "", "entity type.")
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <entity-id>",
+		Short: "Restore a deleted entity before it is purged",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).RestoreEntity(ctx, &corev1.RestoreEntityRequest{
+				Id:     args[0],
+				Type:   entityType,
+				Owner:  _owner,
+				Source: _source,
+			})
+			if nil != err {
+				return errors.Wrap(err, "restore entity")
+			}
+			return printEntities(out, out)
+		}),
+	}
+	restoreCmd.Flags().StringVar(&entityType, "type", "", "entity type.")
+
+	cmd.AddCommand(getCmd, listCmd, createCmd, patchCmd, deleteCmd, restoreCmd, newEntityBatchCmd())
+	return cmd
+}
+
+// printEntities prints v in output format, entities are rows of table.
+func printEntities(v interface{}, entities ...*corev1.EntityResponse) error {
+	return print.Output(os.Stdout, _output, v, func() print.Table {
+		table := print.Table{Header: []string{"id", "type", "owner", "source", "properties"}}
+		for _, en := range entities {
+			table.Rows = append(table.Rows, []string{en.Id, en.Type, en.Owner, en.Source, compact(en.Properties)})
+		}
+		return table
+	})
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/mapper"
+	"github.com/tkeel-io/core/pkg/print"
+	"github.com/tkeel-io/core/pkg/tql"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var errMapperTargetMismatched = errors.New("mapper target mismatched")
+
+func newMapperCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mapper",
+		Short: "Manage mappers of entities",
+	}
+	addOwnerFlags(cmd)
+
+	var (
+		entityType string
+		name       string
+		tqlText    string
+	)
+	addCmd := &cobra.Command{
+		Use:     "add <entity-id>",
+		Short:   "Add a mapper into an entity",
+		Example: `core mapper add device1 --owner admin --name temp --tql "insert into device1 select device2.temp as temp"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).AppendMapper(ctx, &corev1.AppendMapperRequest{
+				Id:     args[0],
+				Type:   entityType,
+				Owner:  _owner,
+				Source: _source,
+				Mapper: &corev1.MapperDesc{Name: name, Tql: tqlText},
+			})
+			if nil != err {
+				return errors.Wrap(err, "add mapper")
+			}
+			return printMappers(out)
+		}),
+	}
+	addCmd.Flags().StringVar(&entityType, "type", "", "entity type.")
+	addCmd.Flags().StringVar(&name, "name", "", "mapper name.")
+	addCmd.Flags().StringVar(&tqlText, "tql", "", "TQL of mapper.")
+	_ = addCmd.MarkFlagRequired("name")
+	_ = addCmd.MarkFlagRequired("tql")
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <entity-id>",
+		Short: "Remove a mapper from an entity",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).RemoveMapper(ctx, &corev1.RemoveMapperRequest{
+				Id:         args[0],
+				Type:       entityType,
+				Owner:      _owner,
+				Source:     _source,
+				MapperName: name,
+			})
+			if nil != err {
+				return errors.Wrap(err, "remove mapper")
+			}
+			return printMappers(out)
+		}),
+	}
+	removeCmd.Flags().StringVar(&entityType, "type", "", "entity type.")
+	removeCmd.Flags().StringVar(&name, "name", "", "mapper name.")
+	_ = removeCmd.MarkFlagRequired("name")
+
+	validateCmd := &cobra.Command{
+		Use:   "validate [entity-id]",
+		Short: "Validate TQL of a mapper locally, the target is checked if entity id given",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := tql.Validate(tqlText); nil != err {
+				return errors.Wrap(err, "validate mapper")
+			}
+
+			m, err := mapper.NewMapper(name, tqlText)
+			if nil != err {
+				return errors.Wrap(err, "validate mapper")
+			} else if len(args) > 0 && m.TargetEntity() != args[0] {
+				return errors.Wrapf(errMapperTargetMismatched, "target %s, entity %s", m.TargetEntity(), args[0])
+			}
+
+			var watchKeys []string
+			watched := make(map[string]struct{})
+			for _, tentacle := range m.Tentacles() {
+				for _, item := range tentacle.Items() {
+					if _, has := watched[item.String()]; !has {
+						watched[item.String()] = struct{}{}
+						watchKeys = append(watchKeys, item.String())
+					}
+				}
+			}
+
+			result := map[string]interface{}{
+				"target":  m.TargetEntity(),
+				"sources": m.SourceEntities(),
+				"watch":   watchKeys,
+			}
+			return print.Output(os.Stdout, _output, result, func() print.Table {
+				return print.Table{
+					Header: []string{"target", "sources", "watch"},
+					Rows: [][]string{{m.TargetEntity(),
+						strings.Join(m.SourceEntities(), ","), strings.Join(watchKeys, ",")}},
+				}
+			})
+		},
+	}
+	validateCmd.Flags().StringVar(&name, "name", "", "mapper name.")
+	validateCmd.Flags().StringVar(&tqlText, "tql", "", "TQL of mapper.")
+	_ = validateCmd.MarkFlagRequired("tql")
+
+	cmd.AddCommand(addCmd, removeCmd, validateCmd)
+	return cmd
+}
+
+// printMappers prints entity in output format, mappers are rows of table.
+func printMappers(en *corev1.EntityResponse) error {
+	return print.Output(os.Stdout, _output, en, func() print.Table {
+		table := print.Table{Header: []string{"entity", "name", "tql"}}
+		for _, m := range en.Mappers {
+			table.Rows = append(table.Rows, []string{en.Id, m.Name, m.Tql})
+		}
+		return table
+	})
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func newPurgeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Purge deleted entities permanently",
+	}
+
+	deletedCmd := &cobra.Command{
+		Use:   "deleted",
+		Short: "List deleted entities waiting to be purged",
+		Args:  cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewAdminClient(conn).ListDeletedEntities(ctx, &corev1.ListDeletedEntitiesRequest{})
+			if nil != err {
+				return errors.Wrap(err, "list deleted entities")
+			}
+			return print.Output(os.Stdout, _output, out, func() print.Table {
+				table := print.Table{Header: []string{"id", "type", "owner", "delete time", "purge time"}}
+				for _, en := range out.Items {
+					table.Rows = append(table.Rows, []string{en.Id, en.Type, en.Owner, formatMilli(en.DeleteTime), formatMilli(en.PurgeTime)})
+				}
+				return table
+			})
+		}),
+	}
+
+	var expired bool
+	runCmd := &cobra.Command{
+		Use:     "run [entity-id...]",
+		Short:   "Start a job purging deleted entities",
+		Example: "core purge run device1 device2\ncore purge run --expired",
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewAdminClient(conn).PurgeEntities(ctx, &corev1.PurgeEntitiesRequest{Ids: args, Expired: expired})
+			if nil != err {
+				return errors.Wrap(err, "purge entities")
+			}
+			return printPurgeJobs(out, out.Job)
+		}),
+	}
+	runCmd.Flags().BoolVar(&expired, "expired", false, "purge entities whose retention passed.")
+
+	jobsCmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "List recent purge jobs",
+		Args:  cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewAdminClient(conn).ListPurgeJobs(ctx, &corev1.ListPurgeJobsRequest{})
+			if nil != err {
+				return errors.Wrap(err, "list purge jobs")
+			}
+			return printPurgeJobs(out, out.Items...)
+		}),
+	}
+
+	jobCmd := &cobra.Command{
+		Use:   "job <job-id>",
+		Short: "Get report of a purge job",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewAdminClient(conn).GetPurgeJob(ctx, &corev1.GetPurgeJobRequest{Id: args[0]})
+			if nil != err {
+				return errors.Wrap(err, "get purge job")
+			}
+			return print.Output(os.Stdout, _output, out, func() print.Table {
+				table := print.Table{Header: []string{"entity", "state", "search", "mappers", "subscriptions", "dangling mappers", "error"}}
+				for _, report := range out.Job.Reports {
+					table.Rows = append(table.Rows, []string{report.EntityId, fmt.Sprint(report.State), fmt.Sprint(report.SearchDocument),
+						strings.Join(report.Mappers, ","), strings.Join(report.Subscriptions, ","),
+						strings.Join(report.DanglingMappers, ","), report.Error})
+				}
+				return table
+			})
+		}),
+	}
+
+	cmd.AddCommand(deletedCmd, runCmd, jobsCmd, jobCmd)
+	return cmd
+}
+
+// printPurgeJobs prints v in output format, jobs are rows of table.
+func printPurgeJobs(v interface{}, jobs ...*corev1.PurgeJob) error {
+	return print.Output(os.Stdout, _output, v, func() print.Table {
+		table := print.Table{Header: []string{"id", "status", "trigger", "entities", "start time", "end time"}}
+		for _, job := range jobs {
+			table.Rows = append(table.Rows, []string{job.Id, job.Status, job.Trigger,
+				fmt.Sprint(len(job.Reports)), formatMilli(job.StartTime), formatMilli(job.EndTime)})
+		}
+		return table
+	})
+}
+
+// formatMilli formats unix milliseconds, "-" if unset.
+func formatMilli(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)).Format(time.RFC3339)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func newRelationshipCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "relationship",
+		Aliases: []string{"rel"},
+		Short:   "Manage relationships between entities",
+	}
+	addOwnerFlags(cmd)
+
+	var (
+		entityType string
+		relType    string
+		properties string
+		direction  string
+		maxDepth   int32
+	)
+	addCmd := &cobra.Command{
+		Use:     "add <entity-id> <target-id>",
+		Short:   "Add a relationship from an entity to another",
+		Example: `core relationship add room1 device1 --owner admin --type contains -p '{"slot": 1}'`,
+		Args:    cobra.ExactArgs(2),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			rel := &corev1.Relationship{Type: relType, Target: args[1]}
+			if properties != "" {
+				var err error
+				if rel.Properties, err = readValue(properties, ""); nil != err {
+					return err
+				}
+			}
+
+			out, err := corev1.NewEntityClient(conn).AddRelationship(ctx, &corev1.AddRelationshipRequest{
+				Id:           args[0],
+				Type:         entityType,
+				Owner:        _owner,
+				Source:       _source,
+				Relationship: rel,
+			})
+			if nil != err {
+				return errors.Wrap(err, "add relationship")
+			}
+			return printRelationships(out, out.Relationships)
+		}),
+	}
+	addCmd.Flags().StringVar(&relType, "type", "", "relationship type, e.g. contains, connected-to, located-in.")
+	addCmd.Flags().StringVarP(&properties, "properties", "p", "", "relationship properties, json or yaml object.")
+	_ = addCmd.MarkFlagRequired("type")
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <entity-id> <target-id>",
+		Short: "Remove a relationship from an entity",
+		Args:  cobra.ExactArgs(2),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).RemoveRelationship(ctx, &corev1.RemoveRelationshipRequest{
+				Id:               args[0],
+				Type:             entityType,
+				Owner:            _owner,
+				Source:           _source,
+				RelationshipType: relType,
+				Target:           args[1],
+			})
+			if nil != err {
+				return errors.Wrap(err, "remove relationship")
+			}
+			return printRelationships(out, out.Relationships)
+		}),
+	}
+	removeCmd.Flags().StringVar(&relType, "type", "", "relationship type.")
+	_ = removeCmd.MarkFlagRequired("type")
+
+	listCmd := &cobra.Command{
+		Use:   "list <entity-id>",
+		Short: "List relationships of an entity",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).ListRelationships(ctx, &corev1.ListRelationshipsRequest{
+				Id:               args[0],
+				Type:             entityType,
+				Owner:            _owner,
+				Source:           _source,
+				RelationshipType: relType,
+				Direction:        direction,
+			})
+			if nil != err {
+				return errors.Wrap(err, "list relationships")
+			}
+			return printRelationships(out, out.Items)
+		}),
+	}
+	listCmd.Flags().StringVar(&relType, "type", "", "relationship type, all types if empty.")
+	listCmd.Flags().StringVar(&direction, "direction", "out", "direction of relationships, one of out|in|both.")
+
+	traverseCmd := &cobra.Command{
+		Use:     "traverse <entity-id>",
+		Short:   "List entities reachable from an entity by relationships",
+		Example: "core relationship traverse building1 --owner admin --type contains",
+		Args:    cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).TraverseRelationships(ctx, &corev1.TraverseRelationshipsRequest{
+				Id:               args[0],
+				Type:             entityType,
+				Owner:            _owner,
+				Source:           _source,
+				RelationshipType: relType,
+				Direction:        direction,
+				MaxDepth:         maxDepth,
+			})
+			if nil != err {
+				return errors.Wrap(err, "traverse relationships")
+			}
+			return print.Output(os.Stdout, _output, out, func() print.Table {
+				table := print.Table{Header: []string{"id", "depth", "type", "source", "target"}}
+				for _, item := range out.Items {
+					rel := item.Relationship
+					table.Rows = append(table.Rows, []string{item.Id, fmt.Sprint(item.Depth), rel.GetType(), rel.GetSource(), rel.GetTarget()})
+				}
+				return table
+			})
+		}),
+	}
+	traverseCmd.Flags().StringVar(&relType, "type", "", "relationship type, all types if empty.")
+	traverseCmd.Flags().StringVar(&direction, "direction", "out", "direction of relationships, one of out|in|both.")
+	traverseCmd.Flags().Int32Var(&maxDepth, "max-depth", 0, "max depth of traversal, 10 if zero.")
+
+	pathCmd := &cobra.Command{
+		Use:     "path <entity-id> <target-id>",
+		Short:   "Find the shortest path of relationships between two entities",
+		Example: "core relationship path switch1 switch2 --owner admin --type connected-to --direction both",
+		Args:    cobra.ExactArgs(2),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewEntityClient(conn).FindRelationshipPath(ctx, &corev1.FindRelationshipPathRequest{
+				Id:               args[0],
+				Type:             entityType,
+				Owner:            _owner,
+				Source:           _source,
+				Target:           args[1],
+				RelationshipType: relType,
+				Direction:        direction,
+				MaxDepth:         maxDepth,
+			})
+			if nil != err {
+				return errors.Wrap(err, "find relationship path")
+			}
+			return printRelationships(out, out.Path)
+		}),
+	}
+	pathCmd.Flags().StringVar(&relType, "type", "", "relationship type, all types if empty.")
+	pathCmd.Flags().StringVar(&direction, "direction", "out", "direction of relationships, one of out|in|both.")
+	pathCmd.Flags().Int32Var(&maxDepth, "max-depth", 0, "max length of path, 10 if zero.")
+
+	for _, c := range []*cobra.Command{addCmd, removeCmd, listCmd, traverseCmd, pathCmd} {
+		c.Flags().StringVar(&entityType, "entity-type", "", "entity type.")
+	}
+
+	cmd.AddCommand(addCmd, removeCmd, listCmd, traverseCmd, pathCmd)
+	return cmd
+}
+
+// printRelationships prints v in output format, relationships are rows of table.
+func printRelationships(v interface{}, rels []*corev1.Relationship) error {
+	return print.Output(os.Stdout, _output, v, func() print.Table {
+		table := print.Table{Header: []string{"type", "source", "target", "properties"}}
+		for _, rel := range rels {
+			table.Rows = append(table.Rows, []string{rel.Type, rel.Source, rel.Target, compact(rel.Properties)})
+		}
+		return table
+	})
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/resource/search"
+	"github.com/tkeel-io/core/pkg/util"
+
+	"github.com/tkeel-io/kit/log"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// watchConfig applies changes of config file and etcd config key at runtime.
+func watchConfig(ctx context.Context, etcdClient *clientv3.Client) {
+	config.OnChange(func(prev, next config.Configuration) {
+		if prev.Logger.Level != next.Logger.Level {
+			if err := log.InitLogger(next.Server.AppID, next.Logger.Level, next.Logger.Dev, next.Logger.Output...); nil != err {
+				log.Error("reload logger", zap.Error(err))
+			}
+		}
+		if !reflect.DeepEqual(prev.SearchEngine, next.SearchEngine) {
+			if err := search.GlobalService.Reload(next.SearchEngine); nil != err {
+				log.Error("reload search engine", zap.Error(err))
+			} else {
+				log.Info("search engine reloaded", zap.String("use", next.SearchEngine.Use))
+			}
+		}
+		if prev.Ingest != next.Ingest {
+			_ingestService.Reload(next.Ingest.Window, next.Ingest.MaxBatchSize)
+		}
+		if !reflect.DeepEqual(prev.TimeSeries, next.TimeSeries) {
+			if err := _stateManager.ReloadTimeSeries(next.TimeSeries); nil != err {
+				log.Error("reload time-series client", zap.Error(err))
+			}
+		}
+	})
+
+	if _configKey == "" {
+		return
+	}
+
+	reload := func(content []byte) {
+		if err := config.ReloadFrom(content); nil != err {
+			log.Error("reload config", zap.String("key", _configKey), zap.Error(err))
+			return
+		}
+		log.Info("config reloaded", zap.String("key", _configKey))
+	}
+
+	res, err := etcdClient.Get(ctx, _configKey)
+	if nil != err {
+		log.Error("get config key", zap.String("key", _configKey), zap.Error(err))
+	} else if len(res.Kvs) > 0 {
+		reload(res.Kvs[0].Value)
+	}
+
+	watcher, _ := util.NewWatcherWithClient(ctx, etcdClient)
+	watcher.Watch(_configKey, false, func(ev *clientv3.Event) {
+		if ev.Type == clientv3.EventTypePut {
+			reload(ev.Kv.Value)
+		}
+	})
+}
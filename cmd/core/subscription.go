@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func newSubscriptionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subscription",
+		Short: "Manage subscriptions",
+	}
+	addOwnerFlags(cmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List subscriptions",
+		Args:  cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			out, err := corev1.NewSubscriptionClient(conn).ListSubscription(ctx, &corev1.ListSubscriptionRequest{
+				Owner:  _owner,
+				Source: _source,
+			})
+			if nil != err {
+				return errors.Wrap(err, "list subscriptions")
+			}
+			return printSubscriptions(out, out.Items...)
+		}),
+	}
+
+	var subscription corev1.SubscriptionObject
+	createCmd := &cobra.Command{
+		Use:   "create <subscription-id>",
+		Short: "Create a subscription",
+		Args:  cobra.ExactArgs(1),
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			subscription.Source = _source
+			out, err := corev1.NewSubscriptionClient(conn).CreateSubscription(ctx, &corev1.CreateSubscriptionRequest{
+				Id:           args[0],
+				Owner:        _owner,
+				Source:       _source,
+				Subscription: &subscription,
+			})
+			if nil != err {
+				return errors.Wrap(err, "create subscription")
+			}
+			return printSubscriptions(out, out)
+		}),
+	}
+	createCmd.Flags().StringVar(&subscription.Mode, "mode", "", "subscription mode.")
+	createCmd.Flags().StringVar(&subscription.Filter, "filter", "", "subscription filter.")
+	createCmd.Flags().StringVar(&subscription.Target, "target", "", "target id.")
+	createCmd.Flags().StringVar(&subscription.Topic, "topic", "", "topic name.")
+	createCmd.Flags().StringVar(&subscription.PubsubName, "pubsub", "", "pubsub name.")
+
+	cmd.AddCommand(listCmd, createCmd)
+	return cmd
+}
+
+// printSubscriptions prints v in output format, subscriptions are rows of table.
+func printSubscriptions(v interface{}, subscriptions ...*corev1.SubscriptionResponse) error {
+	return print.Output(os.Stdout, _output, v, func() print.Table {
+		table := print.Table{Header: []string{"id", "owner", "mode", "filter", "target", "topic", "pubsub"}}
+		for _, s := range subscriptions {
+			sub := s.GetSubscription()
+			table.Rows = append(table.Rows, []string{s.Id, s.Owner,
+				sub.GetMode(), sub.GetFilter(), sub.GetTarget(), sub.GetTopic(), sub.GetPubsubName()})
+		}
+		return table
+	})
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/print"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+// transferChunkSize is max size of data sent in a chunk.
+const transferChunkSize = 64 << 10
+
+func newExportCmd() *cobra.Command {
+	var (
+		format     string
+		entityType string
+		filename   string
+	)
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export entities with configs, properties, mappers and relationships",
+		Example: `core export --owner admin --format tar -f backup.tar`,
+		Args:    cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			stream, err := corev1.NewTransferClient(conn).Export(ctx, &corev1.ExportRequest{
+				Format: format,
+				Owner:  _owner,
+				Type:   entityType,
+			})
+			if nil != err {
+				return errors.Wrap(err, "export entities")
+			}
+
+			var w io.Writer = os.Stdout
+			if filename != "" {
+				f, err := os.Create(filename)
+				if nil != err {
+					return errors.Wrap(err, "export entities")
+				}
+				defer f.Close()
+				w = f
+			}
+
+			for {
+				chunk, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					return nil
+				} else if nil != err {
+					return errors.Wrap(err, "export entities")
+				} else if _, err = w.Write(chunk.Data); nil != err {
+					return errors.Wrap(err, "export entities")
+				}
+			}
+		}),
+	}
+	addOwnerFlags(cmd)
+	cmd.Flags().StringVar(&format, "format", "ndjson", "format of exported entities, one of ndjson|tar.")
+	cmd.Flags().StringVar(&entityType, "type", "", "export entities of the type only.")
+	cmd.Flags().StringVarP(&filename, "file", "f", "", "write entities into file instead of stdout.")
+	return cmd
+}
+
+func newImportCmd() *cobra.Command {
+	var (
+		mode     string
+		dryRun   bool
+		filename string
+	)
+	cmd := &cobra.Command{
+		Use:     "import",
+		Short:   "Import entities exported before, ndjson or tar archive",
+		Example: `core import --owner admin --mode upsert --dry-run -f backup.tar`,
+		Args:    cobra.NoArgs,
+		RunE: withConn(func(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+			f, err := os.Open(filename)
+			if nil != err {
+				return errors.Wrap(err, "import entities")
+			}
+			defer f.Close()
+
+			stream, err := corev1.NewTransferClient(conn).Import(ctx)
+			if nil != err {
+				return errors.Wrap(err, "import entities")
+			}
+
+			// mode, dry run and owner are sent with the first chunk.
+			chunk := &corev1.ImportChunk{Mode: mode, DryRun: dryRun, Owner: _owner}
+			buf := make([]byte, transferChunkSize)
+			for {
+				n, err := f.Read(buf)
+				if n > 0 {
+					chunk.Data = buf[:n]
+					if err = stream.Send(chunk); nil != err {
+						return errors.Wrap(err, "import entities")
+					}
+					chunk = &corev1.ImportChunk{}
+				}
+				if errors.Is(err, io.EOF) {
+					break
+				} else if nil != err {
+					return errors.Wrap(err, "import entities")
+				}
+			}
+
+			out, err := stream.CloseAndRecv()
+			if nil != err {
+				return errors.Wrap(err, "import entities")
+			}
+			return printImport(out)
+		}),
+	}
+	addOwnerFlags(cmd)
+	cmd.Flags().StringVar(&mode, "mode", "create", "import mode, create skips existing entities, upsert updates them.")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report what would be imported without writing.")
+	cmd.Flags().StringVarP(&filename, "file", "f", "", "file of exported entities.")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// printImport prints result of every entity and a summary.
+func printImport(out *corev1.ImportResponse) error {
+	err := print.Output(os.Stdout, _output, out, func() print.Table {
+		table := print.Table{Header: []string{"id", "status", "error"}}
+		for _, result := range out.Results {
+			table.Rows = append(table.Rows, []string{result.Id, result.Status, result.Error})
+		}
+		return table
+	})
+	if nil == err && _output == print.OutputTable {
+		prefix := ""
+		if out.DryRun {
+			prefix = "dry run, "
+		}
+		fmt.Printf("\n%s%d created, %d updated, %d skipped, %d failed.\n", prefix, out.Created, out.Updated, out.Skipped, out.Failed)
+	}
+	return err
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alarm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/tql"
+)
+
+/*
+   规则：实体属性与阈值或另一实体属性的比较，在持续 duration 后产生告警.
+
+   device1.temp > 80
+   device1.temp >= device1.max_temp
+
+   condition := source op (number | source)
+   op        := '>' | '>=' | '<' | '<=' | '==' | '!='
+*/
+
+var (
+	conditionRegexp = regexp.MustCompile(`^\s*(\S+?)\s*(>=|<=|==|!=|>|<)\s*(\S+)\s*$`)
+	sourceRegexp    = regexp.MustCompile(`^[0-9a-zA-Z_][a-zA-Z_\-0-9]*(\.[0-9a-zA-Z_][a-zA-Z_\-0-9]*)+$`)
+)
+
+// Condition compares source property with threshold or threshold property.
+type Condition struct {
+	Source   string
+	Operator string
+	// Threshold is ignored if ThresholdSource set.
+	Threshold       float64
+	ThresholdSource string
+}
+
+// ParseCondition parses condition text.
+func ParseCondition(text string) (Condition, error) {
+	var cond Condition
+	matches := conditionRegexp.FindStringSubmatch(text)
+	if len(matches) != 4 {
+		return cond, errors.Wrapf(ErrConditionInvalid, "condition %q", text)
+	} else if !sourceRegexp.MatchString(matches[1]) {
+		return cond, errors.Wrapf(ErrConditionInvalid, "source %q", matches[1])
+	}
+
+	cond.Source, cond.Operator = matches[1], matches[2]
+	if threshold, err := strconv.ParseFloat(matches[3], 64); nil == err {
+		cond.Threshold = threshold
+	} else if sourceRegexp.MatchString(matches[3]) {
+		cond.ThresholdSource = matches[3]
+	} else {
+		return cond, errors.Wrapf(ErrConditionInvalid, "threshold %q", matches[3])
+	}
+
+	if err := tql.Validate(cond.TQL("alarm")); nil != err {
+		return cond, errors.Wrap(ErrConditionInvalid, err.Error())
+	}
+	return cond, nil
+}
+
+// String returns condition text.
+func (c Condition) String() string {
+	threshold := c.ThresholdSource
+	if threshold == "" {
+		threshold = strconv.FormatFloat(c.Threshold, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%s %s %s", c.Source, c.Operator, threshold)
+}
+
+// TQL returns TQL of mapper watching sources of condition.
+func (c Condition) TQL(target string) string {
+	text := fmt.Sprintf("insert into %s select %s as %s", target, c.Source, FieldValue)
+	if c.ThresholdSource != "" {
+		text += fmt.Sprintf(", %s as threshold", c.ThresholdSource)
+	}
+	return text
+}
+
+// Eval returns value of source and whether condition holds, hysteresis
+// keeps condition holding until value moves back past threshold by hysteresis.
+// ok is false if values of sources are not numeric or not known.
+func (c Condition) Eval(values map[string]constraint.Node, active bool, hysteresis float64) (value float64, holds, ok bool) {
+	threshold := c.Threshold
+	if value, ok = numeric(values[c.Source]); !ok {
+		return value, false, false
+	} else if c.ThresholdSource != "" {
+		if threshold, ok = numeric(values[c.ThresholdSource]); !ok {
+			return value, false, false
+		}
+	}
+
+	if !active {
+		hysteresis = 0
+	}
+
+	switch c.Operator {
+	case OperatorGT:
+		holds = value > threshold-hysteresis
+	case OperatorGTE:
+		holds = value >= threshold-hysteresis
+	case OperatorLT:
+		holds = value < threshold+hysteresis
+	case OperatorLTE:
+		holds = value <= threshold+hysteresis
+	case OperatorEQ:
+		holds = value == threshold
+	case OperatorNE:
+		holds = value != threshold
+	}
+	return value, holds, true
+}
+
+func numeric(node constraint.Node) (float64, bool) {
+	if nil == node {
+		return 0, false
+	}
+
+	switch val := node.Value().(type) {
+	case int64:
+		return float64(val), true
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return f, nil == err
+	}
+	return 0, false
+}
+
+// Rule is alarm rule of an alarm entity.
+type Rule struct {
+	Condition   Condition
+	Severity    string
+	Hysteresis  float64
+	Duration    time.Duration
+	Description string
+}
+
+// NewRule returns a rule, severity is warning if empty.
+func NewRule(condition, severity string, hysteresis float64, duration string) (*Rule, error) {
+	cond, err := ParseCondition(condition)
+	if nil != err {
+		return nil, err
+	}
+
+	rule := &Rule{Condition: cond, Severity: severity, Hysteresis: hysteresis}
+	switch severity {
+	case "":
+		rule.Severity = SeverityWarning
+	case SeverityCritical, SeverityMajor, SeverityMinor, SeverityWarning:
+	default:
+		return nil, errors.Wrapf(ErrSeverityInvalid, "severity %q", severity)
+	}
+
+	if hysteresis < 0 {
+		return nil, errors.Wrapf(ErrHysteresisInvalid, "hysteresis %v", hysteresis)
+	} else if duration != "" {
+		if rule.Duration, err = time.ParseDuration(duration); nil != err || rule.Duration < 0 {
+			return nil, errors.Wrapf(ErrDurationInvalid, "duration %q", duration)
+		}
+	}
+	return rule, nil
+}
+
+// Evaluate transitions state by values of sources at now in milliseconds,
+// returns true if status changed.
+func (r *Rule) Evaluate(state *State, values map[string]constraint.Node, now int64) bool {
+	value, holds, ok := r.Condition.Eval(values, state.Active(), r.Hysteresis)
+	if !ok {
+		return false
+	}
+
+	state.Value = value
+	if !holds {
+		state.PendingSince = 0
+		if state.Active() {
+			state.Status = StatusCleared
+			state.ClearedAt = now
+			return true
+		}
+		return false
+	} else if state.Active() {
+		return false
+	}
+
+	if state.PendingSince == 0 {
+		state.PendingSince = now
+	}
+
+	if now-state.PendingSince < r.Duration.Milliseconds() {
+		return false
+	}
+
+	state.Status = StatusActive
+	state.RaisedAt = now
+	state.PendingSince = 0
+	state.AcknowledgedAt, state.AcknowledgedBy = 0, ""
+	state.ClearedAt = 0
+	state.RaiseCount++
+	return true
+}
+
+// Due returns true if condition has held for duration but alarm not raised.
+func (r *Rule) Due(state *State, now int64) bool {
+	return !state.Active() && state.PendingSince > 0 &&
+		now-state.PendingSince >= r.Duration.Milliseconds()
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alarm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+func TestParseCondition(t *testing.T) {
+	cond, err := ParseCondition("device1.temp>80.5")
+	assert.Nil(t, err)
+	assert.Equal(t, Condition{Source: "device1.temp", Operator: OperatorGT, Threshold: 80.5}, cond)
+	assert.Equal(t, "device1.temp > 80.5", cond.String())
+	assert.Equal(t, "insert into alarm1 select device1.temp as value", cond.TQL("alarm1"))
+
+	cond, err = ParseCondition(" device1.metrics.cpu <= device2.max_cpu ")
+	assert.Nil(t, err)
+	assert.Equal(t, Condition{Source: "device1.metrics.cpu", Operator: OperatorLTE, ThresholdSource: "device2.max_cpu"}, cond)
+	assert.Equal(t, "insert into alarm1 select device1.metrics.cpu as value, device2.max_cpu as threshold", cond.TQL("alarm1"))
+
+	for _, text := range []string{"", "device1.temp", "temp > 80", "device1.temp => 80", "device1.temp > 'hot'", "#sensors.temp > 80", "device1.* > 80"} {
+		_, err = ParseCondition(text)
+		assert.ErrorIs(t, err, ErrConditionInvalid, text)
+	}
+}
+
+func TestNewRule(t *testing.T) {
+	rule, err := NewRule("device1.temp > 80", "", 5, "5m")
+	assert.Nil(t, err)
+	assert.Equal(t, SeverityWarning, rule.Severity)
+	assert.Equal(t, 5*time.Minute, rule.Duration)
+
+	decoded, err := DecodeRule(rule.Properties())
+	assert.Nil(t, err)
+	assert.Equal(t, rule, decoded)
+
+	_, err = NewRule("device1.temp > 80", "fatal", 0, "")
+	assert.ErrorIs(t, err, ErrSeverityInvalid)
+	_, err = NewRule("device1.temp > 80", SeverityMajor, -1, "")
+	assert.ErrorIs(t, err, ErrHysteresisInvalid)
+	_, err = NewRule("device1.temp > 80", SeverityMajor, 0, "5 minutes")
+	assert.ErrorIs(t, err, ErrDurationInvalid)
+}
+
+func TestRule_Evaluate(t *testing.T) {
+	rule, err := NewRule("device1.temp > 80", SeverityCritical, 5, "1m")
+	assert.Nil(t, err)
+
+	state := DecodeState(nil)
+	assert.Equal(t, StatusCleared, state.Status)
+	values := map[string]constraint.Node{}
+	evaluate := func(temp interface{}, now int64) bool {
+		values["device1.temp"] = constraint.NewNode(temp)
+		return rule.Evaluate(&state, values, now)
+	}
+
+	// unknown and non-numeric values are ignored.
+	assert.False(t, rule.Evaluate(&state, values, 0))
+	assert.False(t, evaluate("hot", 0))
+
+	// condition holds for duration.
+	assert.False(t, evaluate(85, 1000))
+	assert.Equal(t, int64(1000), state.PendingSince)
+	assert.False(t, evaluate(79, 2000))
+	assert.Zero(t, state.PendingSince)
+	assert.False(t, evaluate(81, 3000))
+	assert.False(t, rule.Due(&state, 62000))
+	assert.True(t, rule.Due(&state, 63000))
+	assert.True(t, evaluate(90.5, 63000))
+	assert.Equal(t, StatusActive, state.Status)
+	assert.Equal(t, int64(63000), state.RaisedAt)
+	assert.Equal(t, 90.5, state.Value)
+	assert.Equal(t, int64(1), state.RaiseCount)
+
+	// acknowledged, hysteresis keeps alarm active.
+	assert.Nil(t, state.Acknowledge("admin", 64000))
+	assert.ErrorIs(t, state.Acknowledge("admin", 64000), ErrAlarmNotActive)
+	assert.False(t, evaluate(76, 65000))
+	assert.Equal(t, StatusAcknowledged, state.Status)
+	assert.True(t, evaluate(75, 66000))
+	assert.Equal(t, StatusCleared, state.Status)
+	assert.Equal(t, int64(66000), state.ClearedAt)
+	assert.ErrorIs(t, state.Acknowledge("admin", 67000), ErrAlarmNotActive)
+
+	// state is kept in properties of alarm entity.
+	assert.Equal(t, state, DecodeState(state.Properties()))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alarm
+
+import (
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+// State is runtime state of an alarm, timestamps are in milliseconds.
+type State struct {
+	Status         string
+	Value          float64
+	PendingSince   int64
+	RaisedAt       int64
+	AcknowledgedAt int64
+	AcknowledgedBy string
+	ClearedAt      int64
+	RaiseCount     int64
+}
+
+// Active returns true if alarm raised and not cleared.
+func (s *State) Active() bool {
+	return s.Status == StatusActive || s.Status == StatusAcknowledged
+}
+
+// Acknowledge acknowledges an active alarm.
+func (s *State) Acknowledge(user string, now int64) error {
+	if s.Status != StatusActive {
+		return errors.Wrapf(ErrAlarmNotActive, "status %s", s.Status)
+	}
+
+	s.Status = StatusAcknowledged
+	s.AcknowledgedAt = now
+	s.AcknowledgedBy = user
+	return nil
+}
+
+// Properties returns state as properties of alarm entity.
+func (s *State) Properties() map[string]constraint.Node {
+	return map[string]constraint.Node{
+		FieldStatus:         constraint.StringNode(s.Status),
+		FieldValue:          constraint.FloatNode(s.Value),
+		FieldPendingSince:   constraint.IntNode(s.PendingSince),
+		FieldRaisedAt:       constraint.IntNode(s.RaisedAt),
+		FieldAcknowledgedAt: constraint.IntNode(s.AcknowledgedAt),
+		FieldAcknowledgedBy: constraint.StringNode(s.AcknowledgedBy),
+		FieldClearedAt:      constraint.IntNode(s.ClearedAt),
+		FieldRaiseCount:     constraint.IntNode(s.RaiseCount),
+	}
+}
+
+// DecodeState decodes state from properties of alarm entity, status is cleared if not set.
+func DecodeState(kvalues map[string]constraint.Node) State {
+	state := State{
+		Status:         stringValue(kvalues[FieldStatus]),
+		AcknowledgedBy: stringValue(kvalues[FieldAcknowledgedBy]),
+	}
+
+	state.Value, _ = numeric(kvalues[FieldValue])
+	state.PendingSince = intValue(kvalues[FieldPendingSince])
+	state.RaisedAt = intValue(kvalues[FieldRaisedAt])
+	state.AcknowledgedAt = intValue(kvalues[FieldAcknowledgedAt])
+	state.ClearedAt = intValue(kvalues[FieldClearedAt])
+	state.RaiseCount = intValue(kvalues[FieldRaiseCount])
+	if state.Status == "" {
+		state.Status = StatusCleared
+	}
+	return state
+}
+
+// DecodeRule decodes rule from properties of alarm entity.
+func DecodeRule(kvalues map[string]constraint.Node) (*Rule, error) {
+	hysteresis, _ := numeric(kvalues[FieldHysteresis])
+	rule, err := NewRule(stringValue(kvalues[FieldCondition]), stringValue(kvalues[FieldSeverity]),
+		hysteresis, stringValue(kvalues[FieldDuration]))
+	if nil != err {
+		return nil, err
+	}
+
+	rule.Description = stringValue(kvalues[FieldDescription])
+	return rule, nil
+}
+
+// Properties returns rule as properties of alarm entity.
+func (r *Rule) Properties() map[string]constraint.Node {
+	var duration string
+	if r.Duration > 0 {
+		duration = r.Duration.String()
+	}
+
+	return map[string]constraint.Node{
+		FieldCondition:   constraint.StringNode(r.Condition.String()),
+		FieldSeverity:    constraint.StringNode(r.Severity),
+		FieldHysteresis:  constraint.FloatNode(r.Hysteresis),
+		FieldDuration:    constraint.StringNode(duration),
+		FieldDescription: constraint.StringNode(r.Description),
+	}
+}
+
+func stringValue(node constraint.Node) string {
+	if nil == node {
+		return ""
+	} else if val, ok := node.Value().(string); ok {
+		return val
+	}
+	return ""
+}
+
+func intValue(node constraint.Node) int64 {
+	val, _ := numeric(node)
+	return int64(val)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alarm
+
+import "github.com/pkg/errors"
+
+var (
+	ErrConditionInvalid  = errors.New("alarm condition invalid")
+	ErrSeverityInvalid   = errors.New("alarm severity invalid")
+	ErrDurationInvalid   = errors.New("alarm duration invalid")
+	ErrHysteresisInvalid = errors.New("alarm hysteresis invalid")
+	ErrAlarmNotActive    = errors.New("alarm not active")
+)
+
+// alarm severities.
+const (
+	SeverityCritical = "critical"
+	SeverityMajor    = "major"
+	SeverityMinor    = "minor"
+	SeverityWarning  = "warning"
+)
+
+// alarm statuses, an active alarm is acknowledged by user and cleared when condition not holds.
+const (
+	StatusActive       = "active"
+	StatusAcknowledged = "acknowledged"
+	StatusCleared      = "cleared"
+)
+
+// operators of conditions.
+const (
+	OperatorGT  = ">"
+	OperatorGTE = ">="
+	OperatorLT  = "<"
+	OperatorLTE = "<="
+	OperatorEQ  = "=="
+	OperatorNE  = "!="
+)
+
+// properties of alarm entity, rule fields are immutable.
+const (
+	FieldCondition      = "condition"
+	FieldSeverity       = "severity"
+	FieldHysteresis     = "hysteresis"
+	FieldDuration       = "duration"
+	FieldDescription    = "description"
+	FieldStatus         = "status"
+	FieldValue          = "value"
+	FieldPendingSince   = "pending_since"
+	FieldRaisedAt       = "raised_at"
+	FieldAcknowledgedAt = "acknowledged_at"
+	FieldAcknowledgedBy = "acknowledged_by"
+	FieldClearedAt      = "cleared_at"
+	FieldRaiseCount     = "raise_count"
+)
+
+// MapperName is name of mapper watching sources of alarm condition.
+const MapperName = "alarm"
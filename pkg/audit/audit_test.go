@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/util"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+type fakeSink []*Event
+
+func (f *fakeSink) Publish(ctx context.Context, event *Event) error {
+	*f = append(*f, event)
+	return nil
+}
+
+func newBase(id string, version int64, configs map[string]constraint.Config) *statem.Base {
+	return &statem.Base{
+		ID:      id,
+		Type:    "DEVICE",
+		Owner:   "admin",
+		Version: version,
+		Mappers: []statem.MapperDesc{{Name: "mapper1", TQLString: "insert into device1 select device2.temp as temp"}},
+		KValues: map[string]constraint.Node{"temp": constraint.IntNode(20)},
+		Configs: configs,
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := map[string]interface{}{
+		"type":    "DEVICE",
+		"configs": map[string]interface{}{"temp": map[string]interface{}{"type": "int"}, "hum": "x"},
+	}
+	after := map[string]interface{}{
+		"type":    "DEVICE",
+		"configs": map[string]interface{}{"temp": map[string]interface{}{"type": "float"}, "light": "y"},
+	}
+
+	assert.Equal(t, []Change{
+		{Path: "configs.hum", Op: OpRemove, Before: "x"},
+		{Path: "configs.light", Op: OpAdd, After: "y"},
+		{Path: "configs.temp.type", Op: OpReplace, Before: "int", After: "float"},
+	}, Diff(before, after))
+	assert.Empty(t, Diff(before, before))
+}
+
+func TestAuditor_Record(t *testing.T) {
+	sink := &fakeSink{}
+	kv, lease := fake.NewKV(), &fake.Lease{}
+	store := NewEtcdStore(kv, lease, time.Hour)
+	auditor := NewAuditor("core", sink, store)
+
+	before := newBase("device1", 3, map[string]constraint.Config{})
+	after := newBase("device1", 4, map[string]constraint.Config{"temp": {ID: "temp", Type: "int"}})
+
+	ctx := ContextWithActor(context.Background(), "tomas")
+	auditor.Record(ctx, EventTypeConfigsChanged, before, after)
+	auditor.Record(context.Background(), EventTypeEntityDeleted, after, nil)
+	auditor.Record(context.Background(), EventTypeEntityCreated, nil, newBase("device2", 1, nil))
+
+	assert.Len(t, *sink, 3)
+	event := (*sink)[0]
+	assert.Equal(t, SpecVersion, event.SpecVersion)
+	assert.Equal(t, "core", event.Source)
+	assert.Equal(t, "device1", event.Subject)
+	assert.Equal(t, "tomas", event.Data.Actor)
+	assert.Equal(t, int64(4), event.Data.Version)
+	assert.Len(t, event.Data.Diff, 1)
+	assert.Equal(t, "configs.temp", event.Data.Diff[0].Path)
+	assert.Equal(t, OpAdd, event.Data.Diff[0].Op)
+
+	// actor unknown.
+	assert.Empty(t, (*sink)[1].Data.Actor)
+	assert.Nil(t, (*sink)[1].Data.After)
+
+	// stored with retention, sharing lease.
+	assert.Len(t, kv.Values(), 6)
+	assert.Equal(t, kv.Lease(util.FormatAudit("device2", (*sink)[2].Time.UnixNano())),
+		kv.Lease(util.FormatTimeline((*sink)[2].Time.UnixNano(), "device2")))
+	assert.Len(t, lease.Grants(), 1)
+	assert.True(t, lease.Grants()[0] > 3600 && lease.Grants()[0] <= 7200)
+
+	events, err := store.Query(context.Background(), Query{EntityID: "device1"})
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, EventTypeEntityDeleted, events[0].Type)
+	assert.Equal(t, EventTypeConfigsChanged, events[1].Type)
+
+	events, err = store.Query(context.Background(), Query{Actor: "tomas"})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+
+	events, err = store.Query(context.Background(), Query{Limit: 1})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "device2", events[0].Subject)
+
+	events, err = store.Query(context.Background(), Query{StartTime: time.Now().Add(time.Minute)})
+	assert.Nil(t, err)
+	assert.Empty(t, events)
+}
+
+// countingKV counts fetched events.
+type countingKV struct {
+	*fake.KV
+	fetched int
+}
+
+func (c *countingKV) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	res, err := c.KV.Get(ctx, key, opts...)
+	if nil == err {
+		c.fetched += len(res.Kvs)
+	}
+	return res, err
+}
+
+func TestEtcdStore_Query(t *testing.T) {
+	kv := &countingKV{KV: fake.NewKV()}
+	store := NewEtcdStore(kv, &fake.Lease{}, 0)
+
+	start := time.Unix(1640995200, 0).UTC()
+	for i := 0; i < 250; i++ {
+		event := &Event{Subject: "device1", Type: EventTypeConfigsChanged, Time: start.Add(time.Duration(i) * time.Second)}
+		if i%50 == 0 {
+			event.Type = EventTypeEntityCreated
+		}
+		if i%2 == 1 {
+			event.Subject = "device2"
+		}
+		assert.Nil(t, store.Append(context.Background(), event))
+	}
+
+	// latest first, only the limit is fetched.
+	events, err := store.Query(context.Background(), Query{Limit: 3})
+	assert.Nil(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, start.Add(249*time.Second), events[0].Time)
+	assert.Equal(t, "device2", events[0].Subject)
+	assert.Equal(t, 3, kv.fetched)
+
+	// time bounds are inclusive.
+	kv.fetched = 0
+	events, err = store.Query(context.Background(), Query{EntityID: "device1", StartTime: start.Add(10 * time.Second), EndTime: start.Add(20 * time.Second)})
+	assert.Nil(t, err)
+	assert.Len(t, events, 6)
+	assert.Equal(t, start.Add(20*time.Second), events[0].Time)
+	assert.Equal(t, start.Add(10*time.Second), events[5].Time)
+	assert.Equal(t, 6, kv.fetched)
+
+	// filtered across pages.
+	events, err = store.Query(context.Background(), Query{Type: EventTypeEntityCreated, Limit: 4})
+	assert.Nil(t, err)
+	assert.Len(t, events, 4)
+	assert.Equal(t, start.Add(200*time.Second), events[0].Time)
+	assert.Equal(t, start.Add(50*time.Second), events[3].Time)
+
+	events, err = store.Query(context.Background(), Query{EntityID: "device2"})
+	assert.Nil(t, err)
+	assert.Len(t, events, 125)
+}
+
+func TestActorResolver(t *testing.T) {
+	_, err := NewActorResolver([]string{"gateway"})
+	assert.ErrorIs(t, err, ErrProxyInvalid)
+
+	resolver, err := NewActorResolver([]string{"10.0.0.0/8", "192.168.1.1"})
+	assert.Nil(t, err)
+	assert.True(t, resolver.trusts("10.1.2.3:4567"))
+	assert.True(t, resolver.trusts("192.168.1.1:80"))
+	assert.False(t, resolver.trusts("192.168.1.2:80"))
+
+	var actor string
+	container := restful.NewContainer()
+	container.Filter(resolver.HTTPFilter)
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/entities").To(func(req *restful.Request, resp *restful.Response) {
+		actor = ActorFrom(req.Request.Context())
+	}))
+	container.Add(ws)
+
+	for _, test := range []struct {
+		remote string
+		actor  string
+	}{
+		{"10.1.2.3:4567", "tomas"},
+		{"172.16.0.1:4567", ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/entities", nil)
+		req.RemoteAddr = test.remote
+		req.Header.Set(HeaderUser, "tomas")
+		actor = ""
+		container.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, test.actor, actor)
+	}
+
+	// grpc metadata.
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(HeaderUser, "tomas"))
+	trusted := peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 4567}})
+	assert.Equal(t, "tomas", ActorFrom(resolver.grpcContext(trusted)))
+	untrusted := peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("172.16.0.1"), Port: 4567}})
+	assert.Equal(t, "", ActorFrom(resolver.grpcContext(untrusted)))
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// HeaderUser identifies who sends the request, set by trusted proxies which authenticate users.
+const HeaderUser = "User"
+
+type actorKey struct{}
+
+// ContextWithActor returns ctx carrying who performs operations.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns who performs operations, empty if unknown.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// ActorResolver sets actor of requests from User header or metadata, which is only
+// trusted from proxies authenticating users, e.g. the gateway. actor of other requests
+// defaults to owner of entity.
+type ActorResolver struct {
+	trusted []*net.IPNet
+}
+
+// NewActorResolver returns ActorResolver trusting proxies of addresses, in IP or CIDR form.
+func NewActorResolver(proxies []string) (*ActorResolver, error) {
+	r := &ActorResolver{}
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); nil != ip && nil != ip.To4() {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if nil != err {
+			return nil, errors.Wrap(ErrProxyInvalid, proxy)
+		}
+		r.trusted = append(r.trusted, ipNet)
+	}
+	return r, nil
+}
+
+// trusts returns true if addr, in host:port form, is a trusted proxy.
+func (r *ActorResolver) trusts(addr string) bool {
+	if host, _, err := net.SplitHostPort(addr); nil == err {
+		addr = host
+	}
+	ip := net.ParseIP(addr)
+	if nil == ip {
+		return false
+	}
+	for _, ipNet := range r.trusted {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPFilter sets actor of request from User header of trusted proxies.
+func (r *ActorResolver) HTTPFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if user := req.HeaderParameter(HeaderUser); user != "" && r.trusts(req.Request.RemoteAddr) {
+		req.Request = req.Request.WithContext(ContextWithActor(req.Request.Context(), user))
+	}
+	chain.ProcessFilter(req, resp)
+}
+
+// UnaryServerInterceptor sets actor of unary calls from user metadata of trusted proxies.
+func (r *ActorResolver) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(r.grpcContext(ctx), req)
+	}
+}
+
+// StreamServerInterceptor sets actor of streams from user metadata of trusted proxies.
+func (r *ActorResolver) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &actorStream{ServerStream: ss, ctx: r.grpcContext(ss.Context())})
+	}
+}
+
+func (r *ActorResolver) grpcContext(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok || nil == p.Addr || !r.trusts(p.Addr.String()) {
+		return ctx
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if users := md.Get(HeaderUser); len(users) > 0 && users[0] != "" {
+			return ContextWithActor(ctx, users[0])
+		}
+	}
+	return ctx
+}
+
+type actorStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *actorStream) Context() context.Context {
+	return s.ctx
+}
+
+// Auditor records entity lifecycle events into sink and store.
+type Auditor struct {
+	source string
+	sink   Sink
+	store  Store
+}
+
+// NewAuditor returns an Auditor, sink and store are optional.
+func NewAuditor(source string, sink Sink, store Store) *Auditor {
+	return &Auditor{source: source, sink: sink, store: store}
+}
+
+// Record emits lifecycle event of entity, failures are logged without failing the operation.
+func (a *Auditor) Record(ctx context.Context, eventType string, before, after *statem.Base) {
+	if nil == a || (nil == a.sink && nil == a.store) {
+		return
+	}
+
+	event := a.newEvent(ctx, eventType, before, after)
+	if nil != a.sink {
+		if err := a.sink.Publish(ctx, event); nil != err {
+			log.Error("publish audit event", logger.EntityID(event.Subject), zap.String("type", eventType), zap.Error(err))
+		}
+	}
+	if nil != a.store {
+		if err := a.store.Append(ctx, event); nil != err {
+			log.Error("store audit event", logger.EntityID(event.Subject), zap.String("type", eventType), zap.Error(err))
+		}
+	}
+}
+
+func (a *Auditor) newEvent(ctx context.Context, eventType string, before, after *statem.Base) *Event {
+	current := after
+	if nil == current {
+		current = before
+	}
+
+	data := EventData{
+		Actor:      ActorFrom(ctx),
+		Owner:      current.Owner,
+		EntityType: current.Type,
+		Version:    current.Version,
+		Before:     Snapshot(before),
+		After:      Snapshot(after),
+	}
+	if nil != before && nil != after {
+		data.Diff = Diff(data.Before, data.After)
+	}
+
+	return &Event{
+		SpecVersion:     SpecVersion,
+		ID:              uuid(),
+		Source:          a.source,
+		Type:            eventType,
+		Subject:         current.ID,
+		Time:            time.Now().UTC(),
+		DataContentType: DataContentType,
+		Data:            data,
+	}
+}
+
+// Snapshot returns audited fields of entity in json form, nil if base is nil.
+func Snapshot(base *statem.Base) map[string]interface{} {
+	if nil == base {
+		return nil
+	}
+
+	mappers := make(map[string]string, len(base.Mappers))
+	for _, mm := range base.Mappers {
+		mappers[mm.Name] = mm.TQLString
+	}
+
+	relationships := make(map[string]interface{}, len(base.Relationships))
+	for _, rel := range base.Relationships {
+		relationships[rel.Type+"/"+rel.Target] = rel.Properties
+	}
+
+	properties := make(map[string]interface{}, len(base.KValues))
+	for key, val := range base.KValues {
+		properties[key] = val.Value()
+	}
+
+	bytes, err := json.Marshal(map[string]interface{}{
+		"id":            base.ID,
+		"type":          base.Type,
+		"owner":         base.Owner,
+		"source":        base.Source,
+		"properties":    properties,
+		"configs":       base.Configs,
+		"mappers":       mappers,
+		"relationships": relationships,
+	})
+	if nil != err {
+		log.Error("snapshot entity", logger.EntityID(base.ID), zap.Error(err))
+		return nil
+	}
+
+	var snapshot map[string]interface{}
+	if err = json.Unmarshal(bytes, &snapshot); nil != err {
+		log.Error("snapshot entity", logger.EntityID(base.ID), zap.Error(err))
+		return nil
+	}
+	return snapshot
+}
+
+// uuid generate an uuid.
+func uuid() string {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		return ""
+	}
+	// see section 4.1.1.
+	uuid[8] = uuid[8]&^0xc0 | 0x80
+	// see section 4.1.3.
+	uuid[6] = uuid[6]&^0xf0 | 0x40
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
+}
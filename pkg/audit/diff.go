@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Diff returns changes from before to after, nested maps are compared by key
+// and other values are compared as a whole.
+func Diff(before, after map[string]interface{}) []Change {
+	var changes []Change
+	diff("", before, after, &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func diff(prefix string, before, after map[string]interface{}, changes *[]Change) {
+	for key, val := range before {
+		path := join(prefix, key)
+		next, ok := after[key]
+		if !ok {
+			*changes = append(*changes, Change{Path: path, Op: OpRemove, Before: val})
+			continue
+		}
+
+		prevMap, ok1 := val.(map[string]interface{})
+		nextMap, ok2 := next.(map[string]interface{})
+		if ok1 && ok2 {
+			diff(path, prevMap, nextMap, changes)
+		} else if !reflect.DeepEqual(val, next) {
+			*changes = append(*changes, Change{Path: path, Op: OpReplace, Before: val, After: next})
+		}
+	}
+
+	for key, val := range after {
+		if _, ok := before[key]; !ok {
+			*changes = append(*changes, Change{Path: join(prefix, key), Op: OpAdd, After: val})
+		}
+	}
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	dapr "github.com/dapr/go-sdk/client"
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+)
+
+// sink types.
+const (
+	SinkPubsub = "pubsub"
+	SinkLog    = "log"
+)
+
+// Publisher publishes data onto pubsub topic, implemented by dapr client.
+type Publisher interface {
+	PublishEvent(ctx context.Context, pubsubName, topicName string, data interface{}, opts ...dapr.PublishEventOption) error
+}
+
+// NewSink returns sink of configuration, nil if disabled.
+func NewSink(cfg config.Audit, publisher Publisher) (Sink, error) {
+	switch cfg.Sink {
+	case "":
+		return nil, nil
+	case SinkPubsub:
+		return &pubsubSink{publisher: publisher, pubsubName: cfg.PubsubName, topic: cfg.Topic}, nil
+	case SinkLog:
+		return &logSink{}, nil
+	default:
+		return nil, errors.Wrap(ErrSinkInvalid, cfg.Sink)
+	}
+}
+
+// pubsubSink publishes structured CloudEvents, passed through by dapr as is.
+type pubsubSink struct {
+	publisher  Publisher
+	pubsubName string
+	topic      string
+}
+
+func (s *pubsubSink) Publish(ctx context.Context, event *Event) error {
+	bytes, err := json.Marshal(event)
+	if nil != err {
+		return errors.Wrap(err, "encode audit event")
+	}
+
+	err = s.publisher.PublishEvent(ctx, s.pubsubName, s.topic, bytes, dapr.PublishEventWithContentType(ContentType))
+	return errors.Wrap(err, "publish audit event")
+}
+
+// logSink writes events into log.
+type logSink struct{}
+
+func (s *logSink) Publish(ctx context.Context, event *Event) error {
+	log.Info("audit event", logger.EntityID(event.Subject),
+		zap.String("type", event.Type),
+		zap.String("actor", event.Data.Actor),
+		zap.Int64("version", event.Data.Version),
+		zap.Any("diff", event.Data.Diff))
+	return nil
+}
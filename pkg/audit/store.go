@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/util"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// store types.
+const (
+	StoreEtcd = "etcd"
+)
+
+// NewStore returns store of configuration, nil if disabled.
+func NewStore(cfg config.Audit, client *clientv3.Client) (Store, error) {
+	switch cfg.Store {
+	case "":
+		return nil, nil
+	case StoreEtcd:
+		return NewEtcdStore(client, client, cfg.Retention), nil
+	default:
+		return nil, errors.Wrap(ErrStoreInvalid, cfg.Store)
+	}
+}
+
+// leaseBucket is the period of events sharing a lease, capped by retention.
+const leaseBucket = time.Hour
+
+// etcdStore keeps events keyed by entity and time, and in the timeline of all entities
+// for queries without entity, expired by lease.
+// events of a bucket share a lease, expired between retention and retention + bucket.
+type etcdStore struct {
+	kv        clientv3.KV
+	lease     clientv3.Lease
+	retention int64
+	bucket    int64
+
+	lock    sync.Mutex
+	current int64
+	leaseID clientv3.LeaseID
+}
+
+func NewEtcdStore(kv clientv3.KV, lease clientv3.Lease, retention time.Duration) Store {
+	bucket := leaseBucket
+	if retention < bucket {
+		bucket = retention
+	}
+	return &etcdStore{
+		kv:        kv,
+		lease:     lease,
+		retention: int64(retention.Seconds()),
+		bucket:    int64(bucket.Seconds()),
+		leaseID:   clientv3.NoLease,
+	}
+}
+
+func (s *etcdStore) Append(ctx context.Context, event *Event) error {
+	bytes, err := json.Marshal(event)
+	if nil != err {
+		return errors.Wrap(err, "encode audit event")
+	}
+
+	leaseID := clientv3.NoLease
+	var opts []clientv3.OpOption
+	if s.retention > 0 {
+		if leaseID, err = s.leaseOf(ctx, event.Time); nil != err {
+			return errors.Wrap(err, "grant audit event lease")
+		}
+		opts = append(opts, clientv3.WithLease(leaseID))
+	}
+
+	timestamp := event.Time.UnixNano()
+	if _, err = s.kv.Txn(ctx).Then(
+		clientv3.OpPut(util.FormatAudit(event.Subject, timestamp), string(bytes), opts...),
+		clientv3.OpPut(util.FormatTimeline(timestamp, event.Subject), string(bytes), opts...),
+	).Commit(); nil != err {
+		// lease may be revoked, granted again by next event.
+		s.resetLease(leaseID)
+		return errors.Wrap(err, "put audit event")
+	}
+	return nil
+}
+
+// leaseOf returns lease shared by events in the bucket of t.
+func (s *etcdStore) leaseOf(ctx context.Context, t time.Time) (clientv3.LeaseID, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	bucket := t.Unix() / s.bucket
+	if s.leaseID != clientv3.NoLease && bucket == s.current {
+		return s.leaseID, nil
+	}
+
+	ttl := (bucket+1)*s.bucket + s.retention - t.Unix()
+	res, err := s.lease.Grant(ctx, ttl)
+	if nil != err {
+		return clientv3.NoLease, errors.Wrap(err, "grant lease")
+	}
+	s.current, s.leaseID = bucket, res.ID
+	return res.ID, nil
+}
+
+func (s *etcdStore) resetLease(leaseID clientv3.LeaseID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.leaseID == leaseID {
+		s.leaseID = clientv3.NoLease
+	}
+}
+
+func (s *etcdStore) Query(ctx context.Context, query Query) ([]*Event, error) {
+	start, end := queryRange(query)
+
+	// actor and type are filtered after fetched, page until limit is reached.
+	page := int64(queryPage)
+	if query.Limit > 0 && query.Actor == "" && query.Type == "" {
+		page = int64(query.Limit)
+	}
+
+	var events []*Event
+	for {
+		res, err := s.kv.Get(ctx, start, clientv3.WithRange(end),
+			clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend), clientv3.WithLimit(page))
+		if nil != err {
+			return nil, errors.Wrap(err, "list audit events")
+		}
+
+		for _, kv := range res.Kvs {
+			var event Event
+			if err = json.Unmarshal(kv.Value, &event); nil != err {
+				return nil, errors.Wrap(err, "decode audit event")
+			} else if match(&event, query) {
+				events = append(events, &event)
+			}
+			if query.Limit > 0 && len(events) == query.Limit {
+				return events, nil
+			}
+		}
+
+		if !res.More || len(res.Kvs) == 0 {
+			return events, nil
+		}
+		// latest first, next page ends before the last key.
+		end = string(res.Kvs[len(res.Kvs)-1].Key)
+	}
+}
+
+// queryPage is the number of events fetched at a time.
+const queryPage = 100
+
+// queryRange returns key range [start, end) of events matched by time of query.
+func queryRange(query Query) (string, string) {
+	var startTime, endTime int64
+	if !query.StartTime.IsZero() {
+		startTime = query.StartTime.UnixNano()
+	}
+	if !query.EndTime.IsZero() {
+		endTime = query.EndTime.UnixNano() + 1
+	}
+
+	if query.EntityID != "" {
+		start := util.FormatAudit(query.EntityID, startTime)
+		if endTime > 0 {
+			return start, util.FormatAudit(query.EntityID, endTime)
+		}
+		// events of entity ids sharing the prefix are dropped by match.
+		return start, clientv3.GetPrefixRangeEnd(util.EtcdAuditPrefix + "." + query.EntityID + ".")
+	}
+
+	start := util.FormatTimeline(startTime, "")
+	if endTime > 0 {
+		return start, util.FormatTimeline(endTime, "")
+	}
+	return start, clientv3.GetPrefixRangeEnd(util.EtcdTimelinePrefix + ".")
+}
+
+func match(event *Event, query Query) bool {
+	switch {
+	case query.EntityID != "" && event.Subject != query.EntityID:
+		return false
+	case query.Actor != "" && event.Data.Actor != query.Actor:
+		return false
+	case query.Type != "" && event.Type != query.Type:
+		return false
+	case !query.StartTime.IsZero() && event.Time.Before(query.StartTime):
+		return false
+	case !query.EndTime.IsZero() && event.Time.After(query.EndTime):
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	SpecVersion     = "1.0"
+	DataContentType = "application/json"
+	// ContentType of structured CloudEvents.
+	ContentType = "application/cloudevents+json"
+)
+
+// event types.
+const (
+	EventTypeEntityCreated       = "io.tkeel.core.entity.created"
+	EventTypeEntityDeleted       = "io.tkeel.core.entity.deleted"
+	EventTypeEntityRestored      = "io.tkeel.core.entity.restored"
+	EventTypeConfigsChanged      = "io.tkeel.core.entity.configs.changed"
+	EventTypeMapperAppended      = "io.tkeel.core.entity.mapper.appended"
+	EventTypeMapperRemoved       = "io.tkeel.core.entity.mapper.removed"
+	EventTypeRelationshipAdded   = "io.tkeel.core.entity.relationship.added"
+	EventTypeRelationshipRemoved = "io.tkeel.core.entity.relationship.removed"
+)
+
+// change operations.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+var (
+	ErrSinkInvalid  = errors.New("invalid audit sink")
+	ErrStoreInvalid = errors.New("invalid audit store")
+	ErrProxyInvalid = errors.New("invalid trusted proxy")
+)
+
+// Event is an entity lifecycle event in structured CloudEvents format.
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            EventData `json:"data"`
+}
+
+// EventData describes who changed what of the entity.
+type EventData struct {
+	// Actor is empty if who performs the operation is unknown.
+	Actor      string                 `json:"actor"`
+	Owner      string                 `json:"owner"`
+	EntityType string                 `json:"entity_type"`
+	Version    int64                  `json:"version"`
+	Before     map[string]interface{} `json:"before,omitempty"`
+	After      map[string]interface{} `json:"after,omitempty"`
+	Diff       []Change               `json:"diff,omitempty"`
+}
+
+// Change is a changed field between snapshots of entity.
+type Change struct {
+	Path   string      `json:"path"`
+	Op     string      `json:"op"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// Query filters stored audit events, empty fields match all.
+type Query struct {
+	EntityID string
+	Actor    string
+	Type     string
+	// StartTime and EndTime bound event time, unlimited if zero.
+	StartTime time.Time
+	EndTime   time.Time
+	// Limit of returned events, the latest are returned.
+	Limit int
+}
+
+// Sink publishes audit events.
+type Sink interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+// Store keeps audit events for querying.
+type Store interface {
+	Append(ctx context.Context, event *Event) error
+	// Query returns matched events, latest first.
+	Query(ctx context.Context, query Query) ([]*Event, error)
+}
@@ -17,16 +17,22 @@ limitations under the License.
 package config
 
 import (
+	"bytes"
 	"io/fs"
 	"net/url"
 	"os"
+	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/tkeel-io/core/pkg/print"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
 )
 
 const (
@@ -41,13 +47,36 @@ const (
 
 var (
 	_config = defaultConfig()
+	_lock   sync.RWMutex
+	// _overrides are flag settings, applied again after reloading.
+	_overrides []func(*Configuration)
+	_handlers  []ChangeHandler
 )
 
+// _liveSettings are settings applied at runtime, changes of other settings require restart.
+var _liveSettings = map[string]struct{}{
+	"logger.level":       {},
+	"search_engine":      {},
+	"time_series":        {},
+	"flush.default":      {},
+	"flush.types":        {},
+	"ingest":             {},
+	"presence":           {},
+	"deletion.retention": {},
+}
+
+var ErrUnsafeChange = errors.New("config change can not be applied at runtime")
+
+// ChangeHandler is called after configuration reloaded.
+type ChangeHandler func(prev, next Configuration)
+
 var (
 	_defaultAppServer = Server{
-		AppID:             DefaultAppID,
-		AppPort:           DefaultAppPort,
-		CoroutinePoolSize: 500,
+		AppID:              DefaultAppID,
+		AppPort:            DefaultAppPort,
+		CoroutinePoolSize:  500,
+		ShutdownTimeout:    30 * time.Second,
+		HealthCheckTimeout: 3 * time.Second,
 	}
 	_defaultLogConfig = LogConfig{
 		Level: "debug",
@@ -58,7 +87,17 @@ var (
 		Username: "admin",
 		Password: "admin",
 	}
-	_defaultEtcdConfig = EtcdConfig{[]string{"http://localhost:2379"}}
+	_defaultEmbeddedConfig    = EmbeddedConfig{Path: "data/search"}
+	_defaultFlushTick         = time.Second
+	_defaultAuditRetention    = 30 * 24 * time.Hour
+	_defaultDeletionRetention = 7 * 24 * time.Hour
+	_defaultPurgeInterval     = time.Hour
+	_defaultReconcileInterval = 10 * time.Minute
+	_defaultOrphanGrace       = 5 * time.Minute
+	_defaultResolveInterval   = 30 * time.Second
+	_defaultIngestWindow      = 16
+	_defaultIngestBatchSize   = 1000
+	_defaultEtcdConfig        = EtcdConfig{[]string{"http://localhost:2379"}}
 )
 
 type Configuration struct {
@@ -67,6 +106,15 @@ type Configuration struct {
 	Etcd         EtcdConfig   `mapstructure:"etcd"`
 	TimeSeries   Metadata     `mapstructure:"time_series"`
 	SearchEngine SearchEngine `mapstructure:"search_engine"`
+	Reconciler   Reconciler   `mapstructure:"reconciler"`
+	Tenant       Tenant       `mapstructure:"tenant"`
+	Tracing      Tracing      `mapstructure:"tracing"`
+	Flush        Flush        `mapstructure:"flush"`
+	Audit        Audit        `mapstructure:"audit"`
+	Deletion     Deletion     `mapstructure:"deletion"`
+	Mapper       Mapper       `mapstructure:"mapper"`
+	Ingest       Ingest       `mapstructure:"ingest"`
+	Presence     Presence     `mapstructure:"presence"`
 }
 
 type Pair struct {
@@ -84,8 +132,9 @@ type EtcdConfig struct {
 }
 
 type SearchEngine struct {
-	Use string   `mapstructure:"use" yaml:"use"`
-	ES  ESConfig `mapstructure:"elasticsearch" yaml:"elasticsearch"` //nolint:tagliatelle
+	Use      string         `mapstructure:"use" yaml:"use"`
+	ES       ESConfig       `mapstructure:"elasticsearch" yaml:"elasticsearch"` //nolint:tagliatelle
+	Embedded EmbeddedConfig `mapstructure:"embedded" yaml:"embedded"`
 }
 
 type ESConfig struct {
@@ -94,6 +143,112 @@ type ESConfig struct {
 	Password string   `yaml:"password"`
 }
 
+type Reconciler struct {
+	// Interval of periodic reconciliation, disabled if zero.
+	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
+	// OrphanGrace is how long an entity without state is kept before removed as orphan,
+	// entity is registered before its first state flush.
+	OrphanGrace time.Duration `mapstructure:"orphan_grace" yaml:"orphan_grace"`
+}
+
+type Tracing struct {
+	// Exporter of spans, "otlp" or "stdout", tracing disabled if empty.
+	Exporter string `mapstructure:"exporter" yaml:"exporter"`
+	// Endpoint of OTLP gRPC collector, e.g. localhost:4317.
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint"`
+	// Insecure disables TLS of OTLP exporter.
+	Insecure bool `mapstructure:"insecure" yaml:"insecure"`
+	// SampleRatio is the ratio of traces sampled, from 0 to 1.
+	SampleRatio float64 `mapstructure:"sample_ratio" yaml:"sample_ratio"`
+}
+
+type Audit struct {
+	// Sink of lifecycle events, "pubsub" or "log", disabled if empty.
+	Sink string `mapstructure:"sink" yaml:"sink"`
+	// PubsubName and Topic of pubsub sink.
+	PubsubName string `mapstructure:"pubsub_name" yaml:"pubsub_name"`
+	Topic      string `mapstructure:"topic" yaml:"topic"`
+	// Store of lifecycle events for querying, "etcd", disabled if empty.
+	Store string `mapstructure:"store" yaml:"store"`
+	// Retention of stored events, kept forever if zero.
+	Retention time.Duration `mapstructure:"retention" yaml:"retention"`
+	// TrustedProxies are addresses, in IP or CIDR form, of proxies authenticating users,
+	// only their User header is trusted as actor of events.
+	TrustedProxies []string `mapstructure:"trusted_proxies" yaml:"trusted_proxies"`
+}
+
+type Deletion struct {
+	// Retention of soft-deleted entities, restorable until purged.
+	Retention time.Duration `mapstructure:"retention" yaml:"retention"`
+	// PurgeInterval of purging entities whose retention passed, disabled if zero.
+	PurgeInterval time.Duration `mapstructure:"purge_interval" yaml:"purge_interval"`
+}
+
+type Mapper struct {
+	// ResolveInterval of resolving mapper references periodically, only resolved on changes if zero.
+	ResolveInterval time.Duration `mapstructure:"resolve_interval" yaml:"resolve_interval"`
+}
+
+type Ingest struct {
+	// Window is the number of batches a stream may have unacked.
+	Window int `mapstructure:"window" yaml:"window"`
+	// MaxBatchSize limits updates of a batch, larger batches are dropped.
+	MaxBatchSize int `mapstructure:"max_batch_size" yaml:"max_batch_size"`
+}
+
+type Presence struct {
+	// Timeout of heartbeat of all entity types, entity is offline if no properties
+	// received within timeout, presence is not tracked if zero.
+	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout"`
+	// Types overrides timeout of entity types, keyed by lower-cased type.
+	Types map[string]time.Duration `mapstructure:"types" yaml:"types"`
+}
+
+type Flush struct {
+	// Tick is the period of checking time-based flush policies.
+	Tick time.Duration `mapstructure:"tick" yaml:"tick"`
+	// Default policies of all entity types.
+	Default FlushPolicies `mapstructure:"default" yaml:"default"`
+	// Types overrides default policies of entity types, keyed by lower-cased type.
+	Types map[string]FlushPolicies `mapstructure:"types" yaml:"types"`
+}
+
+type FlushPolicies struct {
+	State      FlushPolicy `mapstructure:"state" yaml:"state"`
+	Search     FlushPolicy `mapstructure:"search" yaml:"search"`
+	TimeSeries FlushPolicy `mapstructure:"time_series" yaml:"time_series"`
+}
+
+type FlushPolicy struct {
+	// Mode is one of every_message, every_n, interval and on_idle, defaults to every_message.
+	Mode string `mapstructure:"mode" yaml:"mode"`
+	// Count is the number of messages between flushes of every_n.
+	Count int `mapstructure:"count" yaml:"count"`
+	// Interval is the minimum period between flushes of interval, the idle time
+	// before flushing of on_idle, and the maximum delay of every_n if set.
+	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
+}
+
+type Tenant struct {
+	// Default quota of every tenant.
+	Default Quota `mapstructure:"default" yaml:"default"`
+	// Quotas overrides default quota of specified tenants, keyed by owner.
+	Quotas map[string]Quota `mapstructure:"quotas" yaml:"quotas"`
+}
+
+type Quota struct {
+	// MaxEntities limits entity count of tenant, unlimited if zero.
+	MaxEntities int64 `mapstructure:"max_entities" yaml:"max_entities"`
+	// MessageRate limits messages per second of tenant, unlimited if zero.
+	MessageRate float64 `mapstructure:"message_rate" yaml:"message_rate"`
+	// MessageBurst is the maximum burst of messages, defaults to MessageRate.
+	MessageBurst int `mapstructure:"message_burst" yaml:"message_burst"`
+}
+
+type EmbeddedConfig struct {
+	Path string `yaml:"path"`
+}
+
 type LogConfig struct {
 	Dev    bool     `yaml:"dev"`
 	Level  string   `yaml:"level"`
@@ -105,9 +260,18 @@ func defaultConfig() Configuration {
 }
 
 func Get() Configuration {
+	_lock.RLock()
+	defer _lock.RUnlock()
 	return _config
 }
 
+// OnChange registers handler called after configuration reloaded.
+func OnChange(handler ChangeHandler) {
+	_lock.Lock()
+	defer _lock.Unlock()
+	_handlers = append(_handlers, handler)
+}
+
 func Init(cfgFile string) {
 	if cfgFile != "" {
 		// Use Config file from the flag.
@@ -119,20 +283,7 @@ func Init(cfgFile string) {
 		viper.AddConfigPath("/etc/core")
 	}
 
-	viper.SetEnvPrefix(_corePrefix)
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	viper.AutomaticEnv()
-
-	// default.
-	viper.SetDefault("server.app_port", DefaultAppPort)
-	viper.SetDefault("server.app_id", DefaultAppID)
-	viper.SetDefault("server.coroutine_pool_size", _defaultAppServer.CoroutinePoolSize)
-	viper.SetDefault("logger.level", _defaultLogConfig.Level)
-	viper.SetDefault("etcd.address", _defaultEtcdConfig.Address)
-	viper.SetDefault("search_engine.use", _defaultUseSearchEngine)
-	viper.SetDefault("search_engine.elasticsearch.address", _defaultESConfig.Address)
-	viper.SetDefault("search_engine.elasticsearch.username", _defaultESConfig.Username)
-	viper.SetDefault("search_engine.elasticsearch.password", _defaultESConfig.Password)
+	setDefaults(viper.GetViper())
 
 	if err := viper.ReadInConfig(); nil != err {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok || errors.Is(err, fs.ErrNotExist) { //nolint
@@ -143,18 +294,136 @@ func Init(cfgFile string) {
 		}
 	}
 
-	onConfigChanged(fsnotify.Event{Name: "init", Op: fsnotify.Chmod})
+	next, err := load(viper.GetViper())
+	if nil != err {
+		panic(err)
+	}
+
+	_lock.Lock()
+	_config = next
+	_lock.Unlock()
 
 	// set callback.
 	viper.OnConfigChange(onConfigChanged)
 	viper.WatchConfig()
 }
 
+// setDefaults sets env prefix and default settings of v.
+func setDefaults(v *viper.Viper) {
+	v.SetEnvPrefix(_corePrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	// default.
+	v.SetDefault("server.app_port", DefaultAppPort)
+	v.SetDefault("server.app_id", DefaultAppID)
+	v.SetDefault("server.coroutine_pool_size", _defaultAppServer.CoroutinePoolSize)
+	v.SetDefault("server.shutdown_timeout", _defaultAppServer.ShutdownTimeout)
+	v.SetDefault("server.health_check_timeout", _defaultAppServer.HealthCheckTimeout)
+	v.SetDefault("logger.level", _defaultLogConfig.Level)
+	v.SetDefault("etcd.address", _defaultEtcdConfig.Address)
+	v.SetDefault("search_engine.use", _defaultUseSearchEngine)
+	v.SetDefault("search_engine.elasticsearch.address", _defaultESConfig.Address)
+	v.SetDefault("search_engine.elasticsearch.username", _defaultESConfig.Username)
+	v.SetDefault("search_engine.elasticsearch.password", _defaultESConfig.Password)
+	v.SetDefault("search_engine.embedded.path", _defaultEmbeddedConfig.Path)
+	v.SetDefault("reconciler.interval", _defaultReconcileInterval)
+	v.SetDefault("reconciler.orphan_grace", _defaultOrphanGrace)
+	v.SetDefault("tracing.sample_ratio", 1.0)
+	v.SetDefault("flush.tick", _defaultFlushTick)
+	v.SetDefault("audit.pubsub_name", "core-pubsub")
+	v.SetDefault("audit.topic", "core-audit")
+	v.SetDefault("audit.retention", _defaultAuditRetention)
+	v.SetDefault("deletion.retention", _defaultDeletionRetention)
+	v.SetDefault("deletion.purge_interval", _defaultPurgeInterval)
+	v.SetDefault("mapper.resolve_interval", _defaultResolveInterval)
+	v.SetDefault("ingest.window", _defaultIngestWindow)
+	v.SetDefault("ingest.max_batch_size", _defaultIngestBatchSize)
+}
+
+// Reload applies configuration read by viper, rejected if any setting can not be changed at runtime.
+func Reload() error {
+	return reload(viper.GetViper())
+}
+
+// ReloadFrom merges yaml content into config file and reloads them,
+// content is merged into a new viper so that rejected content is not kept.
+func ReloadFrom(content []byte) error {
+	v := viper.New()
+	setDefaults(v)
+	if file := viper.ConfigFileUsed(); file != "" {
+		v.SetConfigFile(file)
+		if err := v.ReadInConfig(); nil != err && !errors.Is(err, fs.ErrNotExist) {
+			return errors.Wrap(err, "read config")
+		}
+	}
+
+	v.SetConfigType("yaml")
+	if err := v.MergeConfig(bytes.NewReader(content)); nil != err {
+		return errors.Wrap(err, "merge config")
+	}
+	return reload(v)
+}
+
+func reload(v *viper.Viper) error {
+	next, err := load(v)
+	if nil != err {
+		return err
+	}
+
+	_lock.Lock()
+	prev := _config
+	if keys := unsafeChanges(prev, next); len(keys) > 0 {
+		_lock.Unlock()
+		return errors.Wrapf(ErrUnsafeChange, "restart required to change %s", strings.Join(keys, ", "))
+	}
+	_config = next
+	handlers := _handlers
+	_lock.Unlock()
+
+	for _, handler := range handlers {
+		handler(prev, next)
+	}
+	return nil
+}
+
+// unsafeChanges returns keys of changed settings which are not applied at runtime.
+func unsafeChanges(prev, next Configuration) []string {
+	var keys []string
+	changedKeys("", reflect.ValueOf(prev), reflect.ValueOf(next), &keys)
+	return keys
+}
+
+func changedKeys(key string, prev, next reflect.Value, keys *[]string) {
+	if _, ok := _liveSettings[key]; ok {
+		return
+	} else if prev.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(prev.Interface(), next.Interface()) {
+			*keys = append(*keys, key)
+		}
+		return
+	}
+
+	for i := 0; i < prev.NumField(); i++ {
+		field := prev.Type().Field(i)
+		name := field.Tag.Get("mapstructure")
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if key != "" {
+			name = key + "." + name
+		}
+		changedKeys(name, prev.Field(i), next.Field(i), keys)
+	}
+}
+
 func SetEtcdBrokers(brokers []string) {
 	for i := 0; i < len(brokers); i++ {
 		brokers[i] = addHTTPScheme(brokers[i])
 	}
-	_config.Etcd.Address = brokers
+	override(func(c *Configuration) {
+		c.Etcd.Address = brokers
+	})
 }
 
 func SetSearchEngineElasticsearchConfig(username, password string, urls []string) {
@@ -162,30 +431,68 @@ func SetSearchEngineElasticsearchConfig(username, password string, urls []string
 		urls[i] = addHTTPScheme(urls[i])
 	}
 
-	_config.SearchEngine.ES.Address = urls
-	_config.SearchEngine.ES.Username = username
-	_config.SearchEngine.ES.Password = password
+	override(func(c *Configuration) {
+		c.SearchEngine.ES.Address = urls
+		c.SearchEngine.ES.Username = username
+		c.SearchEngine.ES.Password = password
+	})
+}
+
+func SetSearchEngineEmbeddedConfig(path string) {
+	override(func(c *Configuration) {
+		c.SearchEngine.Embedded.Path = path
+	})
 }
 
 func SetSearchEngineUseDrive(drive string) {
-	_config.SearchEngine.Use = drive
+	override(func(c *Configuration) {
+		c.SearchEngine.Use = drive
+	})
+}
+
+// override applies fn to configuration, kept after reloading.
+func override(fn func(*Configuration)) {
+	_lock.Lock()
+	defer _lock.Unlock()
+	fn(&_config)
+	_overrides = append(_overrides, fn)
 }
 
 func onConfigChanged(in fsnotify.Event) {
-	_ = viper.Unmarshal(&_config)
-	formatEtcdConfigAddr()
-	formatESAddress()
+	if err := Reload(); nil != err {
+		log.Error("reload config", zap.String("file", in.Name), zap.Error(err))
+		return
+	}
+	log.Info("config reloaded", zap.String("file", in.Name))
+}
+
+// load unmarshals configuration from viper with overrides applied.
+func load(v *viper.Viper) (Configuration, error) {
+	var cfg Configuration
+	if err := v.Unmarshal(&cfg); nil != err {
+		return cfg, errors.Wrap(err, "unmarshal config")
+	}
+
+	formatEtcdConfigAddr(&cfg)
+	formatESAddress(&cfg)
+
+	_lock.RLock()
+	defer _lock.RUnlock()
+	for _, fn := range _overrides {
+		fn(&cfg)
+	}
+	return cfg, nil
 }
 
-func formatEtcdConfigAddr() {
-	for i := 0; i < len(_config.Etcd.Address); i++ {
-		_config.Etcd.Address[i] = addHTTPScheme(_config.Etcd.Address[i])
+func formatEtcdConfigAddr(cfg *Configuration) {
+	for i := 0; i < len(cfg.Etcd.Address); i++ {
+		cfg.Etcd.Address[i] = addHTTPScheme(cfg.Etcd.Address[i])
 	}
 }
 
-func formatESAddress() {
-	for i := 0; i < len(_config.SearchEngine.ES.Address); i++ {
-		_config.SearchEngine.ES.Address[i] = addHTTPScheme(_config.SearchEngine.ES.Address[i])
+func formatESAddress(cfg *Configuration) {
+	for i := 0; i < len(cfg.SearchEngine.ES.Address); i++ {
+		cfg.SearchEngine.ES.Address[i] = addHTTPScheme(cfg.SearchEngine.ES.Address[i])
 	}
 }
 
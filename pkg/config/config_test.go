@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,6 +27,38 @@ func TestInitConfig(t *testing.T) {
 	assert.Equal(t, []string{"http://localhost:8086"}, _config.SearchEngine.ES.Address)
 }
 
+func TestReload(t *testing.T) {
+	Init("../../testdata/testconfig.yml")
+
+	var changed []string
+	OnChange(func(prev, next Configuration) {
+		changed = append(changed, prev.Logger.Level, next.Logger.Level)
+	})
+
+	// safe change.
+	err := ReloadFrom([]byte("logger:\n  level: INFO\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"DEBUG", "INFO"}, changed)
+	assert.Equal(t, "INFO", Get().Logger.Level)
+
+	// unsafe change rejected.
+	err = ReloadFrom([]byte("server:\n  app_port: 6790\n"))
+	assert.True(t, errors.Is(err, ErrUnsafeChange))
+	assert.Equal(t, 6789, Get().Server.AppPort)
+	assert.Len(t, changed, 2)
+
+	// settings applied at startup only are rejected.
+	err = ReloadFrom([]byte("flush:\n  tick: 5s\n"))
+	assert.True(t, errors.Is(err, ErrUnsafeChange))
+	assert.Contains(t, err.Error(), "flush.tick")
+
+	// rejected content is not kept.
+	err = ReloadFrom([]byte("logger:\n  level: WARN\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "WARN", Get().Logger.Level)
+	assert.Equal(t, 6789, Get().Server.AppPort)
+}
+
 func TestAddHTTPScheme(t *testing.T) {
 	tests := []struct {
 		s    string
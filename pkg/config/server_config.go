@@ -25,6 +25,10 @@ type Server struct {
 	AppPort           int              `mapstructure:"app_port"`
 	CoroutinePoolSize int              `mapstructure:"coroutine_pool_size"`
 	TSeriesServers    []*TSeriesServer `mapstructure:"tseries_servers"` //nolint
+	// ShutdownTimeout is the deadline of draining and flushing entities on shutdown.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// HealthCheckTimeout is the deadline of each dependency check of readiness.
+	HealthCheckTimeout time.Duration `mapstructure:"health_check_timeout"`
 }
 
 type TSeriesServer struct {
@@ -17,6 +17,8 @@ limitations under the License.
 package constraint
 
 import (
+	"time"
+
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 )
@@ -33,6 +35,13 @@ const (
 	DefineFieldArrayLength  = "length"
 	DefineFieldArrayElemCfg = "elem_type"
 	DefineFieldStructFields = "fields"
+	// DefineFieldHistory is the number of last values kept for the property.
+	DefineFieldHistory = "history"
+	// DefineFieldUpdateInterval is expected update interval of the property,
+	// seconds or duration string, e.g. 30 or "30s".
+	DefineFieldUpdateInterval = "update_interval"
+
+	MaxHistorySize = 1000
 )
 
 type Config struct {
@@ -63,6 +72,50 @@ func (cfg *Config) getStructDefine() DefineStruct {
 	return DefineStruct{Fields: fields}
 }
 
+// HistorySize returns number of last values kept for the property, 0 if disabled.
+func (cfg *Config) HistorySize() int {
+	var size int
+	switch val := cfg.Define[DefineFieldHistory].(type) {
+	case int:
+		size = val
+	case int64:
+		size = int(val)
+	case uint64:
+		size = int(val)
+	case float64:
+		size = int(val)
+	}
+
+	if size > MaxHistorySize {
+		size = MaxHistorySize
+	} else if size < 0 {
+		size = 0
+	}
+	return size
+}
+
+// UpdateInterval returns expected update interval of the property, 0 if not defined.
+func (cfg *Config) UpdateInterval() time.Duration {
+	var interval time.Duration
+	switch val := cfg.Define[DefineFieldUpdateInterval].(type) {
+	case int:
+		interval = time.Duration(val) * time.Second
+	case int64:
+		interval = time.Duration(val) * time.Second
+	case uint64:
+		interval = time.Duration(val) * time.Second
+	case float64:
+		interval = time.Duration(val * float64(time.Second))
+	case string:
+		interval, _ = time.ParseDuration(val)
+	}
+
+	if interval < 0 {
+		interval = 0
+	}
+	return interval
+}
+
 func (cfg *Config) GetConfig(segs []string, index int) (int, *Config, error) {
 	return cfg.getConfig(segs, index)
 }
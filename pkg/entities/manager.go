@@ -19,24 +19,30 @@ package entities
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	dapr "github.com/dapr/go-sdk/client"
-	elastic "github.com/olivere/elastic/v7"
 	"github.com/pkg/errors"
 	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/audit"
 	"github.com/tkeel-io/core/pkg/config"
 	"github.com/tkeel-io/core/pkg/constraint"
 	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/purger"
 	"github.com/tkeel-io/core/pkg/runtime"
 	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/tenant"
 	"github.com/tkeel-io/core/pkg/tql"
+	"github.com/tkeel-io/core/pkg/tracing"
 	"github.com/tkeel-io/core/pkg/util"
 	"github.com/tkeel-io/kit/log"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 const EntityStateName = "core-state"
@@ -48,6 +54,8 @@ type entityManager struct {
 	etcdClient   *clientv3.Client
 	searchClient pb.SearchHTTPServer
 	stateManager *runtime.Manager
+	quotas       *tenant.Quotas
+	auditor      *audit.Auditor
 
 	lock   sync.RWMutex
 	ctx    context.Context
@@ -59,6 +67,8 @@ func NewEntityManager(ctx context.Context, mgr *runtime.Manager, searchClient pb
 		err        error
 		daprClient dapr.Client
 		etcdClient *clientv3.Client
+		auditSink  audit.Sink
+		auditStore audit.Store
 	)
 
 	if daprClient, err = dapr.NewClient(); nil != err {
@@ -68,6 +78,10 @@ func NewEntityManager(ctx context.Context, mgr *runtime.Manager, searchClient pb
 		DialTimeout: 3 * time.Second,
 	}); nil != err {
 		return nil, errors.Wrap(err, "create manager failed")
+	} else if auditSink, err = audit.NewSink(config.Get().Audit, daprClient); nil != err {
+		return nil, errors.Wrap(err, "create manager failed")
+	} else if auditStore, err = audit.NewStore(config.Get().Audit, etcdClient); nil != err {
+		return nil, errors.Wrap(err, "create manager failed")
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -79,6 +93,8 @@ func NewEntityManager(ctx context.Context, mgr *runtime.Manager, searchClient pb
 		daprClient:   daprClient,
 		etcdClient:   etcdClient,
 		searchClient: searchClient,
+		quotas:       tenant.NewQuotas(config.Get().Tenant, tenant.NewEtcdCounter(etcdClient)),
+		auditor:      audit.NewAuditor(config.Get().Server.AppID, auditSink, auditStore),
 		lock:         sync.RWMutex{},
 	}, nil
 }
@@ -88,9 +104,27 @@ func (m *entityManager) Start() error {
 	return errors.Wrap(nil, "start entity manager")
 }
 
-func (m *entityManager) OnMessage(ctx context.Context, msgCtx statem.MessageContext) {
+func (m *entityManager) Shutdown(ctx context.Context) (runtime.DrainReport, error) {
+	report, err := m.stateManager.Shutdown(ctx)
+	return report, errors.Wrap(err, "shutdown entity manager")
+}
+
+func (m *entityManager) OnMessage(ctx context.Context, msgCtx statem.MessageContext) error {
+	// stop accepting messages while shutting down.
+	if !m.stateManager.Accepting() {
+		return runtime.ErrManagerShutdown
+	}
+
+	// check message rate of tenant.
+	if err := m.quotas.AllowMessage(msgCtx.Headers.GetOwner()); nil != err {
+		log.Warn("drop message", zap.Error(err), logger.EntityID(msgCtx.Headers.GetTargetID()),
+			zap.String("owner", msgCtx.Headers.GetOwner()))
+		return errors.Wrap(err, "handle message")
+	}
+
 	// 接受来自 pubsub 的消息，这些消息将触发 实体 运行时.
 	m.stateManager.SendMsg(msgCtx)
+	return nil
 }
 
 // ------------------------------------APIs-----------------------------.
@@ -123,6 +157,12 @@ func (m *entityManager) CreateEntity(ctx context.Context, base *statem.Base) (*s
 		return nil, err
 	}
 
+	// check entity quota of tenant.
+	if err = m.quotas.CheckEntities(ctx, base.Owner); nil != err {
+		log.Error("create entity", zap.Error(err), logger.EntityID(base.ID), zap.String("owner", base.Owner))
+		return nil, errors.Wrap(err, "create entity")
+	}
+
 	// 2. check template id.
 	tid, _ := ctx.Value(TemplateEntityID{}).(string)
 	if tid != "" {
@@ -147,6 +187,13 @@ func (m *entityManager) CreateEntity(ctx context.Context, base *statem.Base) (*s
 
 	log.Debug("create entity state", logger.EntityID(base.ID), zap.String("template", tid), zap.String("state", string(bytes)))
 
+	// register entity, used by reconciler.
+	if _, err = m.etcdClient.Put(ctx, util.FormatEntity(base.ID), base.Type); nil != err {
+		log.Warn("register entity", zap.Error(err), logger.EntityID(base.ID))
+	} else if _, err = m.etcdClient.Put(ctx, util.FormatTenantEntity(base.Owner, base.ID), base.Type); nil != err {
+		log.Warn("register tenant entity", zap.Error(err), logger.EntityID(base.ID))
+	}
+
 	// 3. 向实体发送消息，来在某一个节点上拉起实体，执行实体运行时过程.
 	msgCtx := statem.MessageContext{
 		Headers: statem.Header{},
@@ -160,47 +207,117 @@ func (m *entityManager) CreateEntity(ctx context.Context, base *statem.Base) (*s
 	msgCtx.Headers.SetTargetID(base.ID)
 	msgCtx.Headers.SetSource(base.Source)
 	msgCtx.Headers.Set(statem.MessageCtxHeaderType, base.Type)
+	tracing.Inject(ctx, msgCtx.Headers)
 	m.stateManager.SendMsg(msgCtx)
 
+	m.auditor.Record(ctx, audit.EventTypeEntityCreated, nil, base)
 	return base, nil
 }
 
-// DeleteEntity delete an entity from manager.
+// DeleteEntity soft-deletes an entity, the entity is restorable until purged after retention.
 func (m *entityManager) DeleteEntity(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
-	// 1. delete from elasticsearch.
-	if _, err = m.searchClient.DeleteByID(ctx, &pb.DeleteByIDRequest{Id: en.ID}); nil != err {
+	// 1. mark deleted in runtime and state.
+	if _, err = m.getEntityFromState(ctx, en); nil != err {
 		log.Error("delete entity", zap.Error(err), logger.EntityID(en.ID))
-		if elastic.IsNotFound(err) {
-			return nil, errors.Wrap(err, "delete entity from es state")
-		}
+		return nil, errors.Wrap(err, "delete entity")
+	} else if base, err = m.stateManager.DeleteStateMarchin(ctx, en); nil != err {
+		log.Error("delete entity runtime", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "delete entity from runtime")
 	}
 
-	// 2. delete from runtime.
-	if base, err = m.stateManager.DeleteStateMarchin(ctx, en); nil != err {
-		log.Error("delete entity runtime", zap.Error(err), logger.EntityID(en.ID))
-		if base, err = m.getEntityFromState(ctx, en); nil != err {
-			log.Error("get entity", zap.Error(err), logger.EntityID(en.ID))
-			return nil, errors.Wrap(err, "delete entity from runtime")
+	// 2. keep tombstone until purged.
+	var bytes []byte
+	tombstone := purger.Tombstone{ID: base.ID, Type: base.Type, Owner: base.Owner, DeletedAt: base.DeletedAt}
+	if bytes, err = json.Marshal(tombstone); nil != err {
+		return nil, errors.Wrap(err, "delete entity")
+	} else if _, err = m.etcdClient.Put(ctx, util.FormatDeleted(base.ID), string(bytes)); nil != err {
+		log.Error("delete entity", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "delete entity")
+	}
+
+	// 3. delete from search index, removed by reconciler if failed.
+	if _, err = m.searchClient.DeleteByID(ctx, &pb.DeleteByIDRequest{Id: en.ID}); nil != err {
+		log.Error("delete entity document", zap.Error(err), logger.EntityID(en.ID))
+	}
+
+	// 4. stop mappers, kept in entity state for restoring.
+	for _, mm := range base.Mappers {
+		if _, err = m.etcdClient.Delete(ctx, util.FormatMapper(base.Type, base.ID, mm.Name)); nil != err {
+			log.Error("delete entity mapper", zap.Error(err), logger.EntityID(en.ID), zap.Any("mapper", mm))
 		}
 	}
 
-	// 3. delete from state.
-	if err = m.daprClient.DeleteState(ctx, EntityStateName, en.ID); nil != err {
-		log.Error("delete entity", zap.Error(err), logger.EntityID(en.ID))
-		return nil, errors.Wrap(err, "delete entity from state")
+	// 5. unregister entity.
+	if _, err = m.etcdClient.Delete(ctx, util.FormatEntity(en.ID)); nil != err {
+		log.Error("unregister entity", zap.Error(err), logger.EntityID(en.ID))
+	} else if _, err = m.etcdClient.Delete(ctx, util.FormatTenantEntity(base.Owner, en.ID)); nil != err {
+		log.Error("unregister tenant entity", zap.Error(err), logger.EntityID(en.ID))
 	}
 
-	// 4. delete tql from etcd.
+	// 6. log record.
+	log.Info("delete entity", logger.EntityID(en.ID), zap.Int64("deleted_at", base.DeletedAt), zap.Any("entity", base))
+	m.auditor.Record(ctx, audit.EventTypeEntityDeleted, base, nil)
+
+	return base, nil
+}
+
+// RestoreEntity restores a soft-deleted entity before it is purged.
+func (m *entityManager) RestoreEntity(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
+	// 1. check entity deleted, entity of another owner is invisible.
+	if base, err = m.loadEntity(ctx, en.ID); nil != err {
+		log.Error("restore entity", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "restore entity")
+	} else if base.Owner != en.Owner {
+		return nil, ErrEntityNotFound
+	} else if base.DeletedAt == 0 {
+		return nil, ErrEntityNotDeleted
+	} else if err = m.quotas.CheckEntities(ctx, base.Owner); nil != err {
+		log.Error("restore entity", zap.Error(err), logger.EntityID(en.ID), zap.String("owner", base.Owner))
+		return nil, errors.Wrap(err, "restore entity")
+	}
+
+	// 2. clear deletion mark.
+	var bytes []byte
+	base.DeletedAt = 0
+	if bytes, err = statem.EncodeBase(base); nil != err {
+		return nil, errors.Wrap(err, "restore entity")
+	} else if err = m.daprClient.SaveState(ctx, EntityStateName, base.ID, bytes); nil != err {
+		log.Error("restore entity", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "restore entity")
+	}
+
+	// 3. register entity.
+	if _, err = m.etcdClient.Put(ctx, util.FormatEntity(base.ID), base.Type); nil != err {
+		log.Warn("register entity", zap.Error(err), logger.EntityID(base.ID))
+	} else if _, err = m.etcdClient.Put(ctx, util.FormatTenantEntity(base.Owner, base.ID), base.Type); nil != err {
+		log.Warn("register tenant entity", zap.Error(err), logger.EntityID(base.ID))
+	}
+
+	// 4. index entity, re-indexed by reconciler if failed.
+	var val *structpb.Value
+	if val, err = structpb.NewValue(base.SearchValues()); nil != err {
+		log.Error("index entity", zap.Error(err), logger.EntityID(base.ID))
+	} else if _, err = m.searchClient.Index(ctx, &pb.IndexObject{Obj: val}); nil != err {
+		log.Error("index entity", zap.Error(err), logger.EntityID(base.ID))
+	}
+
+	// 5. start mappers, the entity is loaded by mapper watching.
 	for _, mm := range base.Mappers {
-		if _, err = m.etcdClient.Delete(ctx, util.FormatMapper(base.Type, base.ID, mm.Name), clientv3.WithPrefix()); nil != err {
-			log.Error("delete entity mapper", zap.Error(err), logger.EntityID(en.ID), zap.Any("mapper", base.Mappers))
+		if _, err = m.etcdClient.Put(ctx, util.FormatMapper(base.Type, base.ID, mm.Name), mm.TQLString); nil != err {
+			log.Error("restore mapper", zap.Error(err), logger.EntityID(base.ID), zap.Any("mapper", mm))
+			return nil, errors.Wrap(err, "restore mapper")
 		}
 	}
 
-	// 5. log record.
-	log.Info("delete entity", logger.EntityID(en.ID), zap.Any("entity", base))
+	// 6. remove tombstone.
+	if _, err = m.etcdClient.Delete(ctx, util.FormatDeleted(base.ID)); nil != err {
+		log.Error("restore entity", zap.Error(err), logger.EntityID(base.ID))
+		return nil, errors.Wrap(err, "restore entity")
+	}
 
-	return base, errors.Wrap(err, "delete entity")
+	log.Info("restore entity", logger.EntityID(base.ID))
+	m.auditor.Record(ctx, audit.EventTypeEntityRestored, nil, base)
+	return base, nil
 }
 
 // GetProperties returns statem.Base.
@@ -211,9 +328,17 @@ func (m *entityManager) GetProperties(ctx context.Context, en *statem.Base) (bas
 	return base, errors.Wrap(err, "entity GetProperties")
 }
 
+// getEntityFromState returns entity from state store, soft-deleted entity is not returned.
 func (m *entityManager) getEntityFromState(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
+	if base, err = m.loadEntity(ctx, en.ID); nil == err && base.DeletedAt > 0 {
+		return nil, ErrEntityDeleted
+	}
+	return
+}
+
+func (m *entityManager) loadEntity(ctx context.Context, id string) (base *statem.Base, err error) {
 	var item *dapr.StateItem
-	if item, err = m.daprClient.GetState(ctx, EntityStateName, en.ID); nil != err {
+	if item, err = m.daprClient.GetState(ctx, EntityStateName, id); nil != err {
 		return
 	} else if nil == item || len(item.Value) == 0 {
 		return nil, ErrEntityNotFound
@@ -226,6 +351,11 @@ func (m *entityManager) getEntityFromState(ctx context.Context, en *statem.Base)
 
 // SetProperties set properties into entity.
 func (m *entityManager) SetProperties(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
+	if err = m.quotas.AllowMessage(en.Owner); nil != err {
+		log.Error("set entity properties", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "set entity properties")
+	}
+
 	// TODO：这里的调用其实是可能通过entity-manager的proxy的同步调用，这个可以设置可选项.
 	if err = m.stateManager.SetProperties(ctx, en); nil != err {
 		log.Error("set entity properties", zap.Error(err), logger.EntityID(en.ID))
@@ -237,6 +367,11 @@ func (m *entityManager) SetProperties(ctx context.Context, en *statem.Base) (bas
 }
 
 func (m *entityManager) PatchEntity(ctx context.Context, en *statem.Base, patchData []*pb.PatchData) (base *statem.Base, err error) {
+	if err = m.quotas.AllowMessage(en.Owner); nil != err {
+		log.Error("patch entity", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "patch entity properties")
+	}
+
 	if err = m.stateManager.PatchEntity(ctx, en, patchData); nil != err {
 		log.Error("patch entity", zap.Error(err), logger.EntityID(en.ID))
 		return nil, errors.Wrap(err, "patch entity properties")
@@ -256,14 +391,18 @@ func (m *entityManager) PatchEntity(ctx context.Context, en *statem.Base, patchD
 // AppendMapper append a mapper into entity.
 func (m *entityManager) AppendMapper(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
 	// 1. 判断实体是否存在.
-	if _, err = m.daprClient.GetState(ctx, EntityStateName, en.ID); nil != err {
+	if base, err = m.getEntityFromState(ctx, en); nil != err {
 		log.Error("append mapper", zap.Error(err), logger.EntityID(en.ID))
 		return nil, errors.Wrap(err, "get state")
 	}
+	before := base
 
 	// check TQLs.
 	if err = checkTQLs(en); nil != err {
 		return nil, errors.Wrap(err, "check subscription")
+	} else if err = m.checkMapperOwner(ctx, base.Owner, en); nil != err {
+		log.Error("append mapper", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "check mapper owner")
 	}
 
 	// 2. 将 mapper 推到 etcd.
@@ -280,6 +419,7 @@ func (m *entityManager) AppendMapper(ctx context.Context, en *statem.Base) (base
 	// 	log.Error("append mapper", zap.Error(err), logger.EntityID(en.ID))
 	// 	return nil, errors.Wrap(err, "append mapper")
 	// }
+	m.auditor.Record(ctx, audit.EventTypeMapperAppended, before, withMappers(before, en.Mappers, nil))
 	base, err = m.getEntityFromState(ctx, en)
 	return base, errors.Wrap(err, "append mapper")
 }
@@ -287,7 +427,8 @@ func (m *entityManager) AppendMapper(ctx context.Context, en *statem.Base) (base
 // DeleteMapper delete mapper from entity.
 func (m *entityManager) RemoveMapper(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
 	// 1. 判断实体是否存在.
-	if _, err = m.daprClient.GetState(ctx, EntityStateName, en.ID); nil != err {
+	var before *statem.Base
+	if before, err = m.getEntityFromState(ctx, en); nil != err && !errors.Is(err, ErrEntityNotFound) {
 		log.Error("remove mapper", zap.Error(err), logger.EntityID(en.ID))
 		return nil, errors.Wrap(err, "remove mapper")
 	}
@@ -305,6 +446,10 @@ func (m *entityManager) RemoveMapper(ctx context.Context, en *statem.Base) (base
 		return nil, errors.Wrap(err, "remove mapper")
 	}
 
+	if nil != before {
+		m.auditor.Record(ctx, audit.EventTypeMapperRemoved, before, withMappers(before, nil, en.Mappers))
+	}
+
 	base, err = m.getEntityFromState(ctx, en)
 	return base, errors.Wrap(err, "remove mapper")
 }
@@ -331,17 +476,21 @@ func (m *entityManager) CheckSubscription(ctx context.Context, en *statem.Base)
 
 // SetProperties set properties into entity.
 func (m *entityManager) SetConfigs(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
+	before, _ := m.getEntityFromState(ctx, en)
 	if err = m.stateManager.SetConfigs(ctx, en); nil != err {
 		log.Error("set entity configs", zap.Error(err), logger.EntityID(en.ID))
 		return nil, errors.Wrap(err, "set entity configs")
 	}
 
-	base, err = m.getEntityFromState(ctx, en)
+	if base, err = m.getEntityFromState(ctx, en); nil == err {
+		m.auditor.Record(ctx, audit.EventTypeConfigsChanged, before, base)
+	}
 	return base, errors.Wrap(err, "set entity configs")
 }
 
 // PatchConfigs patch properties into entity.
 func (m *entityManager) PatchConfigs(ctx context.Context, en *statem.Base, patchData []*statem.PatchData) (base *statem.Base, err error) {
+	before, _ := m.getEntityFromState(ctx, en)
 	if err = m.stateManager.PatchConfigs(ctx, en, patchData); nil != err {
 		log.Error("patch entity configs", zap.Error(err), logger.EntityID(en.ID))
 		return nil, errors.Wrap(err, "patch entity configs")
@@ -351,6 +500,7 @@ func (m *entityManager) PatchConfigs(ctx context.Context, en *statem.Base, patch
 		log.Error("patch entity configs", zap.Error(err), logger.EntityID(en.ID))
 		return nil, errors.Wrap(err, "patch entity configs")
 	}
+	m.auditor.Record(ctx, audit.EventTypeConfigsChanged, before, base)
 
 	for _, pd := range patchData {
 		if pd.Operator == constraint.PatchOpCopy {
@@ -367,26 +517,120 @@ func (m *entityManager) PatchConfigs(ctx context.Context, en *statem.Base, patch
 
 // AppendConfigs append entity configs.
 func (m *entityManager) AppendConfigs(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
+	before, _ := m.getEntityFromState(ctx, en)
 	if err = m.stateManager.AppendConfigs(ctx, en); nil != err {
 		log.Error("append entity configs", zap.Error(err), logger.EntityID(en.ID))
 		return nil, errors.Wrap(err, "append entity configs")
 	}
 
-	base, err = m.getEntityFromState(ctx, en)
+	if base, err = m.getEntityFromState(ctx, en); nil == err {
+		m.auditor.Record(ctx, audit.EventTypeConfigsChanged, before, base)
+	}
 	return base, errors.Wrap(err, "append entity configs")
 }
 
 // RemoveConfigs remove entity configs.
 func (m *entityManager) RemoveConfigs(ctx context.Context, en *statem.Base, propertyIDs []string) (base *statem.Base, err error) {
+	before, _ := m.getEntityFromState(ctx, en)
 	if err = m.stateManager.RemoveConfigs(ctx, en, propertyIDs); nil != err {
 		log.Error("remove entity configs", zap.Error(err), logger.EntityID(en.ID))
 		return nil, errors.Wrap(err, "remove entity configs")
 	}
 
-	base, err = m.getEntityFromState(ctx, en)
+	if base, err = m.getEntityFromState(ctx, en); nil == err {
+		m.auditor.Record(ctx, audit.EventTypeConfigsChanged, before, base)
+	}
 	return base, errors.Wrap(err, "remove entity configs")
 }
 
+// AddRelationship adds relationship from entity to target entity of the same owner.
+func (m *entityManager) AddRelationship(ctx context.Context, en *statem.Base, rel statem.Relationship) (base *statem.Base, err error) {
+	var before, target *statem.Base
+	if rel.Type == "" || strings.ContainsAny(rel.Type, ". ") || rel.Target == "" || rel.Target == en.ID {
+		return nil, errors.Wrapf(ErrRelationshipInvalid, "type %q, target %q", rel.Type, rel.Target)
+	} else if before, err = m.getEntityFromState(ctx, en); nil != err {
+		log.Error("add relationship", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "add relationship")
+	} else if target, err = m.getEntityFromState(ctx, &statem.Base{ID: rel.Target}); nil != err {
+		log.Error("add relationship, get target", zap.Error(err), logger.EntityID(en.ID), zap.String("target", rel.Target))
+		return nil, errors.Wrap(err, "add relationship, get target")
+	} else if target.Owner != before.Owner {
+		log.Error("add relationship", zap.Error(ErrRelationshipTenant), logger.EntityID(en.ID), zap.String("target", rel.Target))
+		return nil, ErrRelationshipTenant
+	}
+
+	if err = m.stateManager.SetRelationship(ctx, en, rel); nil != err {
+		log.Error("add relationship", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "add relationship")
+	}
+
+	if base, err = m.getEntityFromState(ctx, en); nil == err {
+		m.auditor.Record(ctx, audit.EventTypeRelationshipAdded, before, base)
+	}
+	return base, errors.Wrap(err, "add relationship")
+}
+
+// RemoveRelationship removes relationship from entity.
+func (m *entityManager) RemoveRelationship(ctx context.Context, en *statem.Base, typ, target string) (base *statem.Base, err error) {
+	var before *statem.Base
+	if before, err = m.getEntityFromState(ctx, en); nil != err {
+		log.Error("remove relationship", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "remove relationship")
+	} else if err = m.stateManager.RemoveRelationship(ctx, en, typ, target); nil != err {
+		log.Error("remove relationship", zap.Error(err), logger.EntityID(en.ID),
+			zap.String("type", typ), zap.String("target", target))
+		return nil, errors.Wrap(err, "remove relationship")
+	}
+
+	if base, err = m.getEntityFromState(ctx, en); nil == err {
+		m.auditor.Record(ctx, audit.EventTypeRelationshipRemoved, before, base)
+	}
+	return base, errors.Wrap(err, "remove relationship")
+}
+
+// SetTimer sets timer of entity, properties of timer are delivered into entity when fired.
+func (m *entityManager) SetTimer(ctx context.Context, en *statem.Base, timer statem.Timer) (*statem.Timer, error) {
+	base, err := m.getEntityFromState(ctx, en)
+	if nil != err {
+		log.Error("set timer", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "set timer")
+	}
+
+	timer.EntityID, timer.Owner = base.ID, base.Owner
+	if err = m.stateManager.SetTimer(ctx, timer); nil != err {
+		log.Error("set timer", zap.Error(err), logger.EntityID(en.ID), zap.String("timer", timer.ID))
+		return nil, errors.Wrap(err, "set timer")
+	}
+
+	for _, item := range m.stateManager.ListTimers(base.ID) {
+		if item.ID == timer.ID {
+			return &item, nil
+		}
+	}
+	return &timer, nil
+}
+
+// CancelTimer removes timer of entity.
+func (m *entityManager) CancelTimer(ctx context.Context, en *statem.Base, timerID string) error {
+	if _, err := m.getEntityFromState(ctx, en); nil != err {
+		log.Error("cancel timer", zap.Error(err), logger.EntityID(en.ID))
+		return errors.Wrap(err, "cancel timer")
+	} else if err = m.stateManager.CancelTimer(ctx, en.ID, timerID); nil != err {
+		log.Error("cancel timer", zap.Error(err), logger.EntityID(en.ID), zap.String("timer", timerID))
+		return errors.Wrap(err, "cancel timer")
+	}
+	return nil
+}
+
+// ListTimers returns timers of entity.
+func (m *entityManager) ListTimers(ctx context.Context, en *statem.Base) ([]statem.Timer, error) {
+	if _, err := m.getEntityFromState(ctx, en); nil != err {
+		log.Error("list timers", zap.Error(err), logger.EntityID(en.ID))
+		return nil, errors.Wrap(err, "list timers")
+	}
+	return m.stateManager.ListTimers(en.ID), nil
+}
+
 // QueryConfigs query entity configs.
 func (m *entityManager) QueryConfigs(ctx context.Context, en *statem.Base, propertyIDs []string) (base *statem.Base, err error) {
 	base, err = m.getEntityFromState(ctx, en)
@@ -402,6 +646,58 @@ func (m *entityManager) QueryConfigs(ctx context.Context, en *statem.Base, prope
 	return &baseEntity, errors.Wrap(err, "remove entity configs")
 }
 
+// withMappers returns copy of base with mappers appended or replaced by name, and removed.
+func withMappers(base *statem.Base, appended, removed []statem.MapperDesc) *statem.Base {
+	names := make(map[string]struct{})
+	for _, mm := range appended {
+		names[mm.Name] = struct{}{}
+	}
+	for _, mm := range removed {
+		names[mm.Name] = struct{}{}
+	}
+
+	after := base.Copy()
+	after.Mappers = nil
+	for _, mm := range base.Mappers {
+		if _, ok := names[mm.Name]; !ok {
+			after.Mappers = append(after.Mappers, mm)
+		}
+	}
+	after.Mappers = append(after.Mappers, appended...)
+	return &after
+}
+
+// checkMapperOwner forbids mappers reading entities of another owner,
+// source entities must exist so that the owner can be checked.
+func (m *entityManager) checkMapperOwner(ctx context.Context, owner string, en *statem.Base) error {
+	for _, mm := range en.Mappers {
+		tqlInst, err := tql.NewTQL(mm.TQLString)
+		if nil != err {
+			return errors.Wrap(err, "parse TQL")
+		}
+
+		for _, entityID := range tqlInst.Entities() {
+			if entityID == en.ID {
+				continue
+			}
+
+			source, err := m.getEntityFromState(ctx, &statem.Base{ID: entityID})
+			if errors.Is(err, ErrEntityNotFound) || errors.Is(err, ErrEntityDeleted) {
+				log.Error("mapper reads entity not existed", logger.EntityID(en.ID),
+					zap.String("source", entityID), zap.Any("mapper", mm))
+				return errors.Wrap(ErrMapperSourceMissing, entityID)
+			} else if nil != err {
+				return errors.Wrap(err, "get source entity")
+			} else if source.Owner != owner {
+				log.Error("mapper reads entity of another owner", logger.EntityID(en.ID),
+					zap.String("source", entityID), zap.Any("mapper", mm))
+				return ErrMapperCrossTenant
+			}
+		}
+	}
+	return nil
+}
+
 func checkTQLs(en *statem.Base) error {
 	// check TQL.
 	var err error
@@ -21,6 +21,7 @@ import (
 	"errors"
 
 	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/runtime"
 	"github.com/tkeel-io/core/pkg/statem"
 )
 
@@ -30,17 +31,27 @@ var (
 	ErrMapperTQLInvalid    = errors.New("invalid TQL")
 	ErrEntityNotFound      = errors.New("not found")
 	ErrEntityAreadyExisted = errors.New("entity already existed")
+	ErrMapperCrossTenant   = errors.New("mapper reads entity of another owner")
+	ErrMapperSourceMissing = errors.New("mapper reads entity not existed")
+	ErrEntityDeleted       = errors.New("entity deleted, restore or purge it first")
+	ErrEntityNotDeleted    = errors.New("entity not deleted")
+	ErrRelationshipInvalid = errors.New("invalid relationship")
+	ErrRelationshipTenant  = errors.New("relationship targets entity of another owner")
 )
 
 type EntityManager interface {
 	// Start start Entity manager.
 	Start() error
+	// Shutdown drains messages and flushes entities.
+	Shutdown(ctx context.Context) (runtime.DrainReport, error)
 	// OnMessage handle message.
-	OnMessage(ctx context.Context, msgCtx statem.MessageContext)
+	OnMessage(ctx context.Context, msgCtx statem.MessageContext) error
 	// CreateEntity create entity.
 	CreateEntity(ctx context.Context, base *statem.Base) (*statem.Base, error)
-	// DeleteEntity delete entity.
+	// DeleteEntity soft-deletes entity, restorable until purged.
 	DeleteEntity(ctx context.Context, en *statem.Base) (base *statem.Base, err error)
+	// RestoreEntity restores soft-deleted entity.
+	RestoreEntity(ctx context.Context, en *statem.Base) (base *statem.Base, err error)
 	// GetProperties returns entity properties.
 	GetProperties(ctx context.Context, en *statem.Base) (base *statem.Base, err error)
 	// SetProperties set entity properties.
@@ -61,6 +72,16 @@ type EntityManager interface {
 	AppendConfigs(ctx context.Context, en *statem.Base) (base *statem.Base, err error)
 	// RemoveConfigs remove entity configs.
 	RemoveConfigs(ctx context.Context, en *statem.Base, propertyIDs []string) (base *statem.Base, err error)
+	// AddRelationship adds or replaces relationship from entity.
+	AddRelationship(ctx context.Context, en *statem.Base, rel statem.Relationship) (base *statem.Base, err error)
+	// RemoveRelationship removes relationship from entity.
+	RemoveRelationship(ctx context.Context, en *statem.Base, typ, target string) (base *statem.Base, err error)
+	// SetTimer sets timer of entity, replaces the existing one with the same id.
+	SetTimer(ctx context.Context, en *statem.Base, timer statem.Timer) (*statem.Timer, error)
+	// CancelTimer removes timer of entity.
+	CancelTimer(ctx context.Context, en *statem.Base, timerID string) error
+	// ListTimers returns timers of entity.
+	ListTimers(ctx context.Context, en *statem.Base) ([]statem.Timer, error)
 	// QueryConfigs returns entity configs.
 	QueryConfigs(ctx context.Context, en *statem.Base, propertyIDs []string) (base *statem.Base, err error)
 }
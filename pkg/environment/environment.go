@@ -17,6 +17,9 @@ limitations under the License.
 package environment
 
 import (
+	"reflect"
+	"sync"
+
 	"github.com/pkg/errors"
 	"github.com/tkeel-io/core/pkg/mapper"
 	"github.com/tkeel-io/kit/log"
@@ -41,6 +44,7 @@ func newMapperCache() *MapperCache {
 
 type Environment struct {
 	mapperCaches map[string]*MapperCache // map[stateID]MapperCache
+	lock         sync.RWMutex
 }
 
 // NewEnvironment returns *Environment.
@@ -52,6 +56,9 @@ func NewEnvironment() *Environment {
 
 // GetActorEnv returns Actor Environments.
 func (env *Environment) GetActorEnv(stateID string) ActorEnv {
+	env.lock.RLock()
+	defer env.lock.RUnlock()
+
 	var actorEnv ActorEnv
 	if mCache, has := env.mapperCaches[stateID]; has {
 		for _, m := range mCache.mappers {
@@ -70,6 +77,9 @@ func (env *Environment) StoreMappers(pairs []EtcdPair) []MaSummary {
 		info MaSummary
 	)
 
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
 	result := make([]MaSummary, 0)
 	for _, pair := range pairs {
 		log.Debug("load mapper", zap.String("key", pair.Key), zap.String("value", string(pair.Value)))
@@ -99,6 +109,9 @@ func (env *Environment) OnMapperChanged(op mvccpb.Event_EventType, pair EtcdPair
 		effects []string
 	)
 
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
 	switch op {
 	case mvccpb.PUT:
 		var mapperInstence mapper.Mapper
@@ -110,6 +123,12 @@ func (env *Environment) OnMapperChanged(op mvccpb.Event_EventType, pair EtcdPair
 			return effects, errors.Wrap(err, "mapper changed")
 		}
 
+		// keep resolved references until resolved again.
+		if mCache, has := env.mapperCaches[mapperInstence.TargetEntity()]; has {
+			if prev, exists := mCache.mappers[mapperInstence.ID()]; exists {
+				mapperInstence.Resolve(prev.Resolved())
+			}
+		}
 		effects = env.addMapper(mapperInstence)
 	case mvccpb.DELETE:
 		log.Debug("tql deleted", zap.String("tql.Key", pair.Key), zap.String("tql.Val", string(pair.Value)))
@@ -132,26 +151,38 @@ func (env *Environment) OnMapperChanged(op mvccpb.Event_EventType, pair EtcdPair
 	return effects, nil
 }
 
-// generateCleanHandler generate clean-handler for mapper.
-func (env *Environment) generateCleanHandler(stateID, mapperID string, tentacleIDs []string) CleanHandler {
+// generateCleanHandler generate clean-handler for mapper, tentacles are map[tentacleID]stateID
+// of state machines holding them, returns state machines whose tentacles removed.
+func (env *Environment) generateCleanHandler(stateID, mapperID string, tentacles map[string]string) CleanHandler {
 	return func() []string {
 		var targets []string
-		if mCache, ok := env.mapperCaches[stateID]; ok {
-			for _, id := range tentacleIDs {
-				if tentacle, ok := mCache.tentacles[id]; ok {
-					delete(mCache.tentacles, id)
-					if tentacle.Type() == mapper.TentacleTypeEntity {
-						targets = append(targets, tentacle.TargetID())
-					}
+		for tentacleID, holderID := range tentacles {
+			if mCache, ok := env.mapperCaches[holderID]; ok {
+				delete(mCache.tentacles, tentacleID)
+				if holderID != stateID {
+					targets = append(targets, holderID)
+					env.removeEmptyCache(holderID)
 				}
 			}
+		}
+
+		if mCache, ok := env.mapperCaches[stateID]; ok {
 			delete(mCache.mappers, mapperID)
-			return targets
+			delete(mCache.cleanHandlers, mapperID)
 		}
 		return targets
 	}
 }
 
+// removeEmptyCache removes cache of state machine without mappers and tentacles.
+func (env *Environment) removeEmptyCache(stateID string) {
+	if mCache, ok := env.mapperCaches[stateID]; ok {
+		if len(mCache.mappers) == 0 && len(mCache.tentacles) == 0 {
+			delete(env.mapperCaches, stateID)
+		}
+	}
+}
+
 // addMapper add mapper into Environment.
 func (env *Environment) addMapper(m mapper.Mapper) (effects []string) {
 	// check mapper exists.
@@ -160,7 +191,7 @@ func (env *Environment) addMapper(m mapper.Mapper) (effects []string) {
 		env.mapperCaches[targetID] = newMapperCache()
 	}
 
-	tentacleIDs := make([]string, 0)
+	tentacles := make(map[string]string)
 	mCache := env.mapperCaches[targetID]
 	if _, exists := mCache.mappers[m.ID()]; exists {
 		effects = mCache.cleanHandlers[m.ID()]()
@@ -174,21 +205,35 @@ func (env *Environment) addMapper(m mapper.Mapper) (effects []string) {
 			effects = append(effects, tentacle.TargetID())
 			tentacle = mapper.NewTentacle(tentacle.Type(), targetID, tentacle.Items())
 			env.addTentacle(remoteID, tentacle)
+			tentacles[tentacle.ID()] = remoteID
 			log.Info("tentacle ", zap.String("target", tentacle.TargetID()), zap.Any("items", tentacle.Items()))
 		case mapper.TentacleTypeMapper:
 			// 如果是Mapper类型的Tentacle，那么将该Tentacle分配到mapper所在stateMachine.
 			mCache.tentacles[tentacle.ID()] = tentacle
+			tentacles[tentacle.ID()] = targetID
 			log.Info("tentacle ", zap.String("target", tentacle.TargetID()), zap.Any("items", tentacle.Items()))
 		default:
 			log.Error("invalid tentacle type", zap.String("target", tentacle.TargetID()), zap.String("type", tentacle.Type()))
 		}
-		tentacleIDs = append(tentacleIDs, tentacle.ID())
 	}
 
 	mCache.mappers[m.ID()] = m
-	mCache.cleanHandlers[m.ID()] = env.generateCleanHandler(targetID, m.ID(), tentacleIDs)
+	mCache.cleanHandlers[m.ID()] = env.generateCleanHandler(targetID, m.ID(), tentacles)
+
+	return unique(append(effects, m.TargetEntity()))
+}
 
-	return append(effects, m.TargetEntity())
+// unique returns state ids without duplicates, in order.
+func unique(stateIDs []string) []string {
+	var result []string
+	seen := make(map[string]struct{}, len(stateIDs))
+	for _, stateID := range stateIDs {
+		if _, has := seen[stateID]; !has {
+			seen[stateID] = struct{}{}
+			result = append(result, stateID)
+		}
+	}
+	return result
 }
 
 // removeMapper remove mapper from Environment.
@@ -205,12 +250,56 @@ func (env *Environment) removeMapper(stateID, mapperID string) []string {
 		return []string{}
 	}
 
-	effects := mCache.cleanHandlers[mapperID]()
-	if len(mCache.mappers) == 0 {
-		delete(env.mapperCaches, stateID)
+	cleanHandler, ok := mCache.cleanHandlers[mapperID]
+	if !ok {
+		return []string{}
+	}
+
+	effects := cleanHandler()
+	env.removeEmptyCache(stateID)
+	return append(effects, stateID)
+}
+
+// ReferenceMappers returns copies of mappers with source references.
+func (env *Environment) ReferenceMappers() []mapper.Mapper {
+	env.lock.RLock()
+	defer env.lock.RUnlock()
+
+	var mappers []mapper.Mapper
+	for _, mCache := range env.mapperCaches {
+		for _, m := range mCache.mappers {
+			if len(m.References()) > 0 {
+				mappers = append(mappers, m.Copy())
+			}
+		}
+	}
+	return mappers
+}
+
+// ResolveMapper updates resolved entities of mapper references, tentacles of entities
+// no longer matched are removed. returns state machines whose environments changed.
+func (env *Environment) ResolveMapper(targetID, mapperID string, resolved map[string][]string) []string {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	mCache, ok := env.mapperCaches[targetID]
+	if !ok {
+		return nil
+	}
+
+	m, ok := mCache.mappers[mapperID]
+	if !ok {
+		return nil
+	}
+
+	m = m.Copy()
+	m.Resolve(resolved)
+	if reflect.DeepEqual(m.Resolved(), mCache.mappers[mapperID].Resolved()) {
+		return nil
 	}
 
-	return effects
+	log.Info("mapper references resolved", zap.String("mapper", mapperID), zap.Any("resolved", m.Resolved()))
+	return env.addMapper(m)
 }
 
 // addTentacle add tentacle into Environment.
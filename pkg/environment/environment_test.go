@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.etcd.io/etcd/api/v3/mvccpb"
 )
 
 func TestEnvironment(t *testing.T) {
@@ -22,3 +23,42 @@ func TestEnvironment(t *testing.T) {
 
 	assert.Equal(t, "device123", infos[0].EntityID)
 }
+
+func TestEnvironment_ResolveMapper(t *testing.T) {
+	env := NewEnvironment()
+	pair := EtcdPair{
+		Key:   "core.mapper.BASIC.room1.temp",
+		Value: []byte("insert into room1 select #sensors.temp as temp from #sensors = avg(related('contains'))"),
+	}
+
+	effects, err := env.OnMapperChanged(mvccpb.PUT, pair)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"room1"}, effects)
+	assert.Len(t, env.ReferenceMappers(), 1)
+
+	effects = env.ResolveMapper("room1", pair.Key, map[string][]string{"#sensors": {"sensor1", "sensor2"}})
+	assert.ElementsMatch(t, []string{"sensor1", "sensor2", "room1"}, effects)
+	assert.Len(t, env.GetActorEnv("sensor1").Tentacles, 1)
+	assert.Equal(t, "room1", env.GetActorEnv("sensor1").Tentacles[0].TargetID())
+	assert.Len(t, env.GetActorEnv("room1").Tentacles[0].Items(), 2)
+	assert.Nil(t, env.ResolveMapper("room1", pair.Key, map[string][]string{"#sensors": {"sensor2", "sensor1"}}))
+
+	// resolution is kept when mapper updated.
+	_, err = env.OnMapperChanged(mvccpb.PUT, pair)
+	assert.Nil(t, err)
+	assert.Len(t, env.GetActorEnv("sensor2").Tentacles, 1)
+
+	// sensor1 no longer matched.
+	effects = env.ResolveMapper("room1", pair.Key, map[string][]string{"#sensors": {"sensor2"}})
+	assert.ElementsMatch(t, []string{"sensor1", "sensor2", "room1"}, effects)
+	assert.Empty(t, env.GetActorEnv("sensor1").Tentacles)
+	assert.Len(t, env.GetActorEnv("sensor2").Tentacles, 1)
+
+	// tentacles of sources are removed with mapper.
+	effects, err = env.OnMapperChanged(mvccpb.DELETE, pair)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"sensor2", "room1"}, effects)
+	assert.Empty(t, env.GetActorEnv("sensor2").Tentacles)
+	assert.Empty(t, env.GetActorEnv("room1").Mappers)
+	assert.Empty(t, env.ReferenceMappers())
+}
@@ -62,4 +62,6 @@ type IEnvironment interface {
 	GetActorEnv(string) ActorEnv
 	StoreMappers([]EtcdPair) []MaSummary
 	OnMapperChanged(mvccpb.Event_EventType, EtcdPair) ([]string, error)
+	ReferenceMappers() []mapper.Mapper
+	ResolveMapper(targetID, mapperID string, resolved map[string][]string) []string
 }
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	defaultTimeout = 3 * time.Second
+)
+
+var ErrCheckTimeout = errors.New("health check timeout")
+
+// Check reports a dependency is usable by returning nil.
+type Check func(ctx context.Context) error
+
+// Result is the result of a single check.
+type Result struct {
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Latency string `json:"latency"`
+}
+
+// Report is the readiness of the service with per-check results.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Checker runs registered checks concurrently, each bounded by a timeout.
+type Checker struct {
+	timeout time.Duration
+	checks  map[string]Check
+	lock    sync.RWMutex
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Register adds a named check, replacing the check registered with the same name.
+func (c *Checker) Register(name string, check Check) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.checks[name] = check
+}
+
+// Check runs all checks, the report is down if any check fails.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.lock.RLock()
+	names := make([]string, 0, len(c.checks))
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		names = append(names, name)
+		checks[name] = check
+	}
+	c.lock.RUnlock()
+	sort.Strings(names)
+
+	var wg sync.WaitGroup
+	results := make([]Result, len(names))
+	for index, name := range names {
+		wg.Add(1)
+		go func(index int, check Check) {
+			defer wg.Done()
+			results[index] = c.run(ctx, check)
+		}(index, checks[name])
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(names))}
+	for index, name := range names {
+		report.Checks[name] = results[index]
+		if results[index].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// checks may ignore ctx, do not wait for them longer than timeout.
+	errCh := make(chan error, 1)
+	start := time.Now()
+	go func() { errCh <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = errors.Wrap(ErrCheckTimeout, c.timeout.String())
+	}
+
+	result := Result{Status: StatusUp, Latency: time.Since(start).String()}
+	if nil != err {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Liveness reports the process is alive.
+func (c *Checker) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Report{Status: StatusUp})
+}
+
+// Readiness reports results of all checks, responds 503 if any check fails.
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := c.Check(r.Context())
+	code := http.StatusOK
+	if report.Status != StatusUp {
+		code = http.StatusServiceUnavailable
+		log.Warn("service not ready", zap.Any("checks", report.Checks))
+	}
+	writeJSON(w, code, report)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); nil != err {
+		log.Error("write health report", zap.Error(err))
+	}
+}
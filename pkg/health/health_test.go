@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errUnreachable = errors.New("unreachable")
+
+func TestChecker(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Register("etcd", func(ctx context.Context) error { return nil })
+
+	report := checker.Check(context.Background())
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Equal(t, StatusUp, report.Checks["etcd"].Status)
+
+	checker.Register("dapr", func(ctx context.Context) error { return errUnreachable })
+	checker.Register("search_engine", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	report = checker.Check(context.Background())
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusUp, report.Checks["etcd"].Status)
+	assert.Equal(t, Result{Status: StatusDown, Error: "unreachable", Latency: report.Checks["dapr"].Latency}, report.Checks["dapr"])
+	assert.Equal(t, StatusDown, report.Checks["search_engine"].Status)
+	assert.Contains(t, report.Checks["search_engine"].Error, ErrCheckTimeout.Error())
+}
+
+func TestHandlers(t *testing.T) {
+	checker := NewChecker(0)
+	checker.Register("runtime", func(ctx context.Context) error { return errUnreachable })
+
+	recorder := httptest.NewRecorder()
+	checker.Liveness(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	checker.Readiness(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	var report Report
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "unreachable", report.Checks["runtime"].Error)
+}
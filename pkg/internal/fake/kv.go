@@ -0,0 +1,383 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var ErrCompareUnsupported = errors.New("fake: compare target unsupported")
+
+// KV is an in-memory clientv3.KV, it follows etcd range semantics:
+// a single key, [key, end) with WithRange/WithPrefix, WithFromKey,
+// WithSort, WithLimit, WithKeysOnly and WithCountOnly.
+// revisions are kept per key, history is not.
+type KV struct {
+	clientv3.KV
+
+	lock  sync.Mutex
+	rev   int64
+	inTxn bool
+	kvs   map[string]*mvccpb.KeyValue
+}
+
+func NewKV() *KV {
+	return &KV{kvs: map[string]*mvccpb.KeyValue{}}
+}
+
+// Set stores key without going through Put, for seeding.
+func (f *KV) Set(key, val string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.rev++
+	f.put(key, []byte(val), 0)
+}
+
+// Values returns a snapshot of all stored keys and values.
+func (f *KV) Values() map[string]string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	values := make(map[string]string, len(f.kvs))
+	for key, kv := range f.kvs {
+		values[key] = string(kv.Value)
+	}
+	return values
+}
+
+// Lease returns the lease attached to key.
+func (f *KV) Lease(key string) clientv3.LeaseID {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if kv, ok := f.kvs[key]; ok {
+		return clientv3.LeaseID(kv.Lease)
+	}
+	return clientv3.NoLease
+}
+
+func (f *KV) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	res, err := f.Do(ctx, clientv3.OpGet(key, opts...))
+	return res.Get(), errors.Wrap(err, "fake get")
+}
+
+func (f *KV) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	res, err := f.Do(ctx, clientv3.OpPut(key, val, opts...))
+	return res.Put(), errors.Wrap(err, "fake put")
+}
+
+func (f *KV) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	res, err := f.Do(ctx, clientv3.OpDelete(key, opts...))
+	return res.Del(), errors.Wrap(err, "fake delete")
+}
+
+func (f *KV) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.do(op)
+}
+
+func (f *KV) Txn(ctx context.Context) clientv3.Txn {
+	return &txn{kv: f}
+}
+
+func (f *KV) do(op clientv3.Op) (clientv3.OpResponse, error) {
+	switch {
+	case op.IsGet():
+		return f.get(op).OpResponse(), nil
+	case op.IsPut():
+		prev := f.kvs[string(op.KeyBytes())]
+		f.bump()
+		f.put(string(op.KeyBytes()), op.ValueBytes(), int64(leaseOf(op)))
+		res := &clientv3.PutResponse{Header: f.header()}
+		if prev != nil && boolField(op, "prevKV") {
+			res.PrevKv = prev
+		}
+		return res.OpResponse(), nil
+	case op.IsDelete():
+		keys := f.keys(op)
+		if len(keys) > 0 {
+			f.bump()
+		}
+		res := &clientv3.DeleteResponse{Header: f.header(), Deleted: int64(len(keys))}
+		for _, key := range keys {
+			if boolField(op, "prevKV") {
+				res.PrevKvs = append(res.PrevKvs, f.kvs[key])
+			}
+			delete(f.kvs, key)
+		}
+		return res.OpResponse(), nil
+	case op.IsTxn():
+		cmps, thenOps, elseOps := op.Txn()
+		res, err := f.txn(cmps, thenOps, elseOps)
+		if err != nil {
+			return clientv3.OpResponse{}, err
+		}
+		return res.OpResponse(), nil
+	}
+	return clientv3.OpResponse{}, nil
+}
+
+func (f *KV) get(op clientv3.Op) *clientv3.GetResponse {
+	keys := f.keys(op)
+	res := &clientv3.GetResponse{Header: f.header(), Count: int64(len(keys))}
+	if op.IsCountOnly() {
+		return res
+	}
+
+	kvs := make([]*mvccpb.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, f.kvs[key])
+	}
+	sortKVs(kvs, op)
+	if limit := intField(op, "limit"); limit > 0 && int64(len(kvs)) > limit {
+		kvs, res.More = kvs[:limit], true
+	}
+	for _, kv := range kvs {
+		item := *kv
+		if op.IsKeysOnly() {
+			item.Value = nil
+		}
+		res.Kvs = append(res.Kvs, &item)
+	}
+	return res
+}
+
+// bump starts a new revision, a txn writes all its ops in one revision.
+func (f *KV) bump() {
+	if !f.inTxn {
+		f.rev++
+	}
+}
+
+func (f *KV) put(key string, val []byte, lease int64) {
+	kv, ok := f.kvs[key]
+	if !ok {
+		kv = &mvccpb.KeyValue{Key: []byte(key), CreateRevision: f.rev}
+	}
+	f.kvs[key] = &mvccpb.KeyValue{
+		Key:            kv.Key,
+		Value:          val,
+		Lease:          lease,
+		CreateRevision: kv.CreateRevision,
+		ModRevision:    f.rev,
+		Version:        kv.Version + 1,
+	}
+}
+
+// keys returns sorted keys in range of op.
+func (f *KV) keys(op clientv3.Op) []string {
+	key, end := op.KeyBytes(), op.RangeBytes()
+	var keys []string
+	for k := range f.kvs {
+		if inRange([]byte(k), key, end) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *KV) header() *pb.ResponseHeader {
+	return &pb.ResponseHeader{Revision: f.rev}
+}
+
+func (f *KV) txn(cmps []clientv3.Cmp, thenOps, elseOps []clientv3.Op) (*clientv3.TxnResponse, error) {
+	succeeded := true
+	for _, cmp := range cmps {
+		ok, err := f.compare(cmp)
+		if err != nil {
+			return nil, err
+		}
+		succeeded = succeeded && ok
+	}
+
+	ops := thenOps
+	if !succeeded {
+		ops = elseOps
+	}
+
+	for _, op := range ops {
+		if op.IsPut() || op.IsDelete() {
+			f.bump()
+			break
+		}
+	}
+
+	inTxn := f.inTxn
+	f.inTxn = true
+	defer func() { f.inTxn = inTxn }()
+
+	res := &clientv3.TxnResponse{Succeeded: succeeded}
+	for _, op := range ops {
+		opRes, err := f.do(op)
+		if err != nil {
+			return nil, err
+		}
+		res.Responses = append(res.Responses, responseOp(opRes))
+	}
+	res.Header = f.header()
+	return res, nil
+}
+
+func (f *KV) compare(cmp clientv3.Cmp) (bool, error) {
+	kv := f.kvs[string(cmp.KeyBytes())]
+	if kv == nil {
+		kv = &mvccpb.KeyValue{}
+	}
+
+	var result int
+	switch union := cmp.TargetUnion.(type) {
+	case *pb.Compare_Version:
+		result = compareInt(kv.Version, union.Version)
+	case *pb.Compare_CreateRevision:
+		result = compareInt(kv.CreateRevision, union.CreateRevision)
+	case *pb.Compare_ModRevision:
+		result = compareInt(kv.ModRevision, union.ModRevision)
+	case *pb.Compare_Value:
+		result = bytes.Compare(kv.Value, union.Value)
+	case *pb.Compare_Lease:
+		result = compareInt(kv.Lease, union.Lease)
+	default:
+		return false, ErrCompareUnsupported
+	}
+
+	switch cmp.Result {
+	case pb.Compare_EQUAL:
+		return result == 0, nil
+	case pb.Compare_NOT_EQUAL:
+		return result != 0, nil
+	case pb.Compare_GREATER:
+		return result > 0, nil
+	case pb.Compare_LESS:
+		return result < 0, nil
+	}
+	return false, ErrCompareUnsupported
+}
+
+type txn struct {
+	kv      *KV
+	cmps    []clientv3.Cmp
+	thenOps []clientv3.Op
+	elseOps []clientv3.Op
+}
+
+func (t *txn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.cmps = append(t.cmps, cs...)
+	return t
+}
+
+func (t *txn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.thenOps = append(t.thenOps, ops...)
+	return t
+}
+
+func (t *txn) Else(ops ...clientv3.Op) clientv3.Txn {
+	t.elseOps = append(t.elseOps, ops...)
+	return t
+}
+
+func (t *txn) Commit() (*clientv3.TxnResponse, error) {
+	t.kv.lock.Lock()
+	defer t.kv.lock.Unlock()
+	return t.kv.txn(t.cmps, t.thenOps, t.elseOps)
+}
+
+func inRange(key, start, end []byte) bool {
+	switch {
+	case len(end) == 0:
+		return bytes.Equal(key, start)
+	case len(end) == 1 && end[0] == 0:
+		// WithFromKey.
+		return bytes.Compare(key, start) >= 0
+	default:
+		return bytes.Compare(key, start) >= 0 && bytes.Compare(key, end) < 0
+	}
+}
+
+func sortKVs(kvs []*mvccpb.KeyValue, op clientv3.Op) {
+	target, order := clientv3.SortByKey, clientv3.SortAscend
+	if opt := reflect.ValueOf(op).FieldByName("sort"); !opt.IsNil() {
+		target = clientv3.SortTarget(opt.Elem().FieldByName("Target").Int())
+		order = clientv3.SortOrder(opt.Elem().FieldByName("Order").Int())
+	}
+
+	less := func(a, b *mvccpb.KeyValue) bool {
+		switch target {
+		case clientv3.SortByVersion:
+			return a.Version < b.Version
+		case clientv3.SortByCreateRevision:
+			return a.CreateRevision < b.CreateRevision
+		case clientv3.SortByModRevision:
+			return a.ModRevision < b.ModRevision
+		case clientv3.SortByValue:
+			return bytes.Compare(a.Value, b.Value) < 0
+		default:
+			return bytes.Compare(a.Key, b.Key) < 0
+		}
+	}
+	sort.SliceStable(kvs, func(i, j int) bool {
+		if order == clientv3.SortDescend {
+			return less(kvs[j], kvs[i])
+		}
+		return less(kvs[i], kvs[j])
+	})
+}
+
+func responseOp(res clientv3.OpResponse) *pb.ResponseOp {
+	switch {
+	case res.Get() != nil:
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponseRange{ResponseRange: (*pb.RangeResponse)(res.Get())}}
+	case res.Put() != nil:
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponsePut{ResponsePut: (*pb.PutResponse)(res.Put())}}
+	case res.Del() != nil:
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponseDeleteRange{ResponseDeleteRange: (*pb.DeleteRangeResponse)(res.Del())}}
+	case res.Txn() != nil:
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponseTxn{ResponseTxn: (*pb.TxnResponse)(res.Txn())}}
+	}
+	return &pb.ResponseOp{}
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// clientv3.Op keeps these options unexported.
+func leaseOf(op clientv3.Op) clientv3.LeaseID {
+	return clientv3.LeaseID(intField(op, "leaseID"))
+}
+
+func intField(op clientv3.Op, name string) int64 {
+	return reflect.ValueOf(op).FieldByName(name).Int()
+}
+
+func boolField(op clientv3.Op, name string) bool {
+	return reflect.ValueOf(op).FieldByName(name).Bool()
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func keysOf(res *clientv3.GetResponse) []string {
+	var keys []string
+	for _, kv := range res.Kvs {
+		keys = append(keys, string(kv.Key))
+	}
+	return keys
+}
+
+func TestKV_Get(t *testing.T) {
+	ctx := context.Background()
+	kv := NewKV()
+	for _, key := range []string{"a", "a.1", "a.2", "a.3", "b"} {
+		kv.Set(key, key)
+	}
+
+	res, err := kv.Get(ctx, "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, keysOf(res))
+
+	res, err = kv.Get(ctx, "a.", clientv3.WithPrefix())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.1", "a.2", "a.3"}, keysOf(res))
+
+	res, err = kv.Get(ctx, "a.1", clientv3.WithRange("a.3"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.1", "a.2"}, keysOf(res))
+
+	res, err = kv.Get(ctx, "a.", clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend), clientv3.WithLimit(2))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.3", "a.2"}, keysOf(res))
+	assert.True(t, res.More)
+	assert.Equal(t, int64(3), res.Count)
+
+	res, err = kv.Get(ctx, "a.", clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	assert.Nil(t, err)
+	assert.Empty(t, res.Kvs[0].Value)
+
+	del, err := kv.Delete(ctx, "a.", clientv3.WithPrefix())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), del.Deleted)
+	assert.Equal(t, map[string]string{"a": "a", "b": "b"}, kv.Values())
+}
+
+func TestKV_Txn(t *testing.T) {
+	ctx := context.Background()
+	kv := NewKV()
+	kv.Set("a", "1")
+
+	res, err := kv.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision("b"), ">", 0)).
+		Then(clientv3.OpPut("b", "2")).
+		Else(clientv3.OpPut("c", "3"), clientv3.OpPut("d", "4", clientv3.WithLease(7))).
+		Commit()
+	assert.Nil(t, err)
+	assert.False(t, res.Succeeded)
+	assert.Equal(t, map[string]string{"a": "1", "c": "3", "d": "4"}, kv.Values())
+	assert.Equal(t, clientv3.LeaseID(7), kv.Lease("d"))
+
+	// one revision for all writes of a txn.
+	c, err := kv.Get(ctx, "c")
+	assert.Nil(t, err)
+	d, err := kv.Get(ctx, "d")
+	assert.Nil(t, err)
+	assert.Equal(t, res.Header.Revision, c.Kvs[0].ModRevision)
+	assert.Equal(t, c.Kvs[0].ModRevision, d.Kvs[0].ModRevision)
+
+	res, err = kv.Txn(ctx).
+		If(clientv3.Compare(clientv3.Value("a"), "=", "1")).
+		Then(clientv3.OpGet("a")).
+		Commit()
+	assert.Nil(t, err)
+	assert.True(t, res.Succeeded)
+	assert.Equal(t, "1", string(res.Responses[0].GetResponseRange().Kvs[0].Value))
+}
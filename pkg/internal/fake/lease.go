@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"sync"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Lease grants sequential lease ids and records granted ttls.
+type Lease struct {
+	clientv3.Lease
+
+	lock sync.Mutex
+	ttls []int64
+}
+
+func (f *Lease) Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.ttls = append(f.ttls, ttl)
+	return &clientv3.LeaseGrantResponse{ID: clientv3.LeaseID(len(f.ttls)), TTL: ttl}, nil
+}
+
+// Grants returns ttls of all granted leases in order.
+func (f *Lease) Grants() []int64 {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]int64{}, f.ttls...)
+}
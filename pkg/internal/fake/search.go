@@ -0,0 +1,35 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/resource/search"
+	"github.com/tkeel-io/core/pkg/resource/search/driver"
+)
+
+// NewSearch returns a search service backed by the embedded engine.
+func NewSearch(t *testing.T) *search.Service {
+	engine, err := driver.NewEmbeddedEngine(config.EmbeddedConfig{})
+	require.NoError(t, err)
+	return search.NewService(map[driver.Type]driver.SearchEngine{
+		driver.EmbeddedDriver: engine,
+	}).Use(driver.Embedded)
+}
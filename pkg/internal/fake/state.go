@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"sync"
+
+	dapr "github.com/dapr/go-sdk/client"
+)
+
+// State is an in-memory dapr state store, store names are ignored.
+type State struct {
+	dapr.Client
+
+	lock   sync.Mutex
+	states map[string][]byte
+}
+
+func NewState() *State {
+	return &State{states: map[string][]byte{}}
+}
+
+// Set stores key without going through SaveState, for seeding.
+func (f *State) Set(key string, data []byte) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.states[key] = data
+}
+
+// Values returns a snapshot of all stored keys and data.
+func (f *State) Values() map[string][]byte {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	values := make(map[string][]byte, len(f.states))
+	for key, data := range f.states {
+		values[key] = data
+	}
+	return values
+}
+
+func (f *State) GetState(ctx context.Context, storeName, key string) (*dapr.StateItem, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return &dapr.StateItem{Key: key, Value: f.states[key]}, nil
+}
+
+func (f *State) SaveState(ctx context.Context, storeName, key string, data []byte, so ...dapr.StateOption) error {
+	f.Set(key, data)
+	return nil
+}
+
+func (f *State) DeleteState(ctx context.Context, storeName, key string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.states, key)
+	return nil
+}
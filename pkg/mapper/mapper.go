@@ -28,6 +28,8 @@ type mapper struct {
 	id      string
 	tqlText string
 	tqlInst tql.TQL
+	// resolved entities of references, map[reference][]entityID.
+	resolved map[string][]string
 }
 
 func NewMapper(id, tqlText string) (Mapper, error) {
@@ -36,9 +38,10 @@ func NewMapper(id, tqlText string) (Mapper, error) {
 		return nil, errors.Wrap(err, "construct mapper")
 	}
 	return &mapper{
-		id:      id,
-		tqlText: tqlText,
-		tqlInst: tqlInst,
+		id:       id,
+		tqlText:  tqlText,
+		tqlInst:  tqlInst,
+		resolved: make(map[string][]string),
 	}, nil
 }
 
@@ -74,17 +77,25 @@ func (m *mapper) Tentacles() []Tentacler {
 	mItems := make([]WatchKey, 0)
 
 	for _, tentacleConf := range tentacleConfigs {
-		eItems := make([]WatchKey, len(tentacleConf.PropertyKeys))
-		for index, item := range tentacleConf.PropertyKeys {
-			watchKey := WatchKey{
-				EntityId:    tentacleConf.SourceEntity,
-				PropertyKey: item,
-			}
-			eItems[index] = watchKey
-			mItems = append(mItems, watchKey)
+		// a reference watches each of resolved entities.
+		sourceEntities := []string{tentacleConf.SourceEntity}
+		if m.isReference(tentacleConf.SourceEntity) {
+			sourceEntities = m.resolved[tentacleConf.SourceEntity]
 		}
 
-		tentacles = append(tentacles, NewTentacle(TentacleTypeEntity, tentacleConf.SourceEntity, eItems))
+		for _, sourceEntity := range sourceEntities {
+			eItems := make([]WatchKey, len(tentacleConf.PropertyKeys))
+			for index, item := range tentacleConf.PropertyKeys {
+				watchKey := WatchKey{
+					EntityId:    sourceEntity,
+					PropertyKey: item,
+				}
+				eItems[index] = watchKey
+				mItems = append(mItems, watchKey)
+			}
+
+			tentacles = append(tentacles, NewTentacle(TentacleTypeEntity, sourceEntity, eItems))
+		}
 	}
 
 	tentacles = append(tentacles, NewTentacle(TentacleTypeMapper, m.id, mItems))
@@ -95,11 +106,15 @@ func (m *mapper) Tentacles() []Tentacler {
 // Copy duplicate a mapper.
 func (m *mapper) Copy() Mapper {
 	mCopy, _ := NewMapper(m.id, m.tqlText)
+	mCopy.Resolve(m.resolved)
 	return mCopy
 }
 
 // Exec input returns output.
 func (m *mapper) Exec(values map[string]constraint.Node) (res map[string]constraint.Node, err error) {
+	if len(m.resolved) > 0 {
+		values = m.referenceValues(values)
+	}
 	res, err = m.tqlInst.Exec(values)
 	return res, errors.Wrap(err, "execute tql failed")
 }
@@ -19,7 +19,9 @@ package mapper
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/tql"
 )
 
 func TestMapper(t *testing.T) {
@@ -134,3 +136,45 @@ func TestExec3(t *testing.T) {
 	t.Log(err)
 	t.Log(result)
 }
+
+func TestMapper_References(t *testing.T) {
+	m, err := NewMapper("core.mapper.BASIC.room1.temp",
+		"insert into room1 select #sensors.temp as temp, #sensors.temp as temps from #sensors = avg(related('contains'))")
+	assert.Nil(t, err)
+	assert.Empty(t, m.SourceEntities())
+
+	// unresolved reference watches nothing.
+	tentacles := m.Tentacles()
+	assert.Len(t, tentacles, 1)
+	assert.Equal(t, TentacleTypeMapper, tentacles[0].Type())
+	assert.Empty(t, tentacles[0].Items())
+
+	m.Resolve(map[string][]string{"#sensors": {"sensor2", "sensor1"}, "#unknown": {"device1"}})
+	assert.Equal(t, map[string][]string{"#sensors": {"sensor1", "sensor2"}}, m.Resolved())
+
+	tentacles = m.Copy().Tentacles()
+	assert.Len(t, tentacles, 3)
+	assert.Equal(t, "sensor1", tentacles[0].TargetID())
+	assert.Equal(t, []WatchKey{{EntityId: "sensor2", PropertyKey: "temp"}}, tentacles[1].Items())
+	assert.Equal(t, []WatchKey{{EntityId: "sensor1", PropertyKey: "temp"}, {EntityId: "sensor2", PropertyKey: "temp"}}, tentacles[2].Items())
+
+	out, err := m.Exec(map[string]constraint.Node{
+		"sensor1.temp": constraint.NewNode(20),
+		"sensor2.temp": constraint.NewNode(23.5),
+		"sensor3.temp": constraint.NewNode(100),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 21.75, out["temp"].Value())
+}
+
+func Test_aggregate(t *testing.T) {
+	items := []constraint.Node{constraint.NewNode(3), constraint.NewNode(1.5), constraint.NewNode("on")}
+	assert.Equal(t, []interface{}{3.0, 1.5, "on"}, aggregate("", items).Value())
+	assert.Equal(t, int64(3), aggregate(tql.AggregateCount, items).Value())
+	assert.Equal(t, 4.5, aggregate(tql.AggregateSum, items).Value())
+	assert.Equal(t, 1.5, aggregate(tql.AggregateMin, items).Value())
+	assert.Equal(t, 3.0, aggregate(tql.AggregateMax, items).Value())
+	assert.Equal(t, 2.25, aggregate(tql.AggregateAvg, items).Value())
+	assert.Nil(t, aggregate(tql.AggregateAvg, nil))
+	assert.Equal(t, []interface{}{}, aggregate("", nil).Value())
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mapper
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/tql"
+)
+
+// References returns source references.
+func (m *mapper) References() []tql.Reference {
+	return m.tqlInst.References()
+}
+
+// Resolved returns resolved entities of references.
+func (m *mapper) Resolved() map[string][]string {
+	resolved := make(map[string][]string, len(m.resolved))
+	for name, entityIDs := range m.resolved {
+		resolved[name] = append([]string{}, entityIDs...)
+	}
+	return resolved
+}
+
+// Resolve sets resolved entities of references, entities are sorted.
+func (m *mapper) Resolve(resolved map[string][]string) {
+	m.resolved = make(map[string][]string, len(resolved))
+	for name, entityIDs := range resolved {
+		if m.isReference(name) {
+			entityIDs = append([]string{}, entityIDs...)
+			sort.Strings(entityIDs)
+			m.resolved[name] = entityIDs
+		}
+	}
+}
+
+func (m *mapper) isReference(entityID string) bool {
+	for _, ref := range m.tqlInst.References() {
+		if ref.Name == entityID {
+			return true
+		}
+	}
+	return false
+}
+
+// referenceValues returns values with values of references, e.g. #sensors.temp,
+// collected from resolved entities and aggregated.
+func (m *mapper) referenceValues(values map[string]constraint.Node) map[string]constraint.Node {
+	out := make(map[string]constraint.Node, len(values))
+	for key, val := range values {
+		out[key] = val
+	}
+
+	for _, ref := range m.tqlInst.References() {
+		for _, tentacleConf := range m.tqlInst.Tentacles() {
+			if tentacleConf.SourceEntity != ref.Name {
+				continue
+			}
+
+			for _, propertyKey := range tentacleConf.PropertyKeys {
+				var items []constraint.Node
+				for _, entityID := range m.resolved[ref.Name] {
+					watchKey := WatchKey{EntityId: entityID, PropertyKey: propertyKey}
+					if val, has := values[watchKey.String()]; has && nil != val {
+						items = append(items, val)
+					}
+				}
+
+				watchKey := WatchKey{EntityId: ref.Name, PropertyKey: propertyKey}
+				if val := aggregate(ref.Aggregate, items); nil != val {
+					out[watchKey.String()] = val
+				}
+			}
+		}
+	}
+	return out
+}
+
+// aggregate returns aggregation of values, nil if no value to aggregate.
+func aggregate(fn string, items []constraint.Node) constraint.Node {
+	switch fn {
+	case "":
+		values := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			values = append(values, item.Value())
+		}
+		return constraint.NewNode(values)
+	case tql.AggregateCount:
+		return constraint.NewNode(len(items))
+	}
+
+	var numbers []float64
+	for _, item := range items {
+		if number, ok := toFloat(item); ok {
+			numbers = append(numbers, number)
+		}
+	}
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	result := numbers[0]
+	for _, number := range numbers[1:] {
+		switch fn {
+		case tql.AggregateSum, tql.AggregateAvg:
+			result += number
+		case tql.AggregateMin:
+			if number < result {
+				result = number
+			}
+		case tql.AggregateMax:
+			if number > result {
+				result = number
+			}
+		}
+	}
+	if fn == tql.AggregateAvg {
+		result /= float64(len(numbers))
+	}
+	return constraint.NewNode(result)
+}
+
+func toFloat(item constraint.Node) (float64, bool) {
+	switch val := item.Value().(type) {
+	case int64:
+		return float64(val), true
+	case float64:
+		return val, true
+	case string:
+		number, err := strconv.ParseFloat(val, 64)
+		return number, nil == err
+	default:
+		return 0, false
+	}
+}
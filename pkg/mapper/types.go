@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/tql"
 )
 
 const (
@@ -47,6 +48,12 @@ type Mapper interface {
 	Copy() Mapper
 	// Exec excute input returns output.
 	Exec(map[string]constraint.Node) (map[string]constraint.Node, error)
+	// References returns source references.
+	References() []tql.Reference
+	// Resolved returns resolved entities of references.
+	Resolved() map[string][]string
+	// Resolve sets resolved entities of references.
+	Resolve(resolved map[string][]string)
 }
 
 type TentacleType = string
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+)
+
+const (
+	namespace = "core"
+
+	SinkState      = "state"
+	SinkSearch     = "search"
+	SinkTimeSeries = "time_series"
+)
+
+var (
+	// MessageDuration observes message processing latency of state machines.
+	MessageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "runtime",
+		Name:      "message_duration_seconds",
+		Help:      "Message processing latency of state machines.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type"})
+
+	// MailboxOverflows counts messages sent while the runtime mailbox is full, senders block until it has room.
+	MailboxOverflows = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "runtime",
+		Name:      "mailbox_overflows_total",
+		Help:      "Number of messages sent while the runtime mailbox is full.",
+	})
+
+	// MapperExecutions counts mapper executions.
+	MapperExecutions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mapper",
+		Name:      "executions_total",
+		Help:      "Number of mapper executions.",
+	})
+
+	// MapperFailures counts failed mapper executions.
+	MapperFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mapper",
+		Name:      "failures_total",
+		Help:      "Number of failed mapper executions.",
+	})
+
+	// FlushDuration observes flush latency of sinks.
+	FlushDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "flush",
+		Name:      "duration_seconds",
+		Help:      "Flush latency of state, search and time-series sinks.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"sink"})
+
+	// FlushErrors counts flush errors of sinks.
+	FlushErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "flush",
+		Name:      "errors_total",
+		Help:      "Number of flush errors of state, search and time-series sinks.",
+	}, []string{"sink"})
+)
+
+func init() {
+	Register(
+		MessageDuration,
+		MailboxOverflows,
+		MapperExecutions,
+		MapperFailures,
+		FlushDuration,
+		FlushErrors,
+	)
+}
+
+// Register registers collectors into default registry, collectors registered already are ignored.
+func Register(collectors ...prometheus.Collector) {
+	for _, collector := range collectors {
+		if err := prometheus.Register(collector); nil != err {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok { //nolint
+				log.Error("register metrics collector", zap.Error(err))
+			}
+		}
+	}
+}
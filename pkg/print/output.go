@@ -0,0 +1,106 @@
+package print //nolint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// output formats.
+const (
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputTable = "table"
+)
+
+var ErrOutputFormatInvalid = errors.New("invalid output format")
+
+// Table is tabular view of a result.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Output prints v in given format, table is only evaluated for table format.
+func Output(w io.Writer, format string, v interface{}, table func() Table) error {
+	switch format {
+	case OutputJSON:
+		return JSON(w, v)
+	case OutputYAML:
+		return YAML(w, v)
+	case OutputTable:
+		return PrintTable(w, table())
+	default:
+		return errors.Wrap(ErrOutputFormatInvalid, format)
+	}
+}
+
+// JSON prints v as indented json, proto messages are marshaled by protojson.
+func JSON(w io.Writer, v interface{}) error {
+	bytes, err := marshalJSON(v)
+	if nil != err {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(bytes))
+	return errors.Wrap(err, "print json")
+}
+
+// YAML prints v as yaml, field names follow json output.
+func YAML(w io.Writer, v interface{}) error {
+	bytes, err := marshalJSON(v)
+	if nil != err {
+		return err
+	}
+
+	var data interface{}
+	if err = json.Unmarshal(bytes, &data); nil != err {
+		return errors.Wrap(err, "print yaml")
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(data); nil != err {
+		return errors.Wrap(err, "print yaml")
+	}
+	return errors.Wrap(encoder.Close(), "print yaml")
+}
+
+// PrintTable prints table with aligned columns.
+func PrintTable(w io.Writer, table Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	if len(table.Header) > 0 {
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(table.Header, "\t")))
+	}
+	for _, row := range table.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return errors.Wrap(tw.Flush(), "print table")
+}
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	var err error
+	var data []byte
+	if msg, ok := v.(proto.Message); ok {
+		data, err = protojson.Marshal(msg)
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if nil != err {
+		return nil, errors.Wrap(err, "marshal json")
+	}
+
+	// protojson output is not stable, indent it again.
+	var buf bytes.Buffer
+	if err = json.Indent(&buf, data, "", "  "); nil != err {
+		return nil, errors.Wrap(err, "marshal json")
+	}
+	return buf.Bytes(), nil
+}
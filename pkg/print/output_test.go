@@ -0,0 +1,35 @@
+package print //nolint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestOutput(t *testing.T) {
+	val, err := structpb.NewStruct(map[string]interface{}{"id": "device1", "temp": 20})
+	assert.Nil(t, err)
+
+	table := func() Table {
+		return Table{
+			Header: []string{"id", "temp"},
+			Rows:   [][]string{{"device1", "20"}},
+		}
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, Output(&buf, OutputJSON, val, table))
+	assert.Equal(t, "{\n  \"id\": \"device1\",\n  \"temp\": 20\n}\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, Output(&buf, OutputYAML, val, table))
+	assert.Equal(t, "id: device1\ntemp: 20\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, Output(&buf, OutputTable, val, table))
+	assert.Equal(t, "ID        TEMP\ndevice1   20\n", buf.String())
+
+	assert.ErrorIs(t, Output(&buf, "xml", val, table), ErrOutputFormatInvalid)
+}
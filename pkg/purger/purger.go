@@ -0,0 +1,426 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package purger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/runtime"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/tql"
+	"github.com/tkeel-io/core/pkg/util"
+	"github.com/tkeel-io/kit/log"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Purger permanently removes soft-deleted entities, together with their search documents,
+// mappers and subscriptions reading only them. mappers of other entities reading them are
+// kept and reported dangling. every purge runs as a job in background.
+type Purger struct {
+	stateClient  StateClient
+	kv           clientv3.KV
+	searchClient pb.SearchHTTPServer
+	actors       ActorRemover
+	interval     time.Duration
+
+	jobs    []*pb.PurgeJob
+	running sync.Mutex
+	lock    sync.RWMutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func NewPurger(ctx context.Context, stateClient StateClient, kv clientv3.KV, searchClient pb.SearchHTTPServer, actors ActorRemover, interval time.Duration) *Purger {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Purger{
+		ctx:          ctx,
+		cancel:       cancel,
+		stateClient:  stateClient,
+		kv:           kv,
+		searchClient: searchClient,
+		actors:       actors,
+		interval:     interval,
+	}
+}
+
+// Start purge entities whose retention passed periodically.
+func (p *Purger) Start() {
+	if p.interval <= 0 {
+		log.Info("periodic purge disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := p.PurgeExpired(p.ctx); nil != err && !errors.Is(err, ErrEmptyEntities) {
+					log.Error("periodic purge", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+func (p *Purger) Stop() {
+	p.cancel()
+}
+
+// Deleted returns soft-deleted entities, earliest deleted first.
+func (p *Purger) Deleted(ctx context.Context) ([]*Tombstone, error) {
+	res, err := p.kv.Get(ctx, util.FormatDeleted(""), clientv3.WithPrefix())
+	if nil != err {
+		return nil, errors.Wrap(err, "list deleted entities")
+	}
+
+	tombstones := make([]*Tombstone, 0, len(res.Kvs))
+	for _, kv := range res.Kvs {
+		var tombstone Tombstone
+		if err = json.Unmarshal(kv.Value, &tombstone); nil != err {
+			log.Warn("decode tombstone", zap.String("key", string(kv.Key)), zap.Error(err))
+			continue
+		}
+		tombstones = append(tombstones, &tombstone)
+	}
+
+	sort.Slice(tombstones, func(i, j int) bool {
+		if tombstones[i].DeletedAt != tombstones[j].DeletedAt {
+			return tombstones[i].DeletedAt < tombstones[j].DeletedAt
+		}
+		return tombstones[i].ID < tombstones[j].ID
+	})
+	return tombstones, nil
+}
+
+// PurgeExpired starts a job purging entities whose retention passed.
+func (p *Purger) PurgeExpired(ctx context.Context) (*pb.PurgeJob, error) {
+	tombstones, err := p.Deleted(ctx)
+	if nil != err {
+		return nil, errors.Wrap(err, "purge expired entities")
+	}
+
+	var ids []string
+	deadline := util.UnixMilli() - config.Get().Deletion.Retention.Milliseconds()
+	for _, tombstone := range tombstones {
+		if tombstone.DeletedAt <= deadline {
+			ids = append(ids, tombstone.ID)
+		}
+	}
+
+	job, err := p.Purge(ids, TriggerRetention)
+	return job, errors.Wrap(err, "purge expired entities")
+}
+
+// Purge starts a job purging soft-deleted entities, returns the job without waiting it completed.
+func (p *Purger) Purge(ids []string, trigger string) (*pb.PurgeJob, error) {
+	if len(ids) == 0 {
+		return nil, ErrEmptyEntities
+	}
+
+	job := &pb.PurgeJob{
+		Id:        uuid(),
+		Status:    JobRunning,
+		Trigger:   trigger,
+		StartTime: util.UnixMilli(),
+	}
+
+	p.lock.Lock()
+	p.jobs = append(p.jobs, job)
+	if len(p.jobs) > maxJobs {
+		p.jobs = p.jobs[len(p.jobs)-maxJobs:]
+	}
+	snapshot := proto.Clone(job).(*pb.PurgeJob)
+	p.lock.Unlock()
+
+	go p.run(job, ids)
+	return snapshot, nil
+}
+
+// Job returns purge job.
+func (p *Purger) Job(id string) (*pb.PurgeJob, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for _, job := range p.jobs {
+		if job.Id == id {
+			return proto.Clone(job).(*pb.PurgeJob), nil
+		}
+	}
+	return nil, ErrJobNotFound
+}
+
+// Jobs returns recent purge jobs, latest first.
+func (p *Purger) Jobs() []*pb.PurgeJob {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	jobs := make([]*pb.PurgeJob, 0, len(p.jobs))
+	for i := len(p.jobs) - 1; i >= 0; i-- {
+		jobs = append(jobs, proto.Clone(p.jobs[i]).(*pb.PurgeJob))
+	}
+	return jobs
+}
+
+func (p *Purger) run(job *pb.PurgeJob, ids []string) {
+	p.running.Lock()
+	defer p.running.Unlock()
+
+	log.Info("purge job started", zap.String("job", job.Id),
+		zap.String("trigger", job.Trigger), zap.Strings("entities", ids))
+
+	status := JobSucceeded
+	for _, id := range ids {
+		report := &pb.PurgeReport{EntityId: id}
+		if err := p.purge(p.ctx, id, report); nil != err {
+			log.Error("purge entity", logger.EntityID(id), zap.String("job", job.Id), zap.Error(err))
+			report.Error = err.Error()
+			status = JobFailed
+		}
+
+		p.lock.Lock()
+		job.Reports = append(job.Reports, report)
+		p.lock.Unlock()
+	}
+
+	p.lock.Lock()
+	job.Status = status
+	job.EndTime = util.UnixMilli()
+	p.lock.Unlock()
+
+	log.Info("purge job completed", zap.String("job", job.Id), zap.String("status", status))
+}
+
+// purge removes entity and everything reading it, the entity is removed at last
+// so that a failed purge can be retried.
+func (p *Purger) purge(ctx context.Context, id string, report *pb.PurgeReport) error {
+	tombstone, err := p.tombstone(ctx, id)
+	if nil != err {
+		return err
+	}
+
+	base, err := p.state(ctx, id)
+	if nil != err {
+		return err
+	}
+
+	switch {
+	case nil != base && base.DeletedAt == 0:
+		return ErrEntityNotDeleted
+	case nil != base:
+		tombstone = &Tombstone{ID: base.ID, Type: base.Type, Owner: base.Owner, DeletedAt: base.DeletedAt}
+	case nil == tombstone:
+		return ErrEntityNotFound
+	}
+
+	// subscriptions and mappers of other entities reading the entity.
+	refs, err := p.references(ctx, id)
+	if nil != err {
+		return err
+	}
+
+	removed := make(map[string]struct{})
+	for _, ref := range refs {
+		if ref.Type != runtime.StateMachineTypeSubscription || !ref.Sole {
+			// mapper reads other entities too, kept.
+			report.DanglingMappers = append(report.DanglingMappers, ref.Key)
+			continue
+		} else if _, has := removed[ref.EntityID]; has {
+			continue
+		}
+
+		removed[ref.EntityID] = struct{}{}
+		if err = p.removeSubscription(ctx, ref.EntityID); nil != err {
+			return errors.Wrapf(err, "remove subscription %s", ref.EntityID)
+		}
+		report.Subscriptions = append(report.Subscriptions, ref.EntityID)
+	}
+
+	return p.remove(ctx, tombstone, report)
+}
+
+// remove removes mappers, search document, state and registry of entity.
+func (p *Purger) remove(ctx context.Context, tombstone *Tombstone, report *pb.PurgeReport) error {
+	keys, err := p.deletePrefix(ctx, util.FormatMapper(tombstone.Type, tombstone.ID, ""))
+	if nil != err {
+		return errors.Wrap(err, "delete mappers")
+	}
+	report.Mappers = append(report.Mappers, keys...)
+
+	if _, err = p.searchClient.DeleteByID(ctx, &pb.DeleteByIDRequest{Id: tombstone.ID}); nil != err {
+		return errors.Wrap(err, "delete search document")
+	}
+	report.SearchDocument = true
+
+	if err = p.stateClient.DeleteState(ctx, EntityStateName, tombstone.ID); nil != err {
+		return errors.Wrap(err, "delete state")
+	}
+	report.State = true
+
+	for _, key := range []string{
+		util.FormatEntity(tombstone.ID),
+		util.FormatTenantEntity(tombstone.Owner, tombstone.ID),
+		util.FormatDeleted(tombstone.ID),
+	} {
+		if _, err = p.kv.Delete(ctx, key); nil != err {
+			return errors.Wrap(err, "unregister entity")
+		}
+	}
+
+	log.Info("purge entity", logger.EntityID(tombstone.ID), zap.String("type", tombstone.Type))
+	return nil
+}
+
+// removeSubscription removes subscription reading only purged entity, and detaches it from runtime.
+func (p *Purger) removeSubscription(ctx context.Context, id string) error {
+	tombstone := &Tombstone{ID: id, Type: runtime.StateMachineTypeSubscription}
+	base, err := p.state(ctx, id)
+	if nil != err {
+		return err
+	} else if nil != base {
+		tombstone.Owner = base.Owner
+	}
+
+	if err = p.remove(ctx, tombstone, &pb.PurgeReport{EntityId: id}); nil != err {
+		return err
+	} else if nil != p.actors {
+		return errors.Wrap(p.actors.RemoveActor(ctx, id), "remove subscription actor")
+	}
+	return nil
+}
+
+type reference struct {
+	Key      string
+	Type     string
+	EntityID string
+	// Sole is true if the entity is the only source of the referring entity.
+	Sole bool
+}
+
+// references returns mappers of other entities whose TQL reads the entity.
+func (p *Purger) references(ctx context.Context, id string) ([]reference, error) {
+	res, err := p.kv.Get(ctx, util.EtcdMapperPrefix, clientv3.WithPrefix())
+	if nil != err {
+		return nil, errors.Wrap(err, "list mappers")
+	}
+
+	var refs []reference
+	sources := make(map[string]map[string]struct{})
+	for _, kv := range res.Kvs {
+		// core.mapper.{type}.{entityID}.{name} .
+		arr := strings.Split(string(kv.Key), ".")
+		if len(arr) != 5 || arr[3] == id {
+			continue
+		}
+
+		tqlInst, err := tql.NewTQL(string(kv.Value))
+		if nil != err {
+			log.Warn("parse mapper", zap.String("key", string(kv.Key)), zap.Error(err))
+			continue
+		}
+
+		reads := false
+		for _, entityID := range tqlInst.Entities() {
+			if entityID == arr[3] {
+				continue
+			} else if _, has := sources[arr[3]]; !has {
+				sources[arr[3]] = make(map[string]struct{})
+			}
+			sources[arr[3]][entityID] = struct{}{}
+			reads = reads || entityID == id
+		}
+
+		if reads {
+			refs = append(refs, reference{Key: string(kv.Key), Type: arr[2], EntityID: arr[3]})
+		}
+	}
+
+	for i := range refs {
+		refs[i].Sole = len(sources[refs[i].EntityID]) == 1
+	}
+	return refs, nil
+}
+
+func (p *Purger) tombstone(ctx context.Context, id string) (*Tombstone, error) {
+	res, err := p.kv.Get(ctx, util.FormatDeleted(id))
+	if nil != err {
+		return nil, errors.Wrap(err, "get tombstone")
+	} else if len(res.Kvs) == 0 {
+		return nil, nil
+	}
+
+	var tombstone Tombstone
+	if err = json.Unmarshal(res.Kvs[0].Value, &tombstone); nil != err {
+		return nil, errors.Wrap(err, "decode tombstone")
+	}
+	return &tombstone, nil
+}
+
+func (p *Purger) state(ctx context.Context, id string) (*statem.Base, error) {
+	item, err := p.stateClient.GetState(ctx, EntityStateName, id)
+	if nil != err {
+		return nil, errors.Wrap(err, "get entity state")
+	} else if nil == item || len(item.Value) == 0 {
+		return nil, nil
+	}
+
+	base, err := statem.DecodeBase(item.Value)
+	return base, errors.Wrap(err, "decode entity state")
+}
+
+func (p *Purger) deletePrefix(ctx context.Context, prefix string) ([]string, error) {
+	res, err := p.kv.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if nil != err {
+		return nil, errors.Wrap(err, "list keys")
+	} else if len(res.Kvs) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(res.Kvs))
+	for _, kv := range res.Kvs {
+		keys = append(keys, string(kv.Key))
+	}
+
+	_, err = p.kv.Delete(ctx, prefix, clientv3.WithPrefix())
+	return keys, errors.Wrap(err, "delete keys")
+}
+
+func uuid() string {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		return ""
+	}
+	// see section 4.1.1.
+	uuid[8] = uuid[8]&^0xc0 | 0x80
+	// see section 4.1.3.
+	uuid[6] = uuid[6]&^0xf0 | 0x40
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
+}
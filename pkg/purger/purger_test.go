@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package purger
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/util"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newBase(id, typ string, deletedAt int64) *statem.Base {
+	return &statem.Base{
+		ID:        id,
+		Type:      typ,
+		Owner:     "admin",
+		Version:   1,
+		DeletedAt: deletedAt,
+		KValues:   map[string]constraint.Node{"temp": constraint.IntNode(20)},
+		Configs:   map[string]constraint.Config{},
+	}
+}
+
+func tombstone(t *testing.T, base *statem.Base) string {
+	bytes, err := json.Marshal(Tombstone{ID: base.ID, Type: base.Type, Owner: base.Owner, DeletedAt: base.DeletedAt})
+	assert.Nil(t, err)
+	return string(bytes)
+}
+
+type fakeActors []string
+
+func (f *fakeActors) RemoveActor(ctx context.Context, id string) error {
+	*f = append(*f, id)
+	return nil
+}
+
+func setup(t *testing.T) (*Purger, *fake.State, *fake.KV, *fakeActors) {
+	searchClient := fake.NewSearch(t)
+	state, kv := fake.NewState(), fake.NewKV()
+	for _, base := range []*statem.Base{
+		newBase("device1", "BASIC", 1000),
+		newBase("device2", "BASIC", 0),
+		newBase("sub1", "SUBSCRIPTION", 0),
+		newBase("sub2", "SUBSCRIPTION", 0),
+	} {
+		bytes, err := statem.EncodeBase(base)
+		assert.Nil(t, err)
+		state.Set(base.ID, bytes)
+
+		val, err := structpb.NewValue(base.SearchValues())
+		assert.Nil(t, err)
+		_, err = searchClient.Index(context.Background(), &pb.IndexObject{Obj: val})
+		assert.Nil(t, err)
+
+		if base.DeletedAt > 0 {
+			kv.Set(util.FormatDeleted(base.ID), tombstone(t, base))
+		} else {
+			kv.Set(util.FormatEntity(base.ID), base.Type)
+			kv.Set(util.FormatTenantEntity(base.Owner, base.ID), base.Type)
+		}
+	}
+
+	kv.Set(util.FormatMapper("BASIC", "device1", "m1"), "insert into device1 select device3.temp as temp")
+	kv.Set(util.FormatMapper("BASIC", "device2", "m2"), "insert into device2 select device1.temp as temp")
+	kv.Set(util.FormatMapper("SUBSCRIPTION", "sub1", "subscription"), "insert into sub1 select device1.temp as temp")
+	kv.Set(util.FormatMapper("SUBSCRIPTION", "sub2", "subscription"), "insert into sub2 select device1.temp as temp, device2.temp as temp2")
+
+	actors := &fakeActors{}
+	return NewPurger(context.Background(), state, kv, searchClient, actors, 0), state, kv, actors
+}
+
+func wait(t *testing.T, p *Purger, id string) *pb.PurgeJob {
+	var job *pb.PurgeJob
+	assert.Eventually(t, func() bool {
+		var err error
+		job, err = p.Job(id)
+		return nil == err && job.Status != JobRunning
+	}, time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestPurger_Purge(t *testing.T) {
+	p, state, kv, actors := setup(t)
+
+	_, err := p.Purge(nil, TriggerManual)
+	assert.ErrorIs(t, err, ErrEmptyEntities)
+
+	job, err := p.Purge([]string{"device1", "device2", "missing"}, TriggerManual)
+	assert.Nil(t, err)
+	assert.Equal(t, TriggerManual, job.Trigger)
+
+	job = wait(t, p, job.Id)
+	assert.Equal(t, JobFailed, job.Status)
+	assert.Len(t, job.Reports, 3)
+
+	report := job.Reports[0]
+	assert.Equal(t, "device1", report.EntityId)
+	assert.True(t, report.State)
+	assert.True(t, report.SearchDocument)
+	assert.Equal(t, []string{"core.mapper.BASIC.device1.m1"}, report.Mappers)
+	assert.Equal(t, []string{"core.mapper.BASIC.device2.m2", "core.mapper.SUBSCRIPTION.sub2.subscription"}, report.DanglingMappers)
+	assert.Equal(t, []string{"sub1"}, report.Subscriptions)
+	assert.Equal(t, fakeActors{"sub1"}, *actors)
+	assert.Empty(t, report.Error)
+
+	assert.Equal(t, ErrEntityNotDeleted.Error(), job.Reports[1].Error)
+	assert.Equal(t, ErrEntityNotFound.Error(), job.Reports[2].Error)
+
+	// device1 and subscription reading only it are removed, mappers of others are kept.
+	assert.Len(t, state.Values(), 2)
+	assert.NotNil(t, state.Values()["device2"])
+	assert.NotNil(t, state.Values()["sub2"])
+	assert.Equal(t, map[string]string{
+		"core.entity.device2":                        "BASIC",
+		"core.tenant.admin.entity.device2":           "BASIC",
+		"core.entity.sub2":                           "SUBSCRIPTION",
+		"core.tenant.admin.entity.sub2":              "SUBSCRIPTION",
+		"core.mapper.BASIC.device2.m2":               "insert into device2 select device1.temp as temp",
+		"core.mapper.SUBSCRIPTION.sub2.subscription": "insert into sub2 select device1.temp as temp, device2.temp as temp2",
+	}, kv.Values())
+
+	assert.Len(t, p.Jobs(), 1)
+	_, err = p.Job("unknown")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestPurger_PurgeExpired(t *testing.T) {
+	p, _, kv, _ := setup(t)
+
+	tombstones, err := p.Deleted(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []*Tombstone{{ID: "device1", Type: "BASIC", Owner: "admin", DeletedAt: 1000}}, tombstones)
+
+	job, err := p.PurgeExpired(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, TriggerRetention, job.Trigger)
+	job = wait(t, p, job.Id)
+	assert.Equal(t, JobSucceeded, job.Status)
+
+	for key := range kv.Values() {
+		assert.False(t, strings.HasPrefix(key, util.EtcdDeletedPrefix))
+	}
+
+	_, err = p.PurgeExpired(context.Background())
+	assert.ErrorIs(t, err, ErrEmptyEntities)
+}
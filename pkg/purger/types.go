@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package purger
+
+import (
+	"context"
+	"errors"
+
+	dapr "github.com/dapr/go-sdk/client"
+)
+
+const (
+	EntityStateName = "core-state"
+	// maxJobs is the number of recent jobs kept for querying.
+	maxJobs = 100
+)
+
+// purge job status.
+const (
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// what started purge job.
+const (
+	TriggerManual    = "manual"
+	TriggerRetention = "retention"
+)
+
+var (
+	ErrEntityNotFound   = errors.New("entity not found")
+	ErrEntityNotDeleted = errors.New("entity not deleted, delete it before purging")
+	ErrJobNotFound      = errors.New("purge job not found")
+	ErrEmptyEntities    = errors.New("no entity to purge")
+)
+
+type StateClient interface {
+	// GetState returns entity state.
+	GetState(ctx context.Context, storeName, key string) (*dapr.StateItem, error)
+	// DeleteState deletes entity state.
+	DeleteState(ctx context.Context, storeName, key string) error
+}
+
+type ActorRemover interface {
+	// RemoveActor removes state machine from runtime without flushing.
+	RemoveActor(ctx context.Context, id string) error
+}
+
+// Tombstone records a soft-deleted entity, kept in etcd until the entity is restored or purged.
+type Tombstone struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Owner     string `json:"owner"`
+	DeletedAt int64  `json:"deleted_at"`
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/util"
+	"github.com/tkeel-io/kit/log"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Reconciler keeps search index consistent with entity state,
+// re-indexes stale or missing documents and deletes orphans.
+type Reconciler struct {
+	stateClient  StateClient
+	registry     Registry
+	searchClient pb.SearchHTTPServer
+	interval     time.Duration
+	grace        time.Duration
+	// orphans records when entities without state were first seen.
+	orphans map[string]int64
+
+	report  *pb.ReconcileReport
+	running sync.Mutex
+	lock    sync.RWMutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func NewReconciler(ctx context.Context, stateClient StateClient, registry Registry, searchClient pb.SearchHTTPServer, cfg config.Reconciler) *Reconciler {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Reconciler{
+		ctx:          ctx,
+		cancel:       cancel,
+		stateClient:  stateClient,
+		registry:     registry,
+		searchClient: searchClient,
+		interval:     cfg.Interval,
+		grace:        cfg.OrphanGrace,
+		orphans:      make(map[string]int64),
+		report:       &pb.ReconcileReport{},
+	}
+}
+
+// Start run reconciliation periodically.
+func (r *Reconciler) Start() {
+	if r.interval <= 0 {
+		log.Info("periodic reconciliation disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := r.Reconcile(r.ctx, false); nil != err {
+					log.Error("periodic reconciliation", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+func (r *Reconciler) Stop() {
+	r.cancel()
+}
+
+// LastReport returns report of the last reconciliation.
+func (r *Reconciler) LastReport() *pb.ReconcileReport {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.report
+}
+
+// Reconcile compare entity versions in state store with search index, fix inconsistencies unless dryRun.
+func (r *Reconciler) Reconcile(ctx context.Context, dryRun bool) (*pb.ReconcileReport, error) {
+	r.running.Lock()
+	defer r.running.Unlock()
+
+	report := &pb.ReconcileReport{DryRun: dryRun, StartTime: util.UnixMilli()}
+	log.Info("reconciliation started", zap.Bool("dry_run", dryRun))
+
+	// collect versions of indexed entities.
+	versions, err := r.indexedVersions(ctx)
+	if nil != err {
+		return nil, errors.Wrap(err, "reconcile, list search index")
+	}
+
+	registered, err := r.registry.List(ctx)
+	if nil != err {
+		return nil, errors.Wrap(err, "reconcile, list entity registry")
+	}
+
+	entityIDs := make(map[string]struct{})
+	for id := range versions {
+		entityIDs[id] = struct{}{}
+	}
+	for _, id := range registered {
+		entityIDs[id] = struct{}{}
+	}
+
+	ids := make([]string, 0, len(entityIDs))
+	for id := range entityIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	isRegistered := make(map[string]struct{}, len(registered))
+	for _, id := range registered {
+		isRegistered[id] = struct{}{}
+	}
+
+	orphans := r.orphans
+	r.orphans = make(map[string]int64)
+	for _, id := range ids {
+		version, indexed := versions[id]
+		_, registered := isRegistered[id]
+		report.Checked++
+		if err = r.reconcileEntity(ctx, report, id, version, indexed, registered, orphans[id]); nil != err {
+			log.Error("reconcile entity", logger.EntityID(id), zap.Error(err))
+			report.Failed = append(report.Failed, id)
+		}
+	}
+
+	report.EndTime = util.UnixMilli()
+	log.Info("reconciliation completed",
+		zap.Bool("dry_run", dryRun),
+		zap.Int64("checked", report.Checked),
+		zap.Strings("reindexed", report.Reindexed),
+		zap.Strings("deleted", report.Deleted),
+		zap.Strings("failed", report.Failed))
+
+	r.lock.Lock()
+	r.report = report
+	r.lock.Unlock()
+	return report, nil
+}
+
+func (r *Reconciler) reconcileEntity(ctx context.Context, report *pb.ReconcileReport, id string, version int64, indexed, registered bool, firstSeen int64) error {
+	item, err := r.stateClient.GetState(ctx, EntityStateName, id)
+	if nil != err {
+		return errors.Wrap(err, "get entity state")
+	}
+
+	// orphan, entity deleted from state.
+	if nil == item || len(item.Value) == 0 {
+		// entity may be registered before its first state flush,
+		// keep it until it stays without state for the grace period.
+		if firstSeen == 0 {
+			firstSeen = report.StartTime
+		}
+		if report.StartTime-firstSeen < r.grace.Milliseconds() {
+			r.orphans[id] = firstSeen
+			log.Debug("orphan within grace period", logger.EntityID(id))
+			return nil
+		}
+		return r.removeOrphan(ctx, report, id, indexed, registered)
+	}
+
+	base, err := statem.DecodeBase(item.Value)
+	if nil != err {
+		return errors.Wrap(err, "decode entity state")
+	} else if base.DeletedAt > 0 {
+		// soft-deleted entity, state is kept until purged.
+		return r.removeOrphan(ctx, report, id, indexed, registered)
+	}
+
+	if !registered && !report.DryRun {
+		if err = r.registry.Register(ctx, id, base.Type); nil != err {
+			return errors.Wrap(err, "register entity")
+		}
+	}
+
+	// stale or missing document.
+	if !indexed || version < base.Version {
+		report.Reindexed = append(report.Reindexed, id)
+		if !report.DryRun {
+			var val *structpb.Value
+			if val, err = structpb.NewValue(base.SearchValues()); nil != err {
+				return errors.Wrap(err, "reindex entity")
+			} else if _, err = r.searchClient.Index(ctx, &pb.IndexObject{Obj: val}); nil != err {
+				return errors.Wrap(err, "reindex entity")
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeOrphan deletes document and registry of entity deleted from state.
+func (r *Reconciler) removeOrphan(ctx context.Context, report *pb.ReconcileReport, id string, indexed, registered bool) error {
+	if indexed {
+		report.Deleted = append(report.Deleted, id)
+		if !report.DryRun {
+			if _, err := r.searchClient.DeleteByID(ctx, &pb.DeleteByIDRequest{Id: id}); nil != err {
+				return errors.Wrap(err, "delete orphan document")
+			}
+		}
+	}
+	if registered && !report.DryRun {
+		return errors.Wrap(r.registry.Unregister(ctx, id), "unregister entity")
+	}
+	return nil
+}
+
+// indexedVersions returns versions of all indexed entities, paging by id.
+func (r *Reconciler) indexedVersions(ctx context.Context) (map[string]int64, error) {
+	lastID := ""
+	versions := make(map[string]int64)
+	for {
+		req := &pb.SearchRequest{
+			PageNum:  1,
+			PageSize: searchPageSize,
+			Sort:     []*pb.SearchSort{{Field: "id"}},
+		}
+		if lastID != "" {
+			req.Condition = []*pb.SearchCondition{{
+				Field:    "id.keyword",
+				Operator: "$gt",
+				Value:    structpb.NewStringValue(lastID),
+			}}
+		}
+
+		resp, err := r.searchClient.Search(ctx, req)
+		if nil != err {
+			return nil, errors.Wrap(err, "search entities")
+		}
+
+		prevID := lastID
+		for _, item := range resp.Items {
+			fields := item.GetStructValue().GetFields()
+			id := fields["id"].GetStringValue()
+			if id == "" {
+				continue
+			}
+			versions[id] = int64(fields["version"].GetNumberValue())
+			lastID = id
+		}
+
+		if len(resp.Items) < searchPageSize || lastID == prevID {
+			return versions, nil
+		}
+	}
+}
+
+// etcdRegistry is entity registry stored in etcd.
+type etcdRegistry struct {
+	client clientv3.KV
+}
+
+func NewEtcdRegistry(client clientv3.KV) Registry {
+	return &etcdRegistry{client: client}
+}
+
+func (r *etcdRegistry) List(ctx context.Context) ([]string, error) {
+	prefix := util.FormatEntity("")
+	res, err := r.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if nil != err {
+		return nil, errors.Wrap(err, "list registered entities")
+	}
+
+	ids := make([]string, 0, len(res.Kvs))
+	for _, kv := range res.Kvs {
+		ids = append(ids, string(kv.Key)[len(prefix):])
+	}
+	return ids, nil
+}
+
+func (r *etcdRegistry) Register(ctx context.Context, id, typ string) error {
+	_, err := r.client.Put(ctx, util.FormatEntity(id), typ)
+	return errors.Wrap(err, "register entity")
+}
+
+func (r *etcdRegistry) Unregister(ctx context.Context, id string) error {
+	_, err := r.client.Delete(ctx, util.FormatEntity(id))
+	return errors.Wrap(err, "unregister entity")
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/statem"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type fakeRegistry map[string]string
+
+func (f fakeRegistry) List(ctx context.Context) ([]string, error) {
+	var ids []string
+	for id := range f {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (f fakeRegistry) Register(ctx context.Context, id, typ string) error {
+	f[id] = typ
+	return nil
+}
+
+func (f fakeRegistry) Unregister(ctx context.Context, id string) error {
+	delete(f, id)
+	return nil
+}
+
+func newBase(id string, version int64) *statem.Base {
+	return &statem.Base{
+		ID:      id,
+		Type:    "DEVICE",
+		Owner:   "admin",
+		Version: version,
+		KValues: map[string]constraint.Node{"temp": constraint.IntNode(20)},
+		Configs: map[string]constraint.Config{},
+	}
+}
+
+func index(t *testing.T, searchClient pb.SearchHTTPServer, base *statem.Base) {
+	val, err := structpb.NewValue(base.SearchValues())
+	assert.Nil(t, err)
+	_, err = searchClient.Index(context.Background(), &pb.IndexObject{Obj: val})
+	assert.Nil(t, err)
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	searchClient := fake.NewSearch(t)
+
+	state, registry := fake.NewState(), fakeRegistry{}
+	for _, base := range []*statem.Base{newBase("synced", 3), newBase("stale", 5), newBase("missing", 1)} {
+		bytes, err := statem.EncodeBase(base)
+		assert.Nil(t, err)
+		state.Set(base.ID, bytes)
+	}
+	deleted := newBase("soft-deleted", 2)
+	deleted.DeletedAt = 1000
+	bytes, err := statem.EncodeBase(deleted)
+	assert.Nil(t, err)
+	state.Set(deleted.ID, bytes)
+	registry["missing"] = "DEVICE"
+	registry["deleted"] = "DEVICE"
+
+	index(t, searchClient, newBase("synced", 3))
+	index(t, searchClient, newBase("stale", 2))
+	index(t, searchClient, newBase("orphan", 1))
+	index(t, searchClient, deleted)
+
+	r := NewReconciler(context.Background(), state, registry, searchClient, config.Reconciler{})
+
+	// dry run.
+	report, err := r.Reconcile(context.Background(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), report.Checked)
+	assert.Equal(t, []string{"missing", "stale"}, report.Reindexed)
+	assert.Equal(t, []string{"orphan", "soft-deleted"}, report.Deleted)
+	assert.Empty(t, report.Failed)
+	assert.Len(t, registry, 2)
+
+	// fix.
+	report, err = r.Reconcile(context.Background(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"missing", "stale"}, report.Reindexed)
+	assert.Equal(t, []string{"orphan", "soft-deleted"}, report.Deleted)
+	assert.Equal(t, report, r.LastReport())
+	assert.Equal(t, fakeRegistry{"synced": "DEVICE", "stale": "DEVICE", "missing": "DEVICE"}, registry)
+
+	// consistent now.
+	report, err = r.Reconcile(context.Background(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), report.Checked)
+	assert.Empty(t, report.Reindexed)
+	assert.Empty(t, report.Deleted)
+}
+
+func TestReconciler_OrphanGrace(t *testing.T) {
+	searchClient := fake.NewSearch(t)
+
+	// registered, state not flushed yet.
+	state, registry := fake.NewState(), fakeRegistry{"creating": "DEVICE"}
+	r := NewReconciler(context.Background(), state, registry, searchClient, config.Reconciler{OrphanGrace: time.Minute})
+
+	report, err := r.Reconcile(context.Background(), false)
+	assert.Nil(t, err)
+	assert.Empty(t, report.Deleted)
+	assert.Contains(t, registry, "creating")
+	assert.Contains(t, r.orphans, "creating")
+
+	// grace period expired.
+	r.orphans["creating"] -= time.Minute.Milliseconds()
+	_, err = r.Reconcile(context.Background(), false)
+	assert.Nil(t, err)
+	assert.NotContains(t, registry, "creating")
+	assert.Empty(t, r.orphans)
+}
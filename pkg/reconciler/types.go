@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+
+	dapr "github.com/dapr/go-sdk/client"
+)
+
+const (
+	EntityStateName = "core-state"
+	searchPageSize  = 500
+)
+
+type StateClient interface {
+	// GetState returns entity state.
+	GetState(ctx context.Context, storeName, key string) (*dapr.StateItem, error)
+}
+
+type Registry interface {
+	// List returns ids of registered entities.
+	List(ctx context.Context) ([]string, error)
+	// Register register entity.
+	Register(ctx context.Context, id, typ string) error
+	// Unregister unregister entity.
+	Unregister(ctx context.Context, id string) error
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package relation
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Traverse returns entities reachable from entity in breadth-first order, e.g. all devices in a room
+// recursively. an entity is returned once, with the shortest depth.
+func Traverse(ctx context.Context, g Graph, from, typ, direction string, maxDepth int) ([]Node, error) {
+	if err := checkDirection(direction); nil != err {
+		return nil, err
+	}
+
+	var nodes []Node
+	visited := map[string]struct{}{from: {}}
+	current := []string{from}
+	for depth := 1; depth <= depthLimit(maxDepth) && len(current) > 0; depth++ {
+		var next []string
+		for _, id := range current {
+			edges, err := g.Edges(ctx, id, typ, direction)
+			if nil != err {
+				return nil, errors.Wrapf(err, "traverse edges of %s", id)
+			}
+
+			for _, edge := range edges {
+				neighbor := neighborOf(edge, id)
+				if _, has := visited[neighbor]; has {
+					continue
+				}
+				visited[neighbor] = struct{}{}
+				nodes = append(nodes, Node{ID: neighbor, Depth: depth, Edge: edge})
+				next = append(next, neighbor)
+			}
+		}
+		current = next
+	}
+	return nodes, nil
+}
+
+// Path returns the shortest path of edges from entity to another, e.g. path between two switches.
+func Path(ctx context.Context, g Graph, from, to, typ, direction string, maxDepth int) ([]Edge, error) {
+	if err := checkDirection(direction); nil != err {
+		return nil, err
+	} else if from == to {
+		return []Edge{}, nil
+	}
+
+	// edge through which each entity is reached.
+	via := map[string]Edge{}
+	visited := map[string]struct{}{from: {}}
+	current := []string{from}
+	for depth := 1; depth <= depthLimit(maxDepth) && len(current) > 0; depth++ {
+		var next []string
+		for _, id := range current {
+			edges, err := g.Edges(ctx, id, typ, direction)
+			if nil != err {
+				return nil, errors.Wrapf(err, "find path, edges of %s", id)
+			}
+
+			for _, edge := range edges {
+				neighbor := neighborOf(edge, id)
+				if _, has := visited[neighbor]; has {
+					continue
+				}
+				visited[neighbor] = struct{}{}
+				via[neighbor] = edge
+				if neighbor == to {
+					return backtrack(via, from, to), nil
+				}
+				next = append(next, neighbor)
+			}
+		}
+		current = next
+	}
+	return nil, ErrPathNotFound
+}
+
+func backtrack(via map[string]Edge, from, to string) []Edge {
+	var path []Edge
+	for id := to; id != from; {
+		edge := via[id]
+		path = append([]Edge{edge}, path...)
+		id = neighborOf(edge, id)
+	}
+	return path
+}
+
+// neighborOf returns the other end of edge.
+func neighborOf(edge Edge, id string) string {
+	if edge.Source == id {
+		return edge.Target
+	}
+	return edge.Source
+}
+
+func checkDirection(direction string) error {
+	switch direction {
+	case DirectionOut, DirectionIn, DirectionBoth:
+		return nil
+	default:
+		return errors.Wrap(ErrInvalidDirection, direction)
+	}
+}
+
+func depthLimit(maxDepth int) int {
+	if maxDepth <= 0 {
+		return DefaultMaxDepth
+	} else if maxDepth > maxDepthLimit {
+		return maxDepthLimit
+	}
+	return maxDepth
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package relation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGraph is edges keyed by source.
+type fakeGraph map[string][]Edge
+
+func (f fakeGraph) Edges(ctx context.Context, id, typ, direction string) ([]Edge, error) {
+	var edges []Edge
+	for source, outs := range f {
+		for _, edge := range outs {
+			if typ != "" && edge.Type != typ {
+				continue
+			}
+			if (source == id && direction != DirectionIn) || (edge.Target == id && direction != DirectionOut) {
+				edges = append(edges, edge)
+			}
+		}
+	}
+	return edges, nil
+}
+
+func newGraph(edges ...Edge) fakeGraph {
+	g := fakeGraph{}
+	for _, edge := range edges {
+		g[edge.Source] = append(g[edge.Source], edge)
+	}
+	return g
+}
+
+func TestTraverse(t *testing.T) {
+	g := newGraph(
+		Edge{Type: "contains", Source: "building1", Target: "room1"},
+		Edge{Type: "contains", Source: "room1", Target: "device1"},
+		Edge{Type: "contains", Source: "room1", Target: "device2"},
+		Edge{Type: "connected-to", Source: "device1", Target: "switch1"},
+	)
+
+	nodes, err := Traverse(context.Background(), g, "building1", "contains", DirectionOut, 0)
+	assert.Nil(t, err)
+	assert.Len(t, nodes, 3)
+	assert.Equal(t, Node{ID: "room1", Depth: 1, Edge: g["building1"][0]}, nodes[0])
+	assert.Equal(t, 2, nodes[2].Depth)
+
+	// children only.
+	nodes, err = Traverse(context.Background(), g, "building1", "contains", DirectionOut, 1)
+	assert.Nil(t, err)
+	assert.Len(t, nodes, 1)
+
+	// parents.
+	nodes, err = Traverse(context.Background(), g, "device1", "contains", DirectionIn, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"room1", "building1"}, []string{nodes[0].ID, nodes[1].ID})
+
+	_, err = Traverse(context.Background(), g, "device1", "", "up", 0)
+	assert.ErrorIs(t, err, ErrInvalidDirection)
+}
+
+func TestPath(t *testing.T) {
+	g := newGraph(
+		Edge{Type: "connected-to", Source: "switch1", Target: "switch2"},
+		Edge{Type: "connected-to", Source: "switch2", Target: "switch3"},
+		Edge{Type: "connected-to", Source: "switch4", Target: "switch3"},
+		Edge{Type: "connected-to", Source: "switch1", Target: "switch5"},
+	)
+
+	path, err := Path(context.Background(), g, "switch1", "switch4", "connected-to", DirectionBoth, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []Edge{g["switch1"][0], g["switch2"][0], g["switch4"][0]}, path)
+
+	_, err = Path(context.Background(), g, "switch1", "switch4", "connected-to", DirectionOut, 0)
+	assert.ErrorIs(t, err, ErrPathNotFound)
+
+	_, err = Path(context.Background(), g, "switch1", "switch3", "connected-to", DirectionOut, 1)
+	assert.ErrorIs(t, err, ErrPathNotFound)
+
+	path, err = Path(context.Background(), g, "switch1", "switch1", "", DirectionOut, 0)
+	assert.Nil(t, err)
+	assert.Empty(t, path)
+}
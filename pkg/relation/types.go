@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package relation
+
+import (
+	"context"
+	"errors"
+)
+
+// directions of edges from an entity.
+const (
+	DirectionOut  = "out"
+	DirectionIn   = "in"
+	DirectionBoth = "both"
+)
+
+const (
+	// DefaultMaxDepth is the max depth of traversal if not specified.
+	DefaultMaxDepth = 10
+	// maxDepthLimit caps depth of traversal.
+	maxDepthLimit = 100
+)
+
+var (
+	ErrInvalidDirection = errors.New("invalid direction, one of out|in|both")
+	ErrPathNotFound     = errors.New("path not found")
+)
+
+// Edge is a typed, directed relationship from Source to Target.
+type Edge struct {
+	Type       string
+	Source     string
+	Target     string
+	Properties map[string]interface{}
+}
+
+// Node is an entity reached by traversal.
+type Node struct {
+	ID    string
+	Depth int
+	// Edge is the edge through which the node is reached.
+	Edge Edge
+}
+
+// Graph returns edges of entities.
+type Graph interface {
+	// Edges returns edges of the type connected to entity in direction, all types if typ empty.
+	Edges(ctx context.Context, id, typ, direction string) ([]Edge, error)
+}
@@ -2,9 +2,19 @@ package driver
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+var (
+	ErrConditionInvalid       = errors.New("invalid search condition")
+	ErrAggregationTypeInvalid = errors.New("invalid aggregation type")
+	ErrClusterUnhealthy       = errors.New("search cluster unhealthy")
+	ErrEngineConfigInvalid    = errors.New("invalid search engine config")
+	ErrMappingConflicted      = errors.New("property type conflicted with index mapping")
 )
 
 type Type string
@@ -13,6 +23,11 @@ type SearchEngine interface {
 	BuildIndex(ctx context.Context, index, content string) error
 	Search(ctx context.Context, request SearchRequest) (SearchResponse, error)
 	Delete(ctx context.Context, id string) error
+	UpdateMapping(ctx context.Context, entityType string, configs map[string]constraint.Config) error
+	// Ping checks the engine is reachable.
+	Ping(ctx context.Context) error
+	// Close releases the engine, pending writes are flushed.
+	Close() error
 }
 
 type SelectDriveOption func() Type
@@ -25,23 +40,28 @@ func Parse(drive string) SelectDriveOption {
 	switch strings.ToLower(drive) {
 	case "elasticsearch", "es":
 		return Elasticsearch
+	case "embedded":
+		return Embedded
 	default:
 		return NoopDriver
 	}
 }
 
 type SearchRequest struct {
-	Source    string                `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
-	Owner     string                `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
-	Query     string                `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
-	Page      *pb.Pager             `protobuf:"bytes,4,opt,name=page,proto3" json:"page,omitempty"`
-	Condition []*pb.SearchCondition `protobuf:"bytes,5,rep,name=condition,proto3" json:"condition,omitempty"`
+	Source       string                  `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Owner        string                  `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Query        string                  `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	Page         *pb.Pager               `protobuf:"bytes,4,opt,name=page,proto3" json:"page,omitempty"`
+	Condition    []*pb.SearchCondition   `protobuf:"bytes,5,rep,name=condition,proto3" json:"condition,omitempty"`
+	Sort         []*pb.SearchSort        `protobuf:"bytes,6,rep,name=sort,proto3" json:"sort,omitempty"`
+	Aggregations []*pb.SearchAggregation `protobuf:"bytes,7,rep,name=aggregations,proto3" json:"aggregations,omitempty"`
 }
 
 type SearchResponse struct {
-	Total  int64                    `json:"total,omitempty"`
-	Data   []map[string]interface{} `json:"data,omitempty"`
-	Raw    []byte                   `json:"raw,omitempty"`
-	Limit  int64                    `json:"limit"`
-	Offset int64                    `json:"offset"`
+	Total        int64                    `json:"total,omitempty"`
+	Data         []map[string]interface{} `json:"data,omitempty"`
+	Raw          []byte                   `json:"raw,omitempty"`
+	Limit        int64                    `json:"limit"`
+	Offset       int64                    `json:"offset"`
+	Aggregations map[string]interface{}   `json:"aggregations,omitempty"`
 }
@@ -0,0 +1,622 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/constraint"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+)
+
+const EmbeddedDriver Type = "embedded"
+
+const (
+	embeddedSnapshotFile  = "entities.json"
+	embeddedFlushInterval = time.Second
+	earthRadiusMeters     = 6371008.8
+)
+
+// EmbeddedClient is an in-memory inverted index persisted to disk,
+// used when running core without elasticsearch.
+type EmbeddedClient struct {
+	path  string
+	dirty bool
+	lock  sync.RWMutex
+	docs  map[string]map[string]interface{}
+	terms map[string]map[string]struct{}
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewEmbeddedEngine(cfg config.EmbeddedConfig) (SearchEngine, error) {
+	client, err := newEmbeddedClient(cfg.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "new embedded search engine")
+	}
+
+	log.Info("use EmbeddedDriver, path:", cfg.Path)
+	if cfg.Path != "" {
+		client.stop = make(chan struct{})
+		client.done = make(chan struct{})
+		go client.persistLoop()
+	}
+	return client, nil
+}
+
+func newEmbeddedClient(path string) (*EmbeddedClient, error) {
+	client := &EmbeddedClient{
+		path:  path,
+		docs:  make(map[string]map[string]interface{}),
+		terms: make(map[string]map[string]struct{}),
+	}
+
+	if path == "" {
+		return client, nil
+	}
+
+	bytes, err := ioutil.ReadFile(filepath.Join(path, embeddedSnapshotFile))
+	if os.IsNotExist(err) {
+		return client, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "load embedded search index")
+	}
+
+	var docs map[string]map[string]interface{}
+	if err = json.Unmarshal(bytes, &docs); err != nil {
+		return nil, errors.Wrap(err, "decode embedded search index")
+	}
+	for id, doc := range docs {
+		client.put(id, doc)
+	}
+	return client, nil
+}
+
+func (ec *EmbeddedClient) BuildIndex(ctx context.Context, id, body string) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return errors.Wrap(err, "decode index body")
+	}
+
+	ec.lock.Lock()
+	defer ec.lock.Unlock()
+	ec.remove(id)
+	ec.put(id, doc)
+	ec.dirty = true
+	return nil
+}
+
+func (ec *EmbeddedClient) Delete(ctx context.Context, id string) error {
+	ec.lock.Lock()
+	defer ec.lock.Unlock()
+	ec.remove(id)
+	ec.dirty = true
+	return nil
+}
+
+// UpdateMapping embedded index is schemaless, every property is searchable.
+func (ec *EmbeddedClient) UpdateMapping(ctx context.Context, entityType string, configs map[string]constraint.Config) error {
+	return nil
+}
+
+// Ping always succeeds, the index lives in process.
+func (ec *EmbeddedClient) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (ec *EmbeddedClient) Search(ctx context.Context, req SearchRequest) (SearchResponse, error) {
+	resp := SearchResponse{}
+	ec.lock.RLock()
+	defer ec.lock.RUnlock()
+
+	var ids []string
+	if req.Query != "" {
+		ids = ec.lookup(req.Query)
+	} else {
+		for id := range ec.docs {
+			ids = append(ids, id)
+		}
+	}
+
+	var matched []map[string]interface{}
+	for _, id := range ids {
+		doc := ec.docs[id]
+		ok, err := matchConditions(doc, req.Condition)
+		if err != nil {
+			return resp, errors.Wrap(err, "parse search condition")
+		} else if ok {
+			matched = append(matched, doc)
+		}
+	}
+
+	req.Page = defaultPage(req.Page)
+	sortDocs(matched, req.Page, req.Sort)
+
+	if len(req.Aggregations) > 0 {
+		resp.Aggregations = make(map[string]interface{})
+		for _, agg := range req.Aggregations {
+			result, err := aggregate(matched, agg)
+			if err != nil {
+				return resp, errors.Wrap(err, "parse search aggregation")
+			}
+			resp.Aggregations[agg.Name] = result
+		}
+	}
+
+	start, end := int(req.Page.Offset), int(req.Page.Offset+req.Page.Limit)
+	if start > len(matched) {
+		start = len(matched)
+	}
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	for _, doc := range matched[start:end] {
+		item := make(map[string]interface{}, len(doc))
+		for key, val := range doc {
+			item[key] = val
+		}
+		resp.Data = append(resp.Data, item)
+	}
+
+	resp.Total = int64(len(matched))
+	resp.Raw, _ = json.Marshal(resp.Data)
+	resp.Limit = req.Page.Limit
+	resp.Offset = req.Page.Offset
+	return resp, nil
+}
+
+func Embedded() Type {
+	return EmbeddedDriver
+}
+
+func (ec *EmbeddedClient) put(id string, doc map[string]interface{}) {
+	ec.docs[id] = doc
+	for _, term := range docTerms(doc) {
+		if _, ok := ec.terms[term]; !ok {
+			ec.terms[term] = make(map[string]struct{})
+		}
+		ec.terms[term][id] = struct{}{}
+	}
+}
+
+func (ec *EmbeddedClient) remove(id string) {
+	doc, ok := ec.docs[id]
+	if !ok {
+		return
+	}
+
+	for _, term := range docTerms(doc) {
+		delete(ec.terms[term], id)
+		if len(ec.terms[term]) == 0 {
+			delete(ec.terms, term)
+		}
+	}
+	delete(ec.docs, id)
+}
+
+// lookup returns documents which contain any term of the query string.
+func (ec *EmbeddedClient) lookup(query string) []string {
+	var ids []string
+	hits := make(map[string]struct{})
+	for _, term := range tokenize(query) {
+		for id := range ec.terms[term] {
+			if _, ok := hits[id]; !ok {
+				hits[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// Close stops the persist loop and writes pending changes to disk.
+func (ec *EmbeddedClient) Close() error {
+	ec.closeOnce.Do(func() {
+		if ec.stop != nil {
+			close(ec.stop)
+			<-ec.done
+		}
+	})
+	return errors.Wrap(ec.persist(), "close embedded search engine")
+}
+
+func (ec *EmbeddedClient) persistLoop() {
+	defer close(ec.done)
+	ticker := time.NewTicker(embeddedFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ec.stop:
+			return
+		case <-ticker.C:
+			if err := ec.persist(); err != nil {
+				log.Error("persist embedded search index", zap.String("path", ec.path), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (ec *EmbeddedClient) persist() error {
+	ec.lock.Lock()
+	if ec.path == "" || !ec.dirty {
+		ec.lock.Unlock()
+		return nil
+	}
+	bytes, err := json.Marshal(ec.docs)
+	ec.dirty = false
+	ec.lock.Unlock()
+
+	if err != nil {
+		return errors.Wrap(err, "encode embedded search index")
+	} else if err = os.MkdirAll(ec.path, 0755); err != nil {
+		return errors.Wrap(err, "create embedded search index path")
+	}
+
+	// write snapshot atomically.
+	filename := filepath.Join(ec.path, embeddedSnapshotFile)
+	if err = ioutil.WriteFile(filename+".tmp", bytes, 0600); err != nil {
+		return errors.Wrap(err, "write embedded search index")
+	}
+	return errors.Wrap(os.Rename(filename+".tmp", filename), "write embedded search index")
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func docTerms(doc map[string]interface{}) []string {
+	var terms []string
+	seen := make(map[string]struct{})
+	var walk func(val interface{})
+	walk = func(val interface{}) {
+		switch v := val.(type) {
+		case map[string]interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case nil:
+		default:
+			for _, term := range tokenize(fmt.Sprint(v)) {
+				if _, ok := seen[term]; !ok {
+					seen[term] = struct{}{}
+					terms = append(terms, term)
+				}
+			}
+		}
+	}
+	walk(doc)
+	return terms
+}
+
+// fieldValues returns values of the field path, array values are expanded.
+func fieldValues(doc map[string]interface{}, field string) []interface{} {
+	field = strings.TrimSuffix(field, ".keyword")
+	if val, ok := doc[field]; ok {
+		return expandValues(val)
+	}
+
+	segs := strings.Split(field, ".")
+	current := []interface{}{doc}
+	for _, seg := range segs {
+		var next []interface{}
+		for _, item := range current {
+			if kv, ok := item.(map[string]interface{}); ok {
+				if val, has := kv[seg]; has {
+					next = append(next, expandValues(val)...)
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+func expandValues(val interface{}) []interface{} {
+	if arr, ok := val.([]interface{}); ok {
+		return arr
+	} else if val == nil {
+		return nil
+	}
+	return []interface{}{val}
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// compareValues compare a with b, numeric string is converted if the other one is number.
+func compareValues(a, b interface{}) (int, bool) {
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	if aNum && !bNum {
+		if s, ok := b.(string); ok {
+			f, err := strconv.ParseFloat(s, 64)
+			bf, bNum = f, err == nil
+		}
+	} else if !aNum && bNum {
+		if s, ok := a.(string); ok {
+			f, err := strconv.ParseFloat(s, 64)
+			af, aNum = f, err == nil
+		}
+	}
+
+	switch {
+	case aNum && bNum:
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case aNum || bNum:
+		return 0, false
+	}
+
+	as, aStr := a.(string)
+	bs, bStr := b.(string)
+	if aStr && bStr {
+		return strings.Compare(as, bs), true
+	}
+
+	ab, aBool := a.(bool)
+	bb, bBool := b.(bool)
+	if aBool && bBool && ab == bb {
+		return 0, true
+	}
+	return 0, false
+}
+
+func anyValue(values []interface{}, fn func(interface{}) bool) bool {
+	for _, val := range values {
+		if fn(val) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchConditions(doc map[string]interface{}, conditions []*pb.SearchCondition) (bool, error) {
+	for _, condition := range conditions {
+		ok, err := matchCondition(doc, condition)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchCondition(doc map[string]interface{}, condition *pb.SearchCondition) (bool, error) { //nolint
+	values := fieldValues(doc, condition.Field)
+	compare := func(fn func(int) bool) bool {
+		return anyValue(values, func(val interface{}) bool {
+			ret, ok := compareValues(val, condition.Value.AsInterface())
+			return ok && fn(ret)
+		})
+	}
+
+	switch condition.Operator {
+	case "$lt":
+		return compare(func(ret int) bool { return ret < 0 }), nil
+	case "$lte":
+		return compare(func(ret int) bool { return ret <= 0 }), nil
+	case "$gt":
+		return compare(func(ret int) bool { return ret > 0 }), nil
+	case "$gte":
+		return compare(func(ret int) bool { return ret >= 0 }), nil
+	case "$eq":
+		return compare(func(ret int) bool { return ret == 0 }), nil
+	case "$ne", "$neq":
+		return !compare(func(ret int) bool { return ret == 0 }), nil
+	case "$in":
+		targets := condition.Value.GetListValue().AsSlice()
+		if len(targets) == 0 {
+			return false, errors.Wrap(ErrConditionInvalid, "$in requires a list value")
+		}
+		return anyValue(values, func(val interface{}) bool {
+			return anyValue(targets, func(target interface{}) bool {
+				ret, ok := compareValues(val, target)
+				return ok && ret == 0
+			})
+		}), nil
+	case "$prefix":
+		prefix := condition.Value.GetStringValue()
+		return anyValue(values, func(val interface{}) bool {
+			s, ok := val.(string)
+			return ok && strings.HasPrefix(s, prefix)
+		}), nil
+	case "$wildcard":
+		pattern := regexp.QuoteMeta(condition.Value.GetStringValue())
+		pattern = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(pattern)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, errors.Wrap(ErrConditionInvalid, err.Error())
+		}
+		return anyValue(values, func(val interface{}) bool {
+			s, ok := val.(string)
+			return ok && re.MatchString(s)
+		}), nil
+	case "$exists":
+		exists := len(values) > 0
+		if flag, ok := condition.Value.AsInterface().(bool); ok && !flag {
+			return !exists, nil
+		}
+		return exists, nil
+	case "$geo_distance":
+		return matchGeoDistance(values, condition)
+	case "$and":
+		return matchConditions(doc, condition.Conditions)
+	case "$or":
+		for _, cond := range condition.Conditions {
+			if ok, err := matchCondition(doc, cond); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	default:
+		terms := tokenize(fmt.Sprint(condition.Value.AsInterface()))
+		return anyValue(values, func(val interface{}) bool {
+			valTerms := tokenize(fmt.Sprint(val))
+			for _, term := range terms {
+				for _, valTerm := range valTerms {
+					if term == valTerm {
+						return true
+					}
+				}
+			}
+			return false
+		}), nil
+	}
+}
+
+func matchGeoDistance(values []interface{}, condition *pb.SearchCondition) (bool, error) {
+	fields := condition.Value.GetStructValue().GetFields()
+	lat, hasLat := fields["lat"]
+	lon, hasLon := fields["lon"]
+	distance, hasDistance := fields["distance"]
+	if !hasLat || !hasLon || !hasDistance {
+		return false, errors.Wrap(ErrConditionInvalid, "$geo_distance requires lat, lon and distance")
+	}
+
+	meters, err := parseDistance(distance.GetStringValue())
+	if err != nil {
+		return false, err
+	}
+
+	// geo point is stored as {"lat": 30.1, "lon": 120.2}.
+	return anyValue(values, func(val interface{}) bool {
+		point, ok := val.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		pointLat, okLat := toFloat(point["lat"])
+		pointLon, okLon := toFloat(point["lon"])
+		return okLat && okLon && haversine(lat.GetNumberValue(), lon.GetNumberValue(), pointLat, pointLon) <= meters
+	}), nil
+}
+
+var distanceUnits = []struct {
+	suffix string
+	meters float64
+}{
+	{"km", 1000},
+	{"mi", 1609.344},
+	{"m", 1},
+}
+
+func parseDistance(distance string) (float64, error) {
+	for _, unit := range distanceUnits {
+		if strings.HasSuffix(distance, unit.suffix) {
+			value, err := strconv.ParseFloat(strings.TrimSuffix(distance, unit.suffix), 64)
+			if err != nil {
+				return 0, errors.Wrap(ErrConditionInvalid, "invalid distance "+distance)
+			}
+			return value * unit.meters, nil
+		}
+	}
+
+	value, err := strconv.ParseFloat(distance, 64)
+	if err != nil {
+		return 0, errors.Wrap(ErrConditionInvalid, "invalid distance "+distance)
+	}
+	return value, nil
+}
+
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
+
+func sortDocs(docs []map[string]interface{}, page *pb.Pager, sorts []*pb.SearchSort) {
+	var orders []*pb.SearchSort
+	for _, s := range sorts {
+		if s.Field != "" {
+			orders = append(orders, s)
+		}
+	}
+	if len(orders) == 0 && page.Sort != "" {
+		orders = append(orders, &pb.SearchSort{Field: page.Sort, IsDescending: page.Reverse})
+	}
+	// ties broken by id, pages are stable across searches.
+	orders = append(orders, &pb.SearchSort{Field: sortFieldID})
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, order := range orders {
+			a, b := fieldValues(docs[i], order.Field), fieldValues(docs[j], order.Field)
+			// missing values sort last.
+			switch {
+			case len(a) == 0 && len(b) == 0:
+				continue
+			case len(a) == 0:
+				return false
+			case len(b) == 0:
+				return true
+			}
+
+			if ret, ok := compareValues(a[0], b[0]); ok && ret != 0 {
+				return (ret < 0) != order.IsDescending
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/tkeel-io/core/api/core/v1"
+
+	"github.com/pkg/errors"
+)
+
+const defaultTermsSize = 10
+
+type bucket struct {
+	key  interface{}
+	docs []map[string]interface{}
+}
+
+// aggregate docs, results are formatted as elasticsearch aggregation results.
+func aggregate(docs []map[string]interface{}, agg *pb.SearchAggregation) (map[string]interface{}, error) {
+	if agg.Name == "" || agg.Field == "" {
+		return nil, errors.Wrap(ErrAggregationTypeInvalid, "aggregation name and field required")
+	}
+
+	switch agg.Type {
+	case "terms":
+		return aggregateTerms(docs, agg)
+	case "date_histogram":
+		return aggregateDateHistogram(docs, agg)
+	}
+
+	if len(agg.Aggregations) > 0 {
+		return nil, errors.Wrap(ErrAggregationTypeInvalid, "sub aggregations only supported by bucket aggregation")
+	}
+
+	count, sum, min, max := 0, 0.0, math.Inf(1), math.Inf(-1)
+	distinct := make(map[string]struct{})
+	for _, doc := range docs {
+		for _, val := range fieldValues(doc, agg.Field) {
+			distinct[fmt.Sprint(val)] = struct{}{}
+			if f, ok := toFloat(val); ok {
+				count++
+				sum += f
+				min = math.Min(min, f)
+				max = math.Max(max, f)
+			}
+		}
+	}
+
+	var avg interface{}
+	if count > 0 {
+		avg = sum / float64(count)
+	}
+
+	switch agg.Type {
+	case "stats":
+		if count == 0 {
+			return map[string]interface{}{"count": 0, "min": nil, "max": nil, "avg": nil, "sum": 0.0}, nil
+		}
+		return map[string]interface{}{"count": count, "min": min, "max": max, "avg": avg, "sum": sum}, nil
+	case "avg":
+		return map[string]interface{}{"value": avg}, nil
+	case "min":
+		if count == 0 {
+			return map[string]interface{}{"value": nil}, nil
+		}
+		return map[string]interface{}{"value": min}, nil
+	case "max":
+		if count == 0 {
+			return map[string]interface{}{"value": nil}, nil
+		}
+		return map[string]interface{}{"value": max}, nil
+	case "sum":
+		return map[string]interface{}{"value": sum}, nil
+	case "cardinality":
+		return map[string]interface{}{"value": len(distinct)}, nil
+	default:
+		return nil, errors.Wrap(ErrAggregationTypeInvalid, agg.Type)
+	}
+}
+
+func aggregateTerms(docs []map[string]interface{}, agg *pb.SearchAggregation) (map[string]interface{}, error) {
+	var buckets []*bucket
+	index := make(map[string]*bucket)
+	for _, doc := range docs {
+		for _, val := range fieldValues(doc, agg.Field) {
+			key := fmt.Sprint(val)
+			if _, ok := index[key]; !ok {
+				index[key] = &bucket{key: val}
+				buckets = append(buckets, index[key])
+			}
+			index[key].docs = append(index[key].docs, doc)
+		}
+	}
+
+	sort.SliceStable(buckets, func(i, j int) bool {
+		if len(buckets[i].docs) != len(buckets[j].docs) {
+			return len(buckets[i].docs) > len(buckets[j].docs)
+		}
+		return fmt.Sprint(buckets[i].key) < fmt.Sprint(buckets[j].key)
+	})
+
+	size := int(agg.Size)
+	if size <= 0 {
+		size = defaultTermsSize
+	}
+
+	others := 0
+	if len(buckets) > size {
+		for _, b := range buckets[size:] {
+			others += len(b.docs)
+		}
+		buckets = buckets[:size]
+	}
+
+	results, err := formatBuckets(buckets, agg.Aggregations)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"doc_count_error_upper_bound": 0,
+		"sum_other_doc_count":         others,
+		"buckets":                     results,
+	}, nil
+}
+
+func aggregateDateHistogram(docs []map[string]interface{}, agg *pb.SearchAggregation) (map[string]interface{}, error) {
+	interval, err := parseInterval(agg.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []*bucket
+	index := make(map[int64]*bucket)
+	for _, doc := range docs {
+		for _, val := range fieldValues(doc, agg.Field) {
+			// date field is unix timestamp in milliseconds.
+			ts, ok := toFloat(val)
+			if !ok {
+				continue
+			}
+			key := int64(ts) - int64(ts)%interval.Milliseconds()
+			if _, has := index[key]; !has {
+				index[key] = &bucket{key: key}
+				buckets = append(buckets, index[key])
+			}
+			index[key].docs = append(index[key].docs, doc)
+		}
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].key.(int64) < buckets[j].key.(int64)
+	})
+
+	results, err := formatBuckets(buckets, agg.Aggregations)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, result := range results {
+		key := buckets[index].key.(int64)
+		result.(map[string]interface{})["key_as_string"] = time.Unix(0, key*int64(time.Millisecond)).UTC().Format(time.RFC3339)
+	}
+	return map[string]interface{}{"buckets": results}, nil
+}
+
+func formatBuckets(buckets []*bucket, subAggs []*pb.SearchAggregation) ([]interface{}, error) {
+	results := make([]interface{}, 0, len(buckets))
+	for _, b := range buckets {
+		result := map[string]interface{}{
+			"key":       b.key,
+			"doc_count": len(b.docs),
+		}
+		for _, sub := range subAggs {
+			subResult, err := aggregate(b.docs, sub)
+			if err != nil {
+				return nil, err
+			}
+			result[sub.Name] = subResult
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// parseInterval parse fixed interval like 30s, 5m, 1h or 1d.
+func parseInterval(interval string) (time.Duration, error) {
+	if interval == "" {
+		return time.Hour, nil
+	}
+
+	var (
+		err      error
+		duration time.Duration
+	)
+	if strings.HasSuffix(interval, "d") {
+		var days int
+		if days, err = strconv.Atoi(strings.TrimSuffix(interval, "d")); err == nil {
+			duration = time.Duration(days) * 24 * time.Hour
+		}
+	} else {
+		duration, err = time.ParseDuration(interval)
+	}
+
+	if err != nil || duration < time.Millisecond {
+		return 0, errors.Wrap(ErrAggregationTypeInvalid, "invalid interval "+interval)
+	}
+	return duration, nil
+}
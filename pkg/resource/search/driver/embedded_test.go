@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/config"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newTestEmbeddedClient(t *testing.T, path string) *EmbeddedClient {
+	client, err := newEmbeddedClient(path)
+	assert.Nil(t, err)
+
+	docs := map[string]string{
+		"device1": `{"id":"device1","type":"DEVICE","owner":"admin","temp":20,"name":"smoke sensor","location":{"lat":30.27,"lon":120.15},"ts":1640995200000}`,
+		"device2": `{"id":"device2","type":"DEVICE","owner":"admin","temp":35,"name":"temp sensor","location":{"lat":31.23,"lon":121.47},"ts":1640998800000}`,
+		"device3": `{"id":"device3","type":"DEVICE","owner":"tomas","temp":50,"name":"gateway","ts":1640998900000}`,
+		"space1":  `{"id":"space1","type":"SPACE","owner":"admin","name":"room 101"}`,
+	}
+	for id, body := range docs {
+		assert.Nil(t, client.BuildIndex(context.Background(), id, body))
+	}
+	return client
+}
+
+func searchIDs(t *testing.T, client *EmbeddedClient, req SearchRequest) []string {
+	resp, err := client.Search(context.Background(), req)
+	assert.Nil(t, err)
+
+	var ids []string
+	for _, item := range resp.Data {
+		ids = append(ids, item["id"].(string))
+	}
+	return ids
+}
+
+func TestEmbeddedClient_Search(t *testing.T) {
+	client := newTestEmbeddedClient(t, "")
+
+	// query string.
+	assert.Equal(t, []string{"device1", "device2"}, searchIDs(t, client, SearchRequest{Query: "sensor"}))
+
+	// conditions.
+	ids := searchIDs(t, client, SearchRequest{Condition: []*pb.SearchCondition{
+		{Field: "type", Operator: "$eq", Value: structpb.NewStringValue("DEVICE")},
+		{Field: "temp", Operator: "$gte", Value: structpb.NewNumberValue(30)},
+	}})
+	assert.Equal(t, []string{"device2", "device3"}, ids)
+
+	ids = searchIDs(t, client, SearchRequest{Condition: []*pb.SearchCondition{
+		{Field: "owner", Operator: "$ne", Value: structpb.NewStringValue("admin")},
+	}})
+	assert.Equal(t, []string{"device3"}, ids)
+
+	ids = searchIDs(t, client, SearchRequest{Condition: []*pb.SearchCondition{
+		{Operator: "$or", Conditions: []*pb.SearchCondition{
+			{Field: "name", Operator: "$prefix", Value: structpb.NewStringValue("room")},
+			{Field: "temp", Operator: "$lt", Value: structpb.NewNumberValue(25)},
+		}},
+	}})
+	assert.Equal(t, []string{"device1", "space1"}, ids)
+
+	ids = searchIDs(t, client, SearchRequest{Condition: []*pb.SearchCondition{
+		{Field: "location", Operator: "$exists", Value: structpb.NewBoolValue(false)},
+	}})
+	assert.Equal(t, []string{"device3", "space1"}, ids)
+
+	geoValue, _ := structpb.NewValue(map[string]interface{}{"lat": 30.25, "lon": 120.16, "distance": "10km"})
+	ids = searchIDs(t, client, SearchRequest{Condition: []*pb.SearchCondition{
+		{Field: "location", Operator: "$geo_distance", Value: geoValue},
+	}})
+	assert.Equal(t, []string{"device1"}, ids)
+
+	// sort and pagination.
+	resp, err := client.Search(context.Background(), SearchRequest{
+		Page: &pb.Pager{Offset: 1, Limit: 2},
+		Sort: []*pb.SearchSort{{Field: "temp", IsDescending: true}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), resp.Total)
+	assert.Len(t, resp.Data, 2)
+	assert.Equal(t, "device2", resp.Data[0]["id"])
+	assert.Equal(t, "device1", resp.Data[1]["id"])
+}
+
+func TestEmbeddedClient_Paging(t *testing.T) {
+	client := newTestEmbeddedClient(t, "")
+
+	// entities of the same type are paged in order of id, without overlapping or skipping.
+	for i := 0; i < 10; i++ {
+		var ids []string
+		for offset := int64(0); offset < 4; offset++ {
+			ids = append(ids, searchIDs(t, client, SearchRequest{
+				Page: &pb.Pager{Offset: offset, Limit: 1},
+				Sort: []*pb.SearchSort{{Field: "type"}},
+			})...)
+		}
+		assert.Equal(t, []string{"device1", "device2", "device3", "space1"}, ids)
+	}
+}
+
+func TestEmbeddedClient_Aggregations(t *testing.T) {
+	client := newTestEmbeddedClient(t, "")
+	resp, err := client.Search(context.Background(), SearchRequest{
+		Aggregations: []*pb.SearchAggregation{
+			{Name: "by_owner", Type: "terms", Field: "owner", Aggregations: []*pb.SearchAggregation{
+				{Name: "temp", Type: "max", Field: "temp"},
+			}},
+			{Name: "temp", Type: "stats", Field: "temp"},
+			{Name: "hourly", Type: "date_histogram", Field: "ts", Interval: "1h"},
+		},
+	})
+	assert.Nil(t, err)
+
+	byOwner := resp.Aggregations["by_owner"].(map[string]interface{})
+	buckets := byOwner["buckets"].([]interface{})
+	assert.Len(t, buckets, 2)
+	first := buckets[0].(map[string]interface{})
+	assert.Equal(t, "admin", first["key"])
+	assert.Equal(t, 3, first["doc_count"])
+	assert.Equal(t, map[string]interface{}{"value": 35.0}, first["temp"])
+
+	stats := resp.Aggregations["temp"].(map[string]interface{})
+	assert.Equal(t, 3, stats["count"])
+	assert.Equal(t, 35.0, stats["avg"])
+
+	hourly := resp.Aggregations["hourly"].(map[string]interface{})
+	assert.Len(t, hourly["buckets"], 2)
+
+	_, err = structpb.NewValue(resp.Aggregations)
+	assert.Nil(t, err)
+}
+
+func TestEmbeddedClient_Persist(t *testing.T) {
+	path := t.TempDir()
+	client := newTestEmbeddedClient(t, path)
+	assert.Nil(t, client.Delete(context.Background(), "space1"))
+	assert.Nil(t, client.persist())
+
+	reloaded, err := newEmbeddedClient(path)
+	assert.Nil(t, err)
+	assert.Len(t, reloaded.docs, 3)
+	assert.Equal(t, []string{"device3"}, searchIDs(t, reloaded, SearchRequest{Query: "gateway"}))
+}
+
+func TestEmbeddedClient_Close(t *testing.T) {
+	path := t.TempDir()
+	engine, err := NewEmbeddedEngine(config.EmbeddedConfig{Path: path})
+	assert.Nil(t, err)
+	assert.Nil(t, engine.BuildIndex(context.Background(), "device1", `{"id":"device1","type":"DEVICE","name":"gateway"}`))
+	assert.Nil(t, engine.Close())
+	assert.Nil(t, engine.Close())
+
+	reloaded, err := newEmbeddedClient(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"device1"}, searchIDs(t, reloaded, SearchRequest{Query: "gateway"}))
+}
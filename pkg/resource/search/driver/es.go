@@ -23,24 +23,47 @@ import (
 	"encoding/json"
 	"net/http"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	pb "github.com/tkeel-io/core/api/core/v1"
 	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/constraint"
 
 	"github.com/olivere/elastic/v7"
 	"github.com/pkg/errors"
 	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
 )
 
 const ElasticsearchDriver Type = "elasticsearch"
 
+// sortFieldID is the default sort field, also breaks ties of other sorts.
+const sortFieldID = "id"
+
+// EntityIndex is the alias of all entity indices, entities of each type
+// are written into the index aliased by typeIndex(type).
 const EntityIndex = "entity"
 
 type ESClient struct {
 	Client *elastic.Client
+
+	lock    sync.Mutex
+	indices sync.Map
+	// property configs of entity types merged from entities, key=index.
+	configs  map[string]map[string]constraint.Config
+	mappings map[string]string
+	// indices being reindexed, key=index.
+	reindexing map[string]bool
 }
 
-func NewElasticsearchEngine(config config.ESConfig) SearchEngine {
+func NewElasticsearchEngine(config config.ESConfig) (SearchEngine, error) {
+	if len(config.Address) == 0 {
+		return nil, errors.Wrap(ErrEngineConfigInvalid, "elasticsearch address required")
+	}
+
 	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint
 	client, err := elastic.NewClient(
 		elastic.SetURL(config.Address...),
@@ -48,33 +71,371 @@ func NewElasticsearchEngine(config config.ESConfig) SearchEngine {
 		elastic.SetBasicAuth(config.Username, config.Password),
 	)
 	if err != nil {
-		log.Fatal(err)
+		return nil, errors.Wrap(err, "create elasticsearch client")
 	}
 
 	// ping connection.
-	if len(config.Address) == 0 {
-		log.Fatal("please check your configuration with elasticsearch")
-	}
 	info, _, err := client.Ping(config.Address[0]).Do(context.Background())
 	if nil != err {
-		log.Fatal(err)
+		client.Stop()
+		return nil, errors.Wrap(err, "ping elasticsearch")
 	}
 	log.Info("use ElasticsearchDriver version:", info.Version.Number)
-	return &ESClient{Client: client}
+
+	es := &ESClient{
+		Client:     client,
+		configs:    make(map[string]map[string]constraint.Config),
+		mappings:   make(map[string]string),
+		reindexing: make(map[string]bool),
+	}
+	if err = es.migrateLegacyIndex(context.Background()); nil != err {
+		client.Stop()
+		return nil, errors.Wrap(err, "migrate legacy index")
+	}
+	return es, nil
+}
+
+// migrateLegacyIndex moves entities of the legacy index "entity" into indices
+// of their types, then "entity" is recreated as the alias of all entity indices.
+func (es *ESClient) migrateLegacyIndex(ctx context.Context) error {
+	result, err := es.Client.IndexGet(EntityIndex).Do(ctx)
+	if nil != err && !elastic.IsNotFound(err) {
+		return errors.Wrap(err, "get legacy index")
+	} else if _, legacy := result[EntityIndex]; legacy {
+		var res *elastic.SearchResult
+		aggregation := elastic.NewTermsAggregation().Field("type.keyword").Missing("").Size(10000)
+		if res, err = es.Client.Search(EntityIndex).Size(0).Aggregation("types", aggregation).Do(ctx); nil != err {
+			return errors.Wrap(err, "list types of legacy index")
+		}
+
+		terms, _ := res.Aggregations.Terms("types")
+		for _, bucket := range terms.Buckets {
+			entityType, _ := bucket.Key.(string)
+			if err = es.migrateType(ctx, entityType); nil != err {
+				return errors.Wrapf(err, "migrate entities of type %q", entityType)
+			}
+		}
+
+		if _, err = es.Client.DeleteIndex(EntityIndex).Do(ctx); nil != err {
+			return errors.Wrap(err, "delete legacy index")
+		}
+		log.Info("migrate legacy search index completed", zap.Int("types", len(terms.Buckets)))
+	}
+
+	// alias entity indices, also recovers migration interrupted after legacy index deleted.
+	if _, err = es.Client.Alias().Action(
+		elastic.NewAliasAddAction(EntityIndex).Index(EntityIndex + "_*"),
+	).Do(ctx); nil != err && !elastic.IsNotFound(err) {
+		return errors.Wrap(err, "alias entity indices")
+	}
+	return nil
+}
+
+// migrateType copies entities of the type from legacy index into index of the type.
+func (es *ESClient) migrateType(ctx context.Context, entityType string) error {
+	index := typeIndex(entityType)
+	if exists, err := es.Client.IndexExists(index).Do(ctx); nil != err {
+		return errors.Wrap(err, "check index exists")
+	} else if !exists {
+		// legacy index holds the name of entity alias, added after migrated.
+		if err = es.createIndexWithAliases(ctx, index+"_0", buildMapping(nil), map[string]interface{}{
+			index: map[string]interface{}{"is_write_index": true},
+		}); nil != err {
+			return errors.Wrap(err, "create index")
+		}
+	}
+
+	resp, err := es.Client.Reindex().
+		Source(elastic.NewReindexSource().Index(EntityIndex).Query(legacyTypeQuery(entityType))).
+		Destination(elastic.NewReindexDestination().Index(index)).
+		WaitForCompletion(true).
+		Refresh("true").
+		Do(ctx)
+	if nil != err {
+		return errors.Wrap(err, "reindex legacy entities")
+	}
+
+	log.Info("migrate legacy search index", zap.String("type", entityType),
+		zap.String("index", index), zap.Int64("created", resp.Created), zap.Int64("updated", resp.Updated))
+	return nil
+}
+
+// legacyTypeQuery matches entities of the type, entities without type if empty.
+func legacyTypeQuery(entityType string) elastic.Query {
+	query := elastic.NewTermQuery("type.keyword", entityType)
+	if entityType != "" {
+		return query
+	}
+	return elastic.NewBoolQuery().MinimumNumberShouldMatch(1).
+		Should(query, elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("type")))
+}
+
+// Ping checks cluster health, a red cluster is reported as unhealthy.
+func (es *ESClient) Ping(ctx context.Context) error {
+	health, err := es.Client.ClusterHealth().Do(ctx)
+	if nil != err {
+		return errors.Wrap(err, "elasticsearch cluster health")
+	} else if health.Status == "red" {
+		return errors.Wrap(ErrClusterUnhealthy, health.Status)
+	}
+	return nil
+}
+
+// Close stops the background processes of the elasticsearch client.
+func (es *ESClient) Close() error {
+	es.Client.Stop()
+	return nil
 }
 
 func (es *ESClient) BuildIndex(ctx context.Context, id, body string) error {
-	if _, err := es.Client.Index().Index(EntityIndex).Id(id).BodyString(body).Do(ctx); err != nil {
+	var entity struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(body), &entity); err != nil {
+		return errors.Wrap(err, "decode index body")
+	}
+
+	index := typeIndex(entity.Type)
+	if err := es.ensureIndex(ctx, index); err != nil {
+		return errors.Wrap(err, "set index in es error")
+	}
+	if _, err := es.Client.Index().Index(index).Id(id).BodyString(body).Do(ctx); err != nil {
 		return errors.Wrap(err, "set index in es error")
 	}
 	return nil
 }
 
 func (es *ESClient) Delete(ctx context.Context, id string) error {
-	_, err := es.Client.Delete().Index(EntityIndex).Id(id).Do(ctx)
+	_, err := es.Client.DeleteByQuery(EntityIndex + "_*").
+		Query(elastic.NewIdsQuery().Ids(id)).
+		IgnoreUnavailable(true).
+		AllowNoIndices(true).
+		Do(ctx)
 	return errors.Wrap(err, "elasticsearch delete by id")
 }
 
+// UpdateMapping update index mapping of the entity type from property configs,
+// configs are merged with configs of other entities of the type and kept in
+// the index mapping. a property keeps the type it is first mapped with, the
+// entities are reindexed into a new index only if the index mapping disagrees
+// with the merged configs, e.g. indices created by previous versions.
+func (es *ESClient) UpdateMapping(ctx context.Context, entityType string, configs map[string]constraint.Config) error {
+	index := typeIndex(entityType)
+
+	es.lock.Lock()
+	defer es.lock.Unlock()
+
+	current, ok := es.configs[index]
+	if !ok {
+		var err error
+		if current, err = es.loadConfigs(ctx, index); nil != err {
+			return errors.Wrap(err, "load property configs")
+		}
+	}
+
+	merged, conflicts := mergeConfigs(current, configs)
+	es.configs[index] = merged
+	if err := es.updateMapping(ctx, index); nil != err {
+		return err
+	} else if len(conflicts) > 0 {
+		return errors.Wrapf(ErrMappingConflicted, "properties %v of %s", conflicts, index)
+	}
+	return nil
+}
+
+// updateMapping applies mapping built from merged configs of the index,
+// applied after reindex completed if the index is being reindexed.
+func (es *ESClient) updateMapping(ctx context.Context, index string) error {
+	mapping := buildMapping(es.configs[index])
+	mappingBytes, err := json.Marshal(mapping)
+	if err != nil {
+		return errors.Wrap(err, "encode index mapping")
+	}
+
+	// skip mapping which already applied.
+	if applied, ok := es.mappings[index]; ok && applied == string(mappingBytes) {
+		return nil
+	} else if es.reindexing[index] {
+		return nil
+	}
+
+	// merged configs kept in the mapping, loaded after restarted.
+	mapping[mappingFieldMeta] = map[string]interface{}{metaFieldConfigs: es.configs[index]}
+	if err = es.applyMapping(ctx, index, mapping); nil != err {
+		return err
+	}
+
+	es.mappings[index] = string(mappingBytes)
+	return nil
+}
+
+// loadConfigs returns merged property configs kept in mapping of the index.
+func (es *ESClient) loadConfigs(ctx context.Context, index string) (map[string]constraint.Config, error) {
+	result, err := es.Client.GetMapping().Index(index).Do(ctx)
+	if elastic.IsNotFound(err) {
+		return map[string]constraint.Config{}, nil
+	} else if nil != err {
+		return nil, errors.Wrap(err, "get index mapping")
+	}
+
+	for _, item := range result {
+		indexMapping, _ := item.(map[string]interface{})
+		mappings, _ := indexMapping["mappings"].(map[string]interface{})
+		meta, _ := mappings[mappingFieldMeta].(map[string]interface{})
+		if raw, ok := meta[metaFieldConfigs]; ok {
+			return decodeConfigs(raw)
+		}
+	}
+	return map[string]constraint.Config{}, nil
+}
+
+func (es *ESClient) applyMapping(ctx context.Context, index string, mapping map[string]interface{}) error {
+	exists, err := es.Client.IndexExists(index).Do(ctx)
+	if err != nil {
+		return errors.Wrap(err, "check index exists")
+	} else if !exists {
+		return errors.Wrap(es.createIndex(ctx, index, index+"_0", mapping), "create index")
+	}
+
+	var result map[string]interface{}
+	if result, err = es.Client.GetMapping().Index(index).Do(ctx); err != nil {
+		return errors.Wrap(err, "get index mapping")
+	}
+
+	for indexName, item := range result {
+		indexMapping, _ := item.(map[string]interface{})
+		current, _ := indexMapping["mappings"].(map[string]interface{})
+		if mappingConflicted(current, mapping) {
+			return errors.Wrap(es.reindex(ctx, index, indexName, mapping), "reindex")
+		}
+	}
+
+	_, err = es.Client.PutMapping().Index(index).BodyJson(mapping).Do(ctx)
+	return errors.Wrap(err, "put index mapping")
+}
+
+// ensureIndex create index with basic mapping if not exists.
+func (es *ESClient) ensureIndex(ctx context.Context, index string) error {
+	if _, ok := es.indices.Load(index); ok {
+		return nil
+	}
+
+	exists, err := es.Client.IndexExists(index).Do(ctx)
+	if err != nil {
+		return errors.Wrap(err, "check index exists")
+	} else if !exists {
+		if err = es.createIndex(ctx, index, index+"_0", buildMapping(nil)); err != nil {
+			return errors.Wrap(err, "create index")
+		}
+	}
+
+	es.indices.Store(index, struct{}{})
+	return nil
+}
+
+func (es *ESClient) createIndex(ctx context.Context, alias, index string, mapping map[string]interface{}) error {
+	return es.createIndexWithAliases(ctx, index, mapping, map[string]interface{}{
+		alias:       map[string]interface{}{"is_write_index": true},
+		EntityIndex: map[string]interface{}{},
+	})
+}
+
+func (es *ESClient) createIndexWithAliases(ctx context.Context, index string, mapping, aliases map[string]interface{}) error {
+	body := map[string]interface{}{"mappings": mapping, "aliases": aliases}
+	if _, err := es.Client.CreateIndex(index).BodyJson(body).Do(ctx); err != nil {
+		// index created by other core node.
+		if elastic.IsStatusCode(err, http.StatusBadRequest) && strings.Contains(err.Error(), "resource_already_exists_exception") {
+			return nil
+		}
+		return errors.Wrap(err, "create index")
+	}
+
+	log.Info("create search index", zap.String("index", index), zap.Any("aliases", aliases))
+	return nil
+}
+
+// reindex create index with the new mapping and copy entities from old index
+// in background, the index is written and read through the old index until copied.
+func (es *ESClient) reindex(ctx context.Context, alias, oldIndex string, mapping map[string]interface{}) error {
+	newIndex := alias + "_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if _, err := es.Client.CreateIndex(newIndex).BodyJson(map[string]interface{}{"mappings": mapping}).Do(ctx); err != nil {
+		return errors.Wrap(err, "create index")
+	}
+
+	log.Info("reindex search index", zap.String("alias", alias), zap.String("from", oldIndex), zap.String("to", newIndex))
+	es.reindexing[alias] = true
+	go es.runReindex(alias, oldIndex, newIndex)
+	return nil
+}
+
+// runReindex copies entities into new index, then switches aliases of both indices
+// in one action, entities written during copying are copied again if missing, and
+// entities updated during copying are repaired by the reconciler.
+func (es *ESClient) runReindex(alias, oldIndex, newIndex string) {
+	ctx := context.Background()
+	var switched bool
+	defer func() {
+		es.lock.Lock()
+		defer es.lock.Unlock()
+		delete(es.reindexing, alias)
+		if !switched {
+			// reindexed again by next updating.
+			return
+		}
+
+		// apply configs merged during reindex.
+		if err := es.updateMapping(ctx, alias); nil != err {
+			log.Error("reindex search index, update mapping", zap.String("alias", alias), zap.Error(err))
+		}
+	}()
+
+	resp, err := es.copyIndex(ctx, oldIndex, newIndex)
+	if err != nil {
+		log.Error("reindex search index", zap.String("from", oldIndex), zap.String("to", newIndex), zap.Error(err))
+		if _, err = es.Client.DeleteIndex(newIndex).Do(ctx); err != nil {
+			log.Warn("reindex search index, delete new index", zap.String("index", newIndex), zap.Error(err))
+		}
+		return
+	}
+
+	if _, err = es.Client.Alias().Action(
+		elastic.NewAliasRemoveAction(alias).Index(oldIndex),
+		elastic.NewAliasRemoveAction(EntityIndex).Index(oldIndex),
+		elastic.NewAliasAddAction(alias).Index(newIndex).IsWriteIndex(true),
+		elastic.NewAliasAddAction(EntityIndex).Index(newIndex),
+	).Do(ctx); err != nil {
+		log.Error("reindex search index, switch alias", zap.String("from", oldIndex), zap.String("to", newIndex), zap.Error(err))
+		return
+	}
+
+	switched = true
+	// entities created in old index during copying.
+	if _, err = es.copyIndex(ctx, oldIndex, newIndex); err != nil {
+		log.Error("reindex search index, copy again", zap.String("from", oldIndex), zap.String("to", newIndex), zap.Error(err))
+		return
+	} else if _, err = es.Client.DeleteIndex(oldIndex).Do(ctx); err != nil {
+		log.Warn("reindex search index, delete old index", zap.String("index", oldIndex), zap.Error(err))
+	}
+
+	log.Info("reindex search index completed",
+		zap.String("from", oldIndex),
+		zap.String("to", newIndex),
+		zap.Int64("created", resp.Created),
+		zap.Int64("conflicts", resp.VersionConflicts))
+}
+
+// copyIndex copies entities missing in new index, entities in new index are newer.
+func (es *ESClient) copyIndex(ctx context.Context, oldIndex, newIndex string) (*elastic.BulkIndexByScrollResponse, error) {
+	resp, err := es.Client.Reindex().
+		Source(elastic.NewReindexSource().Index(oldIndex)).
+		Destination(elastic.NewReindexDestination().Index(newIndex).OpType("create")).
+		Conflicts("proceed").
+		WaitForCompletion(true).
+		Refresh("true").
+		Do(ctx)
+	return resp, errors.Wrap(err, "copy index")
+}
+
 func (es *ESClient) DeleteByQuery(ctx context.Context, query map[string]interface{}) error {
 	var bytes bytes.Buffer
 	if err := json.NewEncoder(&bytes).Encode(query); err != nil {
@@ -92,15 +453,26 @@ func (es *ESClient) Search(ctx context.Context, req SearchRequest) (SearchRespon
 	searchQuery := es.Client.Search().Index(EntityIndex)
 
 	if req.Condition != nil {
-		condition2boolQuery(req.Condition, boolQuery)
+		if err := condition2boolQuery(req.Condition, boolQuery); err != nil {
+			return resp, errors.Wrap(err, "parse search condition")
+		}
 	}
 	if req.Query != "" {
 		boolQuery = boolQuery.Must(elastic.NewMultiMatchQuery(req.Query))
 	}
 
 	req.Page = defaultPage(req.Page)
-	// searchQuery = searchQuery.Sort(req.Page.Sort, req.Page.Reverse).
+	searchQuery = searchQuery.IgnoreUnavailable(true).AllowNoIndices(true)
 	searchQuery = searchQuery.Query(boolQuery).From(int(req.Page.Offset)).Size(int(req.Page.Limit))
+	searchQuery = searchQuery.SortBy(buildSorters(req.Page, req.Sort)...)
+
+	for _, agg := range req.Aggregations {
+		aggregation, err := buildAggregation(agg)
+		if err != nil {
+			return resp, errors.Wrap(err, "parse search aggregation")
+		}
+		searchQuery = searchQuery.Aggregation(agg.Name, aggregation)
+	}
 
 	searchResult, err := searchQuery.Pretty(true).Do(ctx)
 	if err != nil {
@@ -114,6 +486,17 @@ func (es *ESClient) Search(ctx context.Context, req SearchRequest) (SearchRespon
 		}
 	}
 
+	if len(searchResult.Aggregations) > 0 {
+		resp.Aggregations = make(map[string]interface{})
+		for name, raw := range searchResult.Aggregations {
+			var result interface{}
+			if err = json.Unmarshal(raw, &result); err != nil {
+				return resp, errors.Wrap(err, "decode aggregation result")
+			}
+			resp.Aggregations[name] = result
+		}
+	}
+
 	resp.Total = searchResult.TotalHits()
 	resp.Data = data
 	resp.Raw, _ = json.Marshal(data)
@@ -127,7 +510,7 @@ func (es *ESClient) Search(ctx context.Context, req SearchRequest) (SearchRespon
 
 // reference: https://www.tutorialspoint.com/elasticsearch/elasticsearch_query_dsl.htm#:~:text=In%20Elasticsearch%2C%20searching%20is%20carried%20out%20by%20using,look%20for%20a%20specific%20value%20in%20specific%20field.
 // convert condition.
-func condition2boolQuery(conditions []*pb.SearchCondition, boolQuery *elastic.BoolQuery) {
+func condition2boolQuery(conditions []*pb.SearchCondition, boolQuery *elastic.BoolQuery) error {
 	for _, condition := range conditions {
 		switch condition.Operator {
 		case "$lt":
@@ -138,18 +521,161 @@ func condition2boolQuery(conditions []*pb.SearchCondition, boolQuery *elastic.Bo
 			boolQuery = boolQuery.Must(elastic.NewRangeQuery(condition.Field).Gt(condition.Value.AsInterface()))
 		case "$gte":
 			boolQuery = boolQuery.Must(elastic.NewRangeQuery(condition.Field).Gte(condition.Value.AsInterface()))
-		case "$neq":
-			boolQuery = boolQuery.MustNot(elastic.NewTermQuery(condition.Field, condition.Value.AsInterface()))
+		case "$ne", "$neq":
+			value := condition.Value.AsInterface()
+			boolQuery = boolQuery.MustNot(elastic.NewTermQuery(termField(condition.Field, value), value))
 		case "$eq":
-			boolQuery = boolQuery.Must(elastic.NewTermQuery(condition.Field+".keyword", condition.Value.AsInterface()))
+			value := condition.Value.AsInterface()
+			boolQuery = boolQuery.Must(elastic.NewTermQuery(termField(condition.Field, value), value))
+		case "$in":
+			values := condition.Value.GetListValue().AsSlice()
+			if len(values) == 0 {
+				return errors.Wrap(ErrConditionInvalid, "$in requires a list value")
+			}
+			boolQuery = boolQuery.Must(elastic.NewTermsQuery(termField(condition.Field, values[0]), values...))
 		case "$prefix":
 			boolQuery = boolQuery.Must(elastic.NewPrefixQuery(condition.Field+".keyword", condition.Value.GetStringValue()))
 		case "$wildcard":
 			boolQuery = boolQuery.Must(elastic.NewWildcardQuery(condition.Field+".keyword", "*"+condition.Value.GetStringValue()+"*"))
+		case "$exists":
+			if exists, ok := condition.Value.AsInterface().(bool); ok && !exists {
+				boolQuery = boolQuery.MustNot(elastic.NewExistsQuery(condition.Field))
+			} else {
+				boolQuery = boolQuery.Must(elastic.NewExistsQuery(condition.Field))
+			}
+		case "$geo_distance":
+			geoQuery, err := geoDistanceQuery(condition)
+			if err != nil {
+				return err
+			}
+			boolQuery = boolQuery.Must(geoQuery)
+		case "$and":
+			subQuery := elastic.NewBoolQuery()
+			if err := condition2boolQuery(condition.Conditions, subQuery); err != nil {
+				return err
+			}
+			boolQuery = boolQuery.Must(subQuery)
+		case "$or":
+			orQuery := elastic.NewBoolQuery().MinimumNumberShouldMatch(1)
+			for _, cond := range condition.Conditions {
+				subQuery := elastic.NewBoolQuery()
+				if err := condition2boolQuery([]*pb.SearchCondition{cond}, subQuery); err != nil {
+					return err
+				}
+				orQuery = orQuery.Should(subQuery)
+			}
+			boolQuery = boolQuery.Must(orQuery)
 		default:
 			boolQuery = boolQuery.Must(elastic.NewMatchQuery(condition.Field, condition.Value.AsInterface()))
 		}
 	}
+	return nil
+}
+
+// geoDistanceQuery parse condition value like {"lat": 30.1, "lon": 120.2, "distance": "10km"}.
+func geoDistanceQuery(condition *pb.SearchCondition) (elastic.Query, error) {
+	fields := condition.Value.GetStructValue().GetFields()
+	lat, hasLat := fields["lat"]
+	lon, hasLon := fields["lon"]
+	distance, hasDistance := fields["distance"]
+	if !hasLat || !hasLon || !hasDistance {
+		return nil, errors.Wrap(ErrConditionInvalid, "$geo_distance requires lat, lon and distance")
+	}
+
+	return elastic.NewGeoDistanceQuery(condition.Field).
+		Lat(lat.GetNumberValue()).
+		Lon(lon.GetNumberValue()).
+		Distance(distance.GetStringValue()), nil
+}
+
+// termField returns field for exact matching, string values matched with keyword.
+func termField(field string, value interface{}) string {
+	if _, ok := value.(string); ok && !strings.HasSuffix(field, ".keyword") && !keywordMapped(field) {
+		return field + ".keyword"
+	}
+	return field
+}
+
+// sortField returns field for sorting, basic fields of entity are text fields.
+func sortField(field string) string {
+	switch field {
+	case sortFieldID, "type", "owner", "source":
+		return field + ".keyword"
+	default:
+		return field
+	}
+}
+
+func buildSorters(page *pb.Pager, sorts []*pb.SearchSort) []elastic.Sorter {
+	var sorters []elastic.Sorter
+	for _, sort := range sorts {
+		if sort.Field == "" {
+			continue
+		}
+		sorters = append(sorters, elastic.NewFieldSort(sortField(sort.Field)).Order(!sort.IsDescending).UnmappedType("keyword"))
+	}
+
+	if len(sorters) == 0 && page.Sort != "" {
+		sorters = append(sorters, elastic.NewFieldSort(sortField(page.Sort)).Order(!page.Reverse).UnmappedType("keyword"))
+	}
+	// ties broken by id, pages are stable across searches.
+	return append(sorters, elastic.NewFieldSort(sortField(sortFieldID)).Order(true))
+}
+
+func buildAggregation(agg *pb.SearchAggregation) (elastic.Aggregation, error) {
+	if agg.Name == "" || agg.Field == "" {
+		return nil, errors.Wrap(ErrAggregationTypeInvalid, "aggregation name and field required")
+	}
+
+	var aggregation elastic.Aggregation
+	switch agg.Type {
+	case "terms":
+		terms := elastic.NewTermsAggregation().Field(sortField(agg.Field))
+		if agg.Size > 0 {
+			terms = terms.Size(int(agg.Size))
+		}
+		for _, sub := range agg.Aggregations {
+			subAgg, err := buildAggregation(sub)
+			if err != nil {
+				return nil, err
+			}
+			terms = terms.SubAggregation(sub.Name, subAgg)
+		}
+		return terms, nil
+	case "date_histogram":
+		interval := agg.Interval
+		if interval == "" {
+			interval = "1h"
+		}
+		histogram := elastic.NewDateHistogramAggregation().Field(agg.Field).FixedInterval(interval)
+		for _, sub := range agg.Aggregations {
+			subAgg, err := buildAggregation(sub)
+			if err != nil {
+				return nil, err
+			}
+			histogram = histogram.SubAggregation(sub.Name, subAgg)
+		}
+		return histogram, nil
+	case "stats":
+		aggregation = elastic.NewStatsAggregation().Field(agg.Field)
+	case "avg":
+		aggregation = elastic.NewAvgAggregation().Field(agg.Field)
+	case "min":
+		aggregation = elastic.NewMinAggregation().Field(agg.Field)
+	case "max":
+		aggregation = elastic.NewMaxAggregation().Field(agg.Field)
+	case "sum":
+		aggregation = elastic.NewSumAggregation().Field(agg.Field)
+	case "cardinality":
+		aggregation = elastic.NewCardinalityAggregation().Field(sortField(agg.Field))
+	default:
+		return nil, errors.Wrap(ErrAggregationTypeInvalid, agg.Type)
+	}
+
+	if len(agg.Aggregations) > 0 {
+		return nil, errors.Wrap(ErrAggregationTypeInvalid, "sub aggregations only supported by bucket aggregation")
+	}
+	return aggregation, nil
 }
 
 func defaultPage(page *pb.Pager) *pb.Pager {
@@ -161,7 +687,7 @@ func defaultPage(page *pb.Pager) *pb.Pager {
 		page.Limit = 10
 	}
 	if page.Sort == "" {
-		page.Sort = "id"
+		page.Sort = sortFieldID
 	}
 	return page
 }
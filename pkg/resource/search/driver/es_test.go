@@ -17,14 +17,87 @@ limitations under the License.
 package driver
 
 import (
+	"encoding/json"
+	"path"
 	"testing"
 
+	"github.com/olivere/elastic/v7"
 	"github.com/stretchr/testify/assert"
 	pb "github.com/tkeel-io/core/api/core/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
+func querySource(t *testing.T, query elastic.Query) string {
+	src, err := query.Source()
+	assert.Nil(t, err)
+	bytes, err := json.Marshal(src)
+	assert.Nil(t, err)
+	return string(bytes)
+}
+
 func Test_condition2boolQuery(t *testing.T) {
+	boolQuery := elastic.NewBoolQuery()
+	err := condition2boolQuery([]*pb.SearchCondition{
+		{Field: "type", Operator: "$eq", Value: structpb.NewStringValue("device")},
+		{Field: "status", Operator: "$ne", Value: structpb.NewStringValue("offline")},
+		{Field: "owner", Operator: "$in", Value: structpb.NewListValue(&structpb.ListValue{
+			Values: []*structpb.Value{structpb.NewStringValue("admin"), structpb.NewStringValue("tomas")}})},
+		{Field: "temp", Operator: "$exists", Value: structpb.NewBoolValue(false)},
+		{Field: "related", Operator: "$eq", Value: structpb.NewStringValue("device1")},
+		{Operator: "$or", Conditions: []*pb.SearchCondition{
+			{Field: "temp", Operator: "$gt", Value: structpb.NewNumberValue(20)},
+			{Field: "name", Operator: "$prefix", Value: structpb.NewStringValue("dev")},
+		}},
+	}, boolQuery)
+	assert.Nil(t, err)
+
+	src := querySource(t, boolQuery)
+	assert.Contains(t, src, `{"term":{"type.keyword":"device"}}`)
+	assert.Contains(t, src, `{"term":{"status.keyword":"offline"}}`)
+	assert.Contains(t, src, `{"terms":{"owner.keyword":["admin","tomas"]}}`)
+	assert.Contains(t, src, `{"exists":{"field":"temp"}}`)
+	assert.Contains(t, src, `{"term":{"related":"device1"}}`)
+	assert.Contains(t, src, `"minimum_should_match":"1"`)
+	assert.Contains(t, src, `{"prefix":{"name.keyword":"dev"}}`)
+
+	geoValue, _ := structpb.NewValue(map[string]interface{}{"lat": 30.1, "lon": 120.2, "distance": "10km"})
+	geoQuery := elastic.NewBoolQuery()
+	err = condition2boolQuery([]*pb.SearchCondition{{Field: "location", Operator: "$geo_distance", Value: geoValue}}, geoQuery)
+	assert.Nil(t, err)
+	assert.Contains(t, querySource(t, geoQuery), `"geo_distance":{"distance":"10km","location":{"lat":30.1,"lon":120.2}}`)
+
+	err = condition2boolQuery([]*pb.SearchCondition{{Field: "location", Operator: "$geo_distance", Value: structpb.NewStringValue("")}}, elastic.NewBoolQuery())
+	assert.ErrorIs(t, err, ErrConditionInvalid)
+}
+
+func Test_buildAggregation(t *testing.T) {
+	agg, err := buildAggregation(&pb.SearchAggregation{
+		Name: "by_type", Type: "terms", Field: "type", Size: 5,
+		Aggregations: []*pb.SearchAggregation{{Name: "temp", Type: "stats", Field: "temp"}},
+	})
+	assert.Nil(t, err)
+	src, err := agg.Source()
+	assert.Nil(t, err)
+	bytes, _ := json.Marshal(src)
+	assert.Equal(t, `{"aggregations":{"temp":{"stats":{"field":"temp"}}},"terms":{"field":"type.keyword","size":5}}`, string(bytes))
+
+	_, err = buildAggregation(&pb.SearchAggregation{Name: "x", Type: "unknown", Field: "temp"})
+	assert.ErrorIs(t, err, ErrAggregationTypeInvalid)
+}
 
+func Test_buildSorters(t *testing.T) {
+	sorters := buildSorters(&pb.Pager{Sort: "temp"}, nil)
+	assert.Len(t, sorters, 2)
+	sorters = buildSorters(&pb.Pager{Sort: "id"}, []*pb.SearchSort{{Field: "temp", IsDescending: true}, {Field: "owner"}})
+	assert.Len(t, sorters, 3)
+	src, _ := sorters[0].Source()
+	bytes, _ := json.Marshal(src)
+	assert.Equal(t, `{"temp":{"order":"desc","unmapped_type":"keyword"}}`, string(bytes))
+
+	// ties broken by id.
+	src, _ = sorters[2].Source()
+	bytes, _ = json.Marshal(src)
+	assert.Equal(t, `{"id.keyword":{"order":"asc"}}`, string(bytes))
 }
 
 func Test_defaultPage(t *testing.T) {
@@ -33,3 +106,45 @@ func Test_defaultPage(t *testing.T) {
 	defaultPage(page)
 	assert.Equal(t, int64(10), page.Limit)
 }
+
+func Test_legacyTypeQuery(t *testing.T) {
+	assert.Equal(t, `{"term":{"type.keyword":"DEVICE"}}`, querySource(t, legacyTypeQuery("DEVICE")))
+	assert.Equal(t, `{"bool":{"minimum_should_match":"1","should":[{"term":{"type.keyword":""}},{"bool":{"must_not":{"exists":{"field":"type"}}}}]}}`,
+		querySource(t, legacyTypeQuery("")))
+}
+
+// mappedType returns type of the field in mapping, fields not mapped are matched by dynamic templates.
+func mappedType(mapping map[string]interface{}, field string) string {
+	properties, _ := mapping["properties"].(map[string]interface{})
+	if fieldMapping, ok := properties[field].(map[string]interface{}); ok {
+		return mappingType(fieldMapping)
+	}
+
+	templates, _ := mapping["dynamic_templates"].([]interface{})
+	for _, item := range templates {
+		template, _ := item.(map[string]interface{})
+		for _, define := range template {
+			define, _ := define.(map[string]interface{})
+			pattern, _ := define["path_match"].(string)
+			if matched, _ := path.Match(pattern, field); matched {
+				fieldMapping, _ := define["mapping"].(map[string]interface{})
+				return mappingType(fieldMapping)
+			}
+		}
+	}
+	return ""
+}
+
+func Test_referenceFields(t *testing.T) {
+	// fields searched by related references of TQL, matched exactly.
+	mapping := buildMapping(nil)
+	for _, field := range []string{"related", "relationships.contains"} {
+		assert.Equal(t, "keyword", mappedType(mapping, field), field)
+
+		query := elastic.NewBoolQuery()
+		err := condition2boolQuery([]*pb.SearchCondition{{Field: field, Operator: "$eq", Value: structpb.NewStringValue("room1")}}, query)
+		assert.Nil(t, err)
+		assert.Contains(t, querySource(t, query), `{"term":{"`+field+`":"room1"}}`)
+	}
+	assert.Empty(t, mappedType(mapping, "contains"))
+}
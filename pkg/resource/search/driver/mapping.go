@@ -0,0 +1,237 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+const (
+	mappingFieldType       = "type"
+	mappingFieldProperties = "properties"
+	mappingTypeObject      = "object"
+	mappingTypeKeyword     = "keyword"
+	mappingFieldMeta       = "_meta"
+	metaFieldConfigs       = "configs"
+
+	// search fields of entity relationships, see statem.SearchFieldRelated.
+	fieldRelated       = "related"
+	fieldRelationships = "relationships"
+)
+
+// typeIndex returns index alias of the entity type.
+func typeIndex(entityType string) string {
+	if entityType == "" {
+		entityType = "default"
+	}
+
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '_'
+		}
+	}, entityType)
+	return EntityIndex + "_" + name
+}
+
+func textMapping() map[string]interface{} {
+	return map[string]interface{}{
+		mappingFieldType: "text",
+		"fields": map[string]interface{}{
+			"keyword": map[string]interface{}{
+				mappingFieldType: mappingTypeKeyword,
+				"ignore_above":   256,
+			},
+		},
+	}
+}
+
+// buildMapping generate index mapping from entity property configs,
+// only properties which enabled search are indexed.
+func buildMapping(configs map[string]constraint.Config) map[string]interface{} {
+	properties := map[string]interface{}{
+		"id":        textMapping(),
+		"type":      textMapping(),
+		"owner":     textMapping(),
+		"source":    textMapping(),
+		"version":   map[string]interface{}{mappingFieldType: "long"},
+		"last_time": map[string]interface{}{mappingFieldType: "long"},
+		// target ids of relationships, matched exactly.
+		fieldRelated: map[string]interface{}{mappingFieldType: mappingTypeKeyword},
+		// relationships.{type}, fields added by the dynamic template.
+		fieldRelationships: map[string]interface{}{
+			mappingFieldType: mappingTypeObject,
+			"dynamic":        true,
+		},
+	}
+
+	for key, cfg := range configs {
+		if mapping := configMapping(cfg); nil != mapping {
+			properties[key] = mapping
+		}
+	}
+
+	return map[string]interface{}{
+		"dynamic": false,
+		"dynamic_templates": []interface{}{
+			map[string]interface{}{
+				fieldRelationships: map[string]interface{}{
+					"path_match": fieldRelationships + ".*",
+					"mapping":    map[string]interface{}{mappingFieldType: mappingTypeKeyword},
+				},
+			},
+		},
+		mappingFieldProperties: properties,
+	}
+}
+
+// keywordMapped check whether the field is mapped as keyword, which has no keyword sub-field.
+func keywordMapped(field string) bool {
+	return field == fieldRelated || strings.HasPrefix(field, fieldRelationships+".")
+}
+
+// mergeConfigs returns property configs of entity type merged with configs of
+// an entity, configs of the entity override, except properties whose type
+// conflicts with configs of the type, which are returned as conflicts.
+func mergeConfigs(current, configs map[string]constraint.Config) (map[string]constraint.Config, []string) {
+	var conflicts []string
+	merged := make(map[string]constraint.Config, len(current)+len(configs))
+	for key, cfg := range current {
+		merged[key] = cfg
+	}
+	for key, cfg := range configs {
+		if prev, ok := merged[key]; ok && prev.Type != cfg.Type {
+			conflicts = append(conflicts, key)
+			continue
+		}
+		merged[key] = cfg
+	}
+
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
+
+// decodeConfigs decodes property configs kept in index mapping.
+func decodeConfigs(raw interface{}) (map[string]constraint.Config, error) {
+	bytes, err := json.Marshal(raw)
+	if nil != err {
+		return nil, errors.Wrap(err, "encode property configs")
+	}
+
+	configs := make(map[string]constraint.Config)
+	return configs, errors.Wrap(json.Unmarshal(bytes, &configs), "decode property configs")
+}
+
+func configMapping(cfg constraint.Config) map[string]interface{} {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	switch cfg.Type {
+	case constraint.PropertyTypeStruct:
+		define := constraint.DefineStruct{}
+		if fields, ok := cfg.Define[constraint.DefineFieldStructFields].(map[string]constraint.Config); ok {
+			define.Fields = fields
+		} else if err := mapstructure.Decode(cfg.Define, &define); nil != err {
+			return nil
+		}
+
+		properties := make(map[string]interface{})
+		for key, field := range define.Fields {
+			if mapping := configMapping(field); nil != mapping {
+				properties[key] = mapping
+			}
+		}
+
+		if len(properties) == 0 {
+			return nil
+		}
+		return map[string]interface{}{
+			mappingFieldType:       mappingTypeObject,
+			mappingFieldProperties: properties,
+		}
+	case constraint.PropertyTypeArray:
+		define := constraint.DefineArray{}
+		if elemType, ok := cfg.Define[constraint.DefineFieldArrayElemCfg].(constraint.Config); ok {
+			define.ElemType = elemType
+		} else if err := mapstructure.Decode(cfg.Define, &define); nil != err {
+			return nil
+		}
+
+		// elasticsearch array is a multi-value field of element type.
+		define.ElemType.Enabled = true
+		define.ElemType.EnabledSearch = define.ElemType.EnabledSearch || cfg.EnabledSearch
+		return configMapping(define.ElemType)
+	}
+
+	if !cfg.EnabledSearch {
+		return nil
+	}
+
+	switch cfg.Type {
+	case constraint.PropertyTypeInt:
+		return map[string]interface{}{mappingFieldType: "long"}
+	case constraint.PropertyTypeBool:
+		return map[string]interface{}{mappingFieldType: "boolean"}
+	case constraint.PropertyTypeFloat:
+		return map[string]interface{}{mappingFieldType: "float"}
+	case constraint.PropertyTypeDouble:
+		return map[string]interface{}{mappingFieldType: "double"}
+	case constraint.PropertyTypeString:
+		return textMapping()
+	default:
+		return nil
+	}
+}
+
+// mappingConflicted check whether desired mapping changes type of field in current mapping,
+// such changes can not be applied in place and require reindex.
+func mappingConflicted(current, desired map[string]interface{}) bool {
+	currentProps, _ := current[mappingFieldProperties].(map[string]interface{})
+	desiredProps, _ := desired[mappingFieldProperties].(map[string]interface{})
+	for key, desiredField := range desiredProps {
+		currentField, ok := currentProps[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		desiredMapping, _ := desiredField.(map[string]interface{})
+		if mappingType(currentField) != mappingType(desiredMapping) {
+			return true
+		} else if mappingConflicted(currentField, desiredMapping) {
+			return true
+		}
+	}
+	return false
+}
+
+func mappingType(mapping map[string]interface{}) string {
+	if typ, ok := mapping[mappingFieldType].(string); ok {
+		return typ
+	}
+	return mappingTypeObject
+}
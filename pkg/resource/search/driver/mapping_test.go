@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+func Test_typeIndex(t *testing.T) {
+	assert.Equal(t, "entity_device", typeIndex("DEVICE"))
+	assert.Equal(t, "entity_default", typeIndex(""))
+	assert.Equal(t, "entity_a_b_c", typeIndex("a/b*c"))
+}
+
+func Test_buildMapping(t *testing.T) {
+	configs := map[string]constraint.Config{
+		"temp": {ID: "temp", Type: "int", Enabled: true, EnabledSearch: true},
+		"name": {ID: "name", Type: "string", Enabled: true, EnabledSearch: false},
+		"metrics": {
+			ID:      "metrics",
+			Type:    "struct",
+			Enabled: true,
+			Define: map[string]interface{}{
+				"fields": map[string]interface{}{
+					"cpu": map[string]interface{}{"id": "cpu", "type": "float", "enabled": true, "enabled_search": true},
+					"mem": map[string]interface{}{"id": "mem", "type": "float", "enabled": true},
+				},
+			},
+		},
+		"tags": {
+			ID:            "tags",
+			Type:          "array",
+			Enabled:       true,
+			EnabledSearch: true,
+			Define: map[string]interface{}{
+				"length":    10,
+				"elem_type": constraint.Config{ID: "tag", Type: "string"},
+			},
+		},
+	}
+
+	mapping := buildMapping(configs)
+	assert.Equal(t, false, mapping["dynamic"])
+
+	properties, _ := mapping["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "long"}, properties["temp"])
+	assert.NotContains(t, properties, "name")
+	assert.Equal(t, textMapping(), properties["tags"])
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"cpu": map[string]interface{}{"type": "float"},
+		},
+	}, properties["metrics"])
+	assert.Contains(t, properties, "id")
+	assert.Contains(t, properties, "owner")
+
+	// relationships are indexed although dynamic mapping disabled.
+	assert.Equal(t, map[string]interface{}{"type": "keyword"}, properties["related"])
+	assert.Equal(t, map[string]interface{}{"type": "object", "dynamic": true}, properties["relationships"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"relationships": map[string]interface{}{
+			"path_match": "relationships.*",
+			"mapping":    map[string]interface{}{"type": "keyword"},
+		},
+	}}, mapping["dynamic_templates"])
+}
+
+func Test_mappingConflicted(t *testing.T) {
+	current := buildMapping(map[string]constraint.Config{
+		"temp": {ID: "temp", Type: "int", Enabled: true, EnabledSearch: true},
+	})
+
+	// add field.
+	desired := buildMapping(map[string]constraint.Config{
+		"temp": {ID: "temp", Type: "int", Enabled: true, EnabledSearch: true},
+		"name": {ID: "name", Type: "string", Enabled: true, EnabledSearch: true},
+	})
+	assert.False(t, mappingConflicted(current, desired))
+
+	// change field type.
+	desired = buildMapping(map[string]constraint.Config{
+		"temp": {ID: "temp", Type: "string", Enabled: true, EnabledSearch: true},
+	})
+	assert.True(t, mappingConflicted(current, desired))
+}
+
+func Test_mergeConfigs(t *testing.T) {
+	current := map[string]constraint.Config{
+		"temp": {ID: "temp", Type: "int", Enabled: true, EnabledSearch: true},
+		"name": {ID: "name", Type: "string", Enabled: true, EnabledSearch: true},
+	}
+
+	// configs of another entity of the type keep existing properties.
+	merged, conflicts := mergeConfigs(current, map[string]constraint.Config{
+		"name":  {ID: "name", Type: "string", Enabled: true, EnabledSearch: false},
+		"humid": {ID: "humid", Type: "float", Enabled: true, EnabledSearch: true},
+	})
+	assert.Empty(t, conflicts)
+	assert.Len(t, merged, 3)
+	assert.False(t, merged["name"].EnabledSearch)
+
+	// conflicting property keeps the type first mapped, mapping is unchanged.
+	again, conflicts := mergeConfigs(merged, map[string]constraint.Config{
+		"temp": {ID: "temp", Type: "float", Enabled: true, EnabledSearch: true},
+	})
+	assert.Equal(t, []string{"temp"}, conflicts)
+	assert.Equal(t, "int", again["temp"].Type)
+	assert.False(t, mappingConflicted(buildMapping(merged), buildMapping(again)))
+	assert.Equal(t, buildMapping(merged), buildMapping(again))
+}
+
+func Test_decodeConfigs(t *testing.T) {
+	configs := map[string]constraint.Config{
+		"temp": {ID: "temp", Type: "int", Enabled: true, EnabledSearch: true},
+		"metrics": {ID: "metrics", Type: "struct", Enabled: true, Define: map[string]interface{}{
+			"fields": map[string]constraint.Config{"cpu": {ID: "cpu", Type: "float", Enabled: true, EnabledSearch: true}},
+		}},
+	}
+
+	// configs kept in _meta of mapping, read back as json.
+	bytes, err := json.Marshal(map[string]interface{}{metaFieldConfigs: configs})
+	assert.Nil(t, err)
+	var meta map[string]interface{}
+	assert.Nil(t, json.Unmarshal(bytes, &meta))
+
+	decoded, err := decodeConfigs(meta[metaFieldConfigs])
+	assert.Nil(t, err)
+	assert.Equal(t, buildMapping(configs), buildMapping(decoded))
+}
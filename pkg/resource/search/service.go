@@ -2,9 +2,11 @@ package search
 
 import (
 	"context"
+	"sync"
 
 	pb "github.com/tkeel-io/core/api/core/v1"
 	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/constraint"
 	"github.com/tkeel-io/core/pkg/resource/search/driver"
 
 	"github.com/pkg/errors"
@@ -13,35 +15,116 @@ import (
 
 var GlobalService *Service
 
-func Init() *Service {
-	defaultRegistered := map[driver.Type]driver.SearchEngine{
-		// Add other drivers to SearchService here.
-		driver.ElasticsearchDriver: driver.NewElasticsearchEngine(config.Get().SearchEngine.ES),
+func Init() (*Service, error) {
+	registered, selectOpt, err := newEngines(config.Get().SearchEngine)
+	if nil != err {
+		return nil, err
 	}
-	return NewService(defaultRegistered).Use(driver.Parse(config.Get().SearchEngine.Use))
+	return NewService(registered).Use(selectOpt), nil
 }
 
-var _ pb.SearchHTTPServer = &Service{}
+func newEngines(cfg config.SearchEngine) (map[driver.Type]driver.SearchEngine, driver.SelectDriveOption, error) {
+	selectOpt := driver.Parse(cfg.Use)
+	registered := map[driver.Type]driver.SearchEngine{}
+	// Add other drivers to SearchService here.
+	switch selectOpt() {
+	case driver.ElasticsearchDriver:
+		engine, err := driver.NewElasticsearchEngine(cfg.ES)
+		if nil != err {
+			return nil, nil, errors.Wrap(err, "init search engine")
+		}
+		registered[driver.ElasticsearchDriver] = engine
+	case driver.EmbeddedDriver:
+		engine, err := driver.NewEmbeddedEngine(cfg.Embedded)
+		if nil != err {
+			return nil, nil, errors.Wrap(err, "init search engine")
+		}
+		registered[driver.EmbeddedDriver] = engine
+	}
+	return registered, selectOpt, nil
+}
+
+var _ Client = &Service{}
 
 type Service struct {
+	lock      *sync.RWMutex
 	drivers   map[driver.Type]driver.SearchEngine
 	selectOpt driver.SelectDriveOption
 }
 
 func NewService(registered map[driver.Type]driver.SearchEngine) *Service {
 	return &Service{
+		lock:      &sync.RWMutex{},
 		drivers:   registered,
 		selectOpt: driver.NoopDriver,
 	}
 }
 
+// Reload rebuilds search engines from configuration, used at runtime.
+// the engines in use are kept if the new ones fail to build, otherwise
+// they are closed after swapped out.
+func (s *Service) Reload(cfg config.SearchEngine) error {
+	registered, selectOpt, err := newEngines(cfg)
+	if nil != err {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	previous := s.drivers
+	s.drivers = registered
+	s.selectOpt = selectOpt
+	return closeEngines(previous)
+}
+
+// Close closes all registered search engines.
+func (s *Service) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := closeEngines(s.drivers)
+	s.drivers = map[driver.Type]driver.SearchEngine{}
+	return err
+}
+
+func closeEngines(engines map[driver.Type]driver.SearchEngine) error {
+	var err error
+	for typ, engine := range engines {
+		if e := engine.Close(); nil != e {
+			err = errors.Wrap(e, "close search engine "+string(typ))
+		}
+	}
+	return err
+}
+
+// engine returns search engine in use.
+func (s *Service) engine() (driver.SearchEngine, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	engine, ok := s.drivers[s.selectOpt()]
+	if !ok {
+		return nil, errors.New("no specified engine:" + string(s.selectOpt()))
+	}
+	return engine, nil
+}
+
+// Ping checks the search engine in use is reachable.
+func (s *Service) Ping(ctx context.Context) error {
+	engine, err := s.engine()
+	if nil != err {
+		return err
+	}
+	return errors.Wrap(engine.Ping(ctx), "ping search engine")
+}
+
 func (s *Service) Search(ctx context.Context, request *pb.SearchRequest) (*pb.SearchResponse, error) {
 	out := &pb.SearchResponse{}
 	req := driver.SearchRequest{
-		Source:    request.Source,
-		Owner:     request.Owner,
-		Query:     request.Query,
-		Condition: request.Condition,
+		Source:       request.Source,
+		Owner:        request.Owner,
+		Query:        request.Query,
+		Condition:    request.Condition,
+		Sort:         request.Sort,
+		Aggregations: request.Aggregations,
 	}
 	req.Page = &pb.Pager{}
 	req.Page.Limit = int64(request.PageSize)
@@ -52,9 +135,9 @@ func (s *Service) Search(ctx context.Context, request *pb.SearchRequest) (*pb.Se
 	// TODO: Multiple Driver Services One Response support.
 	// assumption len(s.selectOpt) == 1.
 
-	engine, ok := s.drivers[s.selectOpt()]
-	if !ok {
-		return out, errors.New("no specified engine:" + string(s.selectOpt()))
+	engine, err := s.engine()
+	if nil != err {
+		return out, err
 	}
 	resp, err := engine.Search(ctx, req)
 	if err != nil {
@@ -67,6 +150,11 @@ func (s *Service) Search(ctx context.Context, request *pb.SearchRequest) (*pb.Se
 		}
 		out.Items = append(out.Items, val)
 	}
+	if len(resp.Aggregations) > 0 {
+		if out.Aggregations, err = structpb.NewValue(resp.Aggregations); err != nil {
+			return nil, errors.Wrap(err, "new aggregations value error")
+		}
+	}
 	out.Total = resp.Total
 	out.PageNum = request.PageNum
 	out.PageSize = request.PageSize
@@ -76,9 +164,9 @@ func (s *Service) Search(ctx context.Context, request *pb.SearchRequest) (*pb.Se
 
 func (s *Service) DeleteByID(ctx context.Context, request *pb.DeleteByIDRequest) (*pb.DeleteByIDResponse, error) {
 	out := &pb.DeleteByIDResponse{}
-	engine, ok := s.drivers[s.selectOpt()]
-	if !ok {
-		return out, errors.New("no specified engine:" + string(s.selectOpt()))
+	engine, err := s.engine()
+	if nil != err {
+		return out, err
 	}
 	if err := engine.Delete(ctx, request.Id); err != nil {
 		return out, errors.Wrap(err, "build index error")
@@ -107,9 +195,9 @@ func (s *Service) Index(ctx context.Context, in *pb.IndexObject) (*pb.IndexRespo
 	if err != nil {
 		return out, errors.Wrap(err, "json marshal error")
 	}
-	engine, ok := s.drivers[s.selectOpt()]
-	if !ok {
-		return out, errors.New("no specified engine:" + string(s.selectOpt()))
+	engine, err := s.engine()
+	if nil != err {
+		return out, err
 	}
 	if err = engine.BuildIndex(ctx, id, string(objBytes)); err != nil {
 		return out, errors.Wrap(err, "build index error")
@@ -118,8 +206,19 @@ func (s *Service) Index(ctx context.Context, in *pb.IndexObject) (*pb.IndexRespo
 	return out, nil
 }
 
+// UpdateMapping update index mapping of the entity type from property configs.
+func (s *Service) UpdateMapping(ctx context.Context, entityType string, configs map[string]constraint.Config) error {
+	engine, err := s.engine()
+	if nil != err {
+		return err
+	}
+	return errors.Wrap(engine.UpdateMapping(ctx, entityType, configs), "update index mapping error")
+}
+
 // Use SelectDriveOption and set the option to this service.
 func (s *Service) Use(opt driver.SelectDriveOption) *Service {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	s.selectOpt = opt
 	return s
 }
@@ -132,6 +231,8 @@ func (s Service) With(opt driver.SelectDriveOption) *Service {
 }
 
 func (s *Service) Register(name driver.Type, implement driver.SearchEngine) *Service {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	if s.drivers == nil {
 		s.drivers = make(map[driver.Type]driver.SearchEngine)
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/constraint"
 	"github.com/tkeel-io/core/pkg/resource/search/driver"
 
 	"github.com/stretchr/testify/assert"
@@ -44,6 +46,37 @@ func TestService_Register(t *testing.T) {
 	assert.Equal(t, engine, d)
 }
 
+func TestService_Reload(t *testing.T) {
+	engine := &closingEngine{}
+	service := NewService(map[driver.Type]driver.SearchEngine{driver.EmbeddedDriver: engine}).Use(driver.Embedded)
+
+	// engines in use are kept if new engines failed to build.
+	err := service.Reload(config.SearchEngine{Use: "elasticsearch"})
+	assert.ErrorIs(t, err, driver.ErrEngineConfigInvalid)
+	current, err := service.engine()
+	assert.Nil(t, err)
+	assert.Equal(t, engine, current)
+	assert.False(t, engine.closed)
+
+	// closed after swapped out.
+	assert.Nil(t, service.Reload(config.SearchEngine{Use: "embedded"}))
+	current, err = service.engine()
+	assert.Nil(t, err)
+	assert.NotEqual(t, engine, current)
+	assert.True(t, engine.closed)
+	assert.Nil(t, service.Close())
+}
+
+type closingEngine struct {
+	fakeEngine
+	closed bool
+}
+
+func (f *closingEngine) Close() error {
+	f.closed = true
+	return nil
+}
+
 type fakeEngine struct{}
 
 func (f fakeEngine) BuildIndex(ctx context.Context, index, content string) error {
@@ -57,3 +90,15 @@ func (f fakeEngine) Search(ctx context.Context, request driver.SearchRequest) (d
 func (f fakeEngine) Delete(ctx context.Context, id string) error {
 	return nil
 }
+
+func (f fakeEngine) UpdateMapping(ctx context.Context, entityType string, configs map[string]constraint.Config) error {
+	return nil
+}
+
+func (f fakeEngine) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (f fakeEngine) Close() error {
+	return nil
+}
@@ -16,8 +16,22 @@ limitations under the License.
 
 package search
 
-import "errors"
+import (
+	"context"
+	"errors"
+
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
 
 var (
 	ErrIndexParamInvalid = errors.New("invalid index params")
 )
+
+// Client is the search client used by core runtime.
+type Client interface {
+	pb.SearchHTTPServer
+
+	// UpdateMapping update index mapping of the entity type from property configs.
+	UpdateMapping(ctx context.Context, entityType string, configs map[string]constraint.Config) error
+}
@@ -100,6 +100,17 @@ func (i *Influx) Write(ctx context.Context, req *tseries.TSeriesRequest) (*tseri
 	return &tseries.TSeriesResponse{Metadata: req.Metadata}, nil
 }
 
+// Ping checks influxdb server is ready, authentication is not validated.
+func (i *Influx) Ping(ctx context.Context) error {
+	ready, err := i.client.Ready(ctx)
+	if nil != err {
+		return errors.Wrap(err, "ping influxdb")
+	} else if !ready {
+		return ErrInfluxNotReady
+	}
+	return nil
+}
+
 func init() {
 	tseries.Register("influxdb", newInflux)
 }
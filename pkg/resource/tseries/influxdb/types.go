@@ -8,4 +8,5 @@ var (
 	ErrInfluxRequiredOrg    = errors.New("Influx Error: Org required")
 	ErrInfluxRequiredBucket = errors.New("Influx Error: Bucket required")
 	ErrInfluxInvalidParams  = errors.New("Influx Error: Cannot convert request data")
+	ErrInfluxNotReady       = errors.New("Influx Error: Server not ready")
 )
@@ -25,6 +25,10 @@ func (n *noop) Write(ctx context.Context, req *tseries.TSeriesRequest) (*tseries
 	return &tseries.TSeriesResponse{}, nil
 }
 
+func (n *noop) Ping(ctx context.Context) error {
+	return nil
+}
+
 func init() {
 	tseries.Register("noop", newNoop)
 }
@@ -29,6 +29,8 @@ type TSeriesResponse struct { //nolint
 type TimeSerier interface {
 	Init(resource.Metadata) error
 	Write(ctx context.Context, req *TSeriesRequest) (*TSeriesResponse, error)
+	// Ping checks the time-series database is reachable.
+	Ping(ctx context.Context) error
 }
 
 type TSGenerator func() TimeSerier
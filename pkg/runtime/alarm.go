@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/alarm"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/mapper"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/util"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+)
+
+// alarmMachine evaluates alarm rule on properties of source entities,
+// status changes are propagated to subscribers of the alarm entity.
+type alarmMachine struct {
+	statem.StateMachiner
+
+	rule  *alarm.Rule
+	state alarm.State
+	// values of source properties, key=entityId.propertyKey.
+	values map[string]constraint.Node
+}
+
+// newAlarm returns an alarm state machine.
+func newAlarm(ctx context.Context, mgr *Manager, in *statem.Base) (stateM statem.StateMachiner, err error) {
+	a := &alarmMachine{
+		state:  alarm.DecodeState(in.KValues),
+		values: make(map[string]constraint.Node),
+	}
+
+	errFunc := func(err error) error { return errors.Wrap(err, "create alarm failed") }
+	if a.rule, err = alarm.DecodeRule(in.KValues); nil != err {
+		return nil, errFunc(err)
+	} else if a.StateMachiner, err = statem.NewState(ctx, mgr, in, a.HandleMessage); nil != err {
+		return nil, errFunc(err)
+	}
+
+	// values of sources are not kept, duration is observed again after loaded.
+	a.state.PendingSince = 0
+
+	// set mapper.
+	a.GetBase().Mappers = []statem.MapperDesc{{
+		Name:      alarm.MapperName,
+		TQLString: a.rule.Condition.TQL(in.ID),
+	}}
+	return a, nil
+}
+
+// FlushDue raises alarm whose condition has held for duration, then flushes due sinks.
+func (a *alarmMachine) FlushDue(ctx context.Context, now time.Time) error {
+	if a.rule.Due(&a.state, now.UnixNano()/1e6) {
+		a.OnMessage(ctx, statem.PropertyMessage{StateID: a.GetID()})
+	}
+	return errors.Wrap(a.StateMachiner.FlushDue(ctx, now), "flush alarm")
+}
+
+// AcknowledgeAlarm acknowledges alarm in its state machine, returns properties of the alarm.
+func (m *Manager) AcknowledgeAlarm(ctx context.Context, id, user string) (map[string]constraint.Node, error) {
+	var properties map[string]constraint.Node
+	err := m.runInLoop(ctx, func() (err error) {
+		channelID, sm := m.getStateMachine("", id)
+		if nil == sm {
+			base := &statem.Base{ID: id, Type: StateMachineTypeAlarm}
+			if sm, err = m.loadOrCreate(ctx, channelID, false, base); nil != err {
+				return errors.Wrap(err, "load alarm")
+			}
+		}
+
+		a, ok := sm.(*alarmMachine)
+		if !ok {
+			return errors.Wrapf(ErrStateMachineNotAlarm, "entity %s", id)
+		}
+		properties, err = a.acknowledge(ctx, user)
+		return err
+	})
+	return properties, errors.Wrap(err, "acknowledge alarm")
+}
+
+// acknowledge checks status held by the alarm, then acknowledges it like a message of itself.
+func (a *alarmMachine) acknowledge(ctx context.Context, user string) (map[string]constraint.Node, error) {
+	state := a.state
+	if err := state.Acknowledge(user, util.UnixMilli()); nil != err {
+		return nil, errors.Wrap(err, "acknowledge alarm")
+	}
+
+	a.OnMessage(ctx, statem.PropertyMessage{
+		StateID:    a.GetID(),
+		Operator:   constraint.PatchOpReplace.String(),
+		Properties: map[string]constraint.Node{alarm.FieldAcknowledgedBy: constraint.StringNode(user)},
+	})
+	return a.state.Properties(), nil
+}
+
+// HandleMessage evaluates rule on source properties, alarm is acknowledged by
+// message of itself, other properties of alarm are not writable.
+func (a *alarmMachine) HandleMessage(message statem.Message) []WatchKey {
+	msg, ok := message.(statem.PropertyMessage)
+	if !ok {
+		log.Error("undefine message type.", logger.MessageInst(message))
+		return nil
+	}
+
+	var changed bool
+	base := a.GetBase()
+	now := util.UnixMilli()
+	if msg.StateID == "" || msg.StateID == base.ID {
+		if user, has := msg.Properties[alarm.FieldAcknowledgedBy]; has && nil != user {
+			if err := a.state.Acknowledge(user.String(), now); nil != err {
+				log.Warn("acknowledge alarm", logger.EntityID(base.ID), zap.Error(err))
+			} else {
+				changed = true
+			}
+		}
+	} else {
+		for key, val := range msg.Properties {
+			a.values[msg.StateID+mapper.WatchKeyDelimiter+key] = val
+		}
+	}
+
+	changed = a.rule.Evaluate(&a.state, a.values, now) || changed
+	properties := a.state.Properties()
+	for key, val := range properties {
+		base.KValues[key] = val
+	}
+
+	if !changed {
+		return nil
+	}
+
+	base.Version++
+	base.LastTime = now
+	log.Info("alarm status changed", logger.EntityID(base.ID),
+		logger.Status(a.state.Status), zap.Float64("value", a.state.Value))
+
+	watchKeys := make([]WatchKey, 0, len(properties))
+	for key := range properties {
+		watchKeys = append(watchKeys, WatchKey{EntityId: base.ID, PropertyKey: key})
+	}
+	return watchKeys
+}
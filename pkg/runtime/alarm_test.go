@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/alarm"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/statem"
+)
+
+func TestAlarm(t *testing.T) {
+	rule, err := alarm.NewRule("device1.temp >= device1.max", alarm.SeverityMajor, 0, "")
+	assert.Nil(t, err)
+
+	_, err = newAlarm(context.Background(), &Manager{}, &statem.Base{ID: "alarm1", Type: StateMachineTypeAlarm})
+	assert.ErrorIs(t, err, alarm.ErrConditionInvalid)
+
+	sm, err := newAlarm(context.Background(), &Manager{}, &statem.Base{ID: "alarm1", Type: StateMachineTypeAlarm, Owner: "admin", KValues: rule.Properties()})
+	assert.Nil(t, err)
+	assert.Equal(t, []statem.MapperDesc{{Name: alarm.MapperName, TQLString: "insert into alarm1 select device1.temp as value, device1.max as threshold"}}, sm.GetBase().Mappers)
+
+	a, _ := sm.(*alarmMachine)
+	source := func(props map[string]interface{}) []WatchKey {
+		msg := statem.PropertyMessage{StateID: "device1", Properties: map[string]constraint.Node{}}
+		for key, val := range props {
+			msg.Properties[key] = constraint.NewNode(val)
+		}
+		return a.HandleMessage(msg)
+	}
+
+	// raised when both values known.
+	assert.Empty(t, source(map[string]interface{}{"temp": 90}))
+	assert.NotEmpty(t, source(map[string]interface{}{"max": 80}))
+	assert.Equal(t, alarm.StatusActive, a.GetBase().KValues[alarm.FieldStatus].String())
+	assert.Equal(t, float64(90), a.GetBase().KValues[alarm.FieldValue].Value())
+
+	// properties of alarm are not writable, except acknowledging.
+	assert.Empty(t, a.HandleMessage(statem.PropertyMessage{StateID: "alarm1", Properties: map[string]constraint.Node{
+		alarm.FieldStatus: constraint.StringNode(alarm.StatusCleared),
+	}}))
+	assert.NotEmpty(t, a.HandleMessage(statem.PropertyMessage{StateID: "alarm1", Properties: map[string]constraint.Node{
+		alarm.FieldAcknowledgedBy: constraint.StringNode("operator"),
+	}}))
+	assert.Equal(t, alarm.StatusAcknowledged, a.GetBase().KValues[alarm.FieldStatus].String())
+	assert.Equal(t, "operator", a.GetBase().KValues[alarm.FieldAcknowledgedBy].String())
+
+	// cleared.
+	assert.NotEmpty(t, source(map[string]interface{}{"temp": 79.5}))
+	assert.Equal(t, alarm.StatusCleared, a.GetBase().KValues[alarm.FieldStatus].String())
+	assert.Equal(t, 79.5, a.GetBase().KValues[alarm.FieldValue].Value())
+}
+
+func TestManager_AcknowledgeAlarm(t *testing.T) {
+	mgr := &Manager{
+		containers:   map[string]*Container{"default": NewContainer()},
+		daprClient:   fake.NewState(),
+		searchClient: fake.NewSearch(t),
+	}
+	rule, err := alarm.NewRule("device1.temp > 80", alarm.SeverityMajor, 0, "")
+	assert.Nil(t, err)
+	sm, err := newAlarm(context.Background(), mgr, &statem.Base{ID: "alarm1", Type: StateMachineTypeAlarm, Owner: "admin", KValues: rule.Properties()})
+	assert.Nil(t, err)
+	mgr.containers["default"].Add(sm)
+	mgr.containers["default"].Add(&fakeStateMachine{id: "device1"})
+
+	_, err = mgr.AcknowledgeAlarm(context.Background(), "device1", "operator")
+	assert.ErrorIs(t, err, ErrStateMachineNotAlarm)
+	_, err = mgr.AcknowledgeAlarm(context.Background(), "alarm1", "operator")
+	assert.ErrorIs(t, err, alarm.ErrAlarmNotActive)
+
+	// raised in state machine, not flushed yet.
+	sm.(*alarmMachine).HandleMessage(statem.PropertyMessage{StateID: "device1", Properties: map[string]constraint.Node{"temp": constraint.IntNode(90)}})
+	properties, err := mgr.AcknowledgeAlarm(context.Background(), "alarm1", "operator")
+	assert.Nil(t, err)
+	assert.Equal(t, alarm.StatusAcknowledged, properties[alarm.FieldStatus].String())
+	assert.Equal(t, "operator", properties[alarm.FieldAcknowledgedBy].String())
+	assert.Equal(t, alarm.StatusAcknowledged, sm.GetBase().KValues[alarm.FieldStatus].String())
+}
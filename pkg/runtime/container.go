@@ -50,3 +50,21 @@ func (c *Container) Get(id string) statem.StateMachiner {
 	defer c.lock.RUnlock()
 	return c.states[id]
 }
+
+// States returns state machines in container.
+func (c *Container) States() []statem.StateMachiner {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	states := make([]statem.StateMachiner, 0, len(c.states))
+	for _, sm := range c.states {
+		states = append(states, sm)
+	}
+	return states
+}
+
+// Size returns number of state machines in container.
+func (c *Container) Size() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.states)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	_defaultDaprHTTPPort = "3500"
+	_etcdHealthKey       = "health"
+)
+
+// CheckReady fails while mappers are loading or the manager is shutting down.
+func (m *Manager) CheckReady(ctx context.Context) error {
+	if atomic.LoadInt32(&m.started) == 0 {
+		return errors.Wrap(ErrManagerNotReady, "loading mappers")
+	} else if !m.Accepting() {
+		return errors.Wrap(ErrManagerNotReady, "shutting down")
+	}
+	return nil
+}
+
+// CheckEtcd reads a key from etcd, which requires quorum.
+func (m *Manager) CheckEtcd(ctx context.Context) error {
+	_, err := m.etcdClient.Get(ctx, _etcdHealthKey)
+	return errors.Wrap(err, "check etcd")
+}
+
+// CheckDapr calls health endpoint of dapr sidecar, the sdk client has no health api.
+func (m *Manager) CheckDapr(ctx context.Context) error {
+	port := os.Getenv("DAPR_HTTP_PORT")
+	if port == "" {
+		port = _defaultDaprHTTPPort
+	}
+
+	url := fmt.Sprintf("http://localhost:%s/v1.0/healthz", port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if nil != err {
+		return errors.Wrap(err, "check dapr")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if nil != err {
+		return errors.Wrap(err, "check dapr")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return errors.Wrap(ErrDaprUnhealthy, resp.Status)
+	}
+	return nil
+}
+
+// CheckTimeSeries pings time-series database in use.
+func (m *Manager) CheckTimeSeries(ctx context.Context) error {
+	m.lock.RLock()
+	tseriesClient := m.tseriesClient
+	m.lock.RUnlock()
+	return errors.Wrap(tseriesClient.Ping(ctx), "check time-series")
+}
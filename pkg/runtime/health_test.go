@@ -0,0 +1,36 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_CheckReady(t *testing.T) {
+	mgr := &Manager{}
+	assert.ErrorIs(t, mgr.CheckReady(context.Background()), ErrManagerNotReady)
+
+	atomic.StoreInt32(&mgr.started, 1)
+	assert.Nil(t, mgr.CheckReady(context.Background()))
+
+	atomic.StoreInt32(&mgr.closing, 1)
+	assert.ErrorIs(t, mgr.CheckReady(context.Background()), ErrManagerNotReady)
+}
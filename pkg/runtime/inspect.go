@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/kit/log"
+)
+
+// ListActors returns runtime information of state machines in container, all containers if empty.
+func (m *Manager) ListActors(ctx context.Context, containerID string) ([]*pb.ActorInfo, error) {
+	var actors []*pb.ActorInfo
+	err := m.runInLoop(ctx, func() error {
+		for channelID, container := range m.listContainers() {
+			if containerID != "" && containerID != channelID {
+				continue
+			}
+			for _, sm := range container.States() {
+				actors = append(actors, actorInfo(channelID, sm))
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(actors, func(i, j int) bool {
+		return actors[i].Id < actors[j].Id
+	})
+	return actors, errors.Wrap(err, "list actors")
+}
+
+// GetActor returns runtime information of state machine.
+func (m *Manager) GetActor(ctx context.Context, id string) (*pb.ActorInfo, error) {
+	var actor *pb.ActorInfo
+	err := m.runInLoop(ctx, func() error {
+		channelID, sm := m.findStateMachine(id)
+		if nil == sm {
+			return ErrStateMachineNotFound
+		}
+		actor = actorInfo(channelID, sm)
+		return nil
+	})
+	return actor, errors.Wrap(err, "get actor")
+}
+
+// FlushActor flushes state machine to state, search and time-series.
+func (m *Manager) FlushActor(ctx context.Context, id string) (*pb.ActorInfo, error) {
+	var actor *pb.ActorInfo
+	err := m.runInLoop(ctx, func() error {
+		channelID, sm := m.findStateMachine(id)
+		if nil == sm {
+			return ErrStateMachineNotFound
+		} else if err := sm.Flush(ctx); nil != err {
+			return errors.Wrap(err, "flush state machine")
+		}
+		actor = actorInfo(channelID, sm)
+		return nil
+	})
+	return actor, errors.Wrap(err, "flush actor")
+}
+
+// FlushResident flushes state machine if resident, serialized with message disposing
+// of the state machine only, nothing to flush if not resident.
+func (m *Manager) FlushResident(ctx context.Context, id string) error {
+	err := m.runInLoop(ctx, func() error {
+		if _, sm := m.findStateMachine(id); nil != sm {
+			return errors.Wrap(sm.Flush(ctx), "flush state machine")
+		}
+		return nil
+	})
+	return errors.Wrap(err, "flush resident actor")
+}
+
+// DetachActor flushes and removes state machine from runtime, loaded again on next message.
+func (m *Manager) DetachActor(ctx context.Context, id string) error {
+	err := m.runInLoop(ctx, func() error {
+		channelID, sm := m.findStateMachine(id)
+		if nil == sm {
+			return ErrStateMachineNotFound
+		} else if err := sm.Flush(ctx); nil != err {
+			return errors.Wrap(err, "flush state machine")
+		}
+		m.getContainer(channelID).Remove(id)
+		log.Info("detach state machine", logger.EntityID(id))
+		return nil
+	})
+	return errors.Wrap(err, "detach actor")
+}
+
+// RemoveActor removes state machine from runtime without flushing, used after its state is purged.
+func (m *Manager) RemoveActor(ctx context.Context, id string) error {
+	err := m.runInLoop(ctx, func() error {
+		if channelID, sm := m.findStateMachine(id); nil != sm {
+			m.getContainer(channelID).Remove(id)
+			log.Info("remove state machine", logger.EntityID(id))
+		}
+		m.removeTimers(ctx, id)
+		return nil
+	})
+	return errors.Wrap(err, "remove actor")
+}
+
+// ReloadActor flushes state machine and loads it again from state store.
+func (m *Manager) ReloadActor(ctx context.Context, id string) (*pb.ActorInfo, error) {
+	var actor *pb.ActorInfo
+	err := m.runInLoop(ctx, func() error {
+		channelID, sm := m.findStateMachine(id)
+		if nil == sm {
+			return ErrStateMachineNotFound
+		} else if err := sm.Flush(ctx); nil != err {
+			return errors.Wrap(err, "flush state machine")
+		}
+
+		m.getContainer(channelID).Remove(id)
+		base := &statem.Base{ID: id, Type: sm.GetBase().Type}
+		reloaded, err := m.loadOrCreate(ctx, channelID, false, base)
+		if nil != err {
+			return errors.Wrap(err, "load state machine")
+		}
+
+		actor = actorInfo(channelID, reloaded)
+		log.Info("reload state machine", logger.EntityID(id))
+		return nil
+	})
+	return actor, errors.Wrap(err, "reload actor")
+}
+
+// runInLoop runs fn in dispatching loop, serialized with message disposing.
+func (m *Manager) runInLoop(ctx context.Context, fn func() error) error {
+	if atomic.LoadInt32(&m.started) == 0 {
+		return fn()
+	}
+
+	errCh := make(chan error, 1)
+	select {
+	case m.actionCh <- func() { errCh <- fn() }:
+	case <-m.stopped:
+		return ErrManagerShutdown
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "wait dispatching loop")
+	}
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "wait action result")
+	}
+}
+
+// findStateMachine returns resident state machine and its container id.
+func (m *Manager) findStateMachine(id string) (string, statem.StateMachiner) {
+	for channelID, container := range m.listContainers() {
+		if sm := container.Get(id); nil != sm {
+			return channelID, sm
+		}
+	}
+	return "", nil
+}
+
+func actorInfo(channelID string, sm statem.StateMachiner) *pb.ActorInfo {
+	base := sm.GetBase()
+	inspection := sm.Inspect()
+	actor := &pb.ActorInfo{
+		Id:            sm.GetID(),
+		Type:          base.Type,
+		Owner:         base.Owner,
+		Container:     channelID,
+		Status:        string(inspection.Status),
+		RuntimeStatus: inspection.RuntimeStatus,
+		Tentacles:     inspection.Tentacles,
+		LastFlushTime: inspection.LastFlushTime,
+	}
+	for _, m := range inspection.Mappers {
+		actor.Mappers = append(actor.Mappers, &pb.MapperDesc{Name: m.Name, Tql: m.TQLString})
+	}
+	return actor
+}
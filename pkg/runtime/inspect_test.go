@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/statem"
+)
+
+func TestManager_Actors(t *testing.T) {
+	mgr := &Manager{
+		containers: map[string]*Container{"default": NewContainer(), "channel": NewContainer()},
+		actionCh:   make(chan func()),
+		stopped:    make(chan struct{}),
+	}
+
+	mgr.containers["default"].Add(&fakeStateMachine{id: "device1"})
+	mgr.containers["channel"].Add(&fakeStateMachine{id: "device2"})
+
+	// dispatching loop.
+	atomic.StoreInt32(&mgr.started, 1)
+	go func() {
+		for action := range mgr.actionCh {
+			action()
+		}
+	}()
+	defer close(mgr.actionCh)
+
+	actors, err := mgr.ListActors(context.Background(), "")
+	assert.Nil(t, err)
+	assert.Len(t, actors, 2)
+	assert.Equal(t, "device1", actors[0].Id)
+	assert.Equal(t, "default", actors[0].Container)
+	assert.Equal(t, []string{"device2.temp"}, actors[0].Tentacles)
+
+	actors, err = mgr.ListActors(context.Background(), "channel")
+	assert.Nil(t, err)
+	assert.Len(t, actors, 1)
+
+	actor, err := mgr.FlushActor(context.Background(), "device2")
+	assert.Nil(t, err)
+	assert.Equal(t, "channel", actor.Container)
+	assert.Equal(t, "active", actor.Status)
+
+	// nothing to flush if not resident.
+	assert.Nil(t, mgr.FlushResident(context.Background(), "device2"))
+	assert.Nil(t, mgr.FlushResident(context.Background(), "device9"))
+
+	assert.Nil(t, mgr.DetachActor(context.Background(), "device2"))
+	_, err = mgr.GetActor(context.Background(), "device2")
+	assert.ErrorIs(t, err, ErrStateMachineNotFound)
+	assert.Equal(t, 0, mgr.containers["channel"].Size())
+}
+
+func TestManager_DeleteStateMarchin(t *testing.T) {
+	mgr := &Manager{
+		containers: map[string]*Container{"default": NewContainer()},
+	}
+
+	sm := &fakeStateMachine{id: "device1", status: statem.SMStatusActive}
+	mgr.containers["default"].Add(sm)
+
+	base, err := mgr.DeleteStateMarchin(context.Background(), &statem.Base{ID: "device1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "device1", base.ID)
+	assert.True(t, base.DeletedAt > 0)
+	assert.Equal(t, statem.SMStatusDeleted, sm.status)
+	assert.Equal(t, 0, mgr.containers["default"].Size())
+}
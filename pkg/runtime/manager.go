@@ -21,7 +21,9 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	dapr "github.com/dapr/go-sdk/client"
@@ -32,12 +34,16 @@ import (
 	"github.com/tkeel-io/core/pkg/constraint"
 	"github.com/tkeel-io/core/pkg/environment"
 	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/metrics"
 	"github.com/tkeel-io/core/pkg/resource"
+	"github.com/tkeel-io/core/pkg/resource/search"
 	"github.com/tkeel-io/core/pkg/resource/tseries"
 	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/tracing"
 	"github.com/tkeel-io/core/pkg/util"
 	"github.com/tkeel-io/kit/log"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/structpb"
 )
@@ -51,16 +57,27 @@ type Manager struct {
 
 	daprClient    dapr.Client
 	etcdClient    *clientv3.Client
-	searchClient  pb.SearchHTTPServer
+	searchClient  search.Client
 	tseriesClient tseries.TimeSerier
 
-	shutdown chan struct{}
-	lock     sync.RWMutex
-	ctx      context.Context
-	cancel   context.CancelFunc
+	// inflight counts messages sent but not yet disposed.
+	inflight int64
+	closing  int32
+	started  int32
+	stopped  chan struct{}
+	actionCh chan func()
+	// resolveCh triggers resolving of mapper references.
+	resolveCh chan struct{}
+	timers    timerQueue
+	lock      sync.RWMutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	// firedTimers persists fired timers out of the dispatch loop.
+	firedTimers *firedTimers
 }
 
-func NewManager(ctx context.Context, coroutinePool *ants.Pool, searchClient pb.SearchHTTPServer) (*Manager, error) {
+func NewManager(ctx context.Context, coroutinePool *ants.Pool, searchClient search.Client) (*Manager, error) {
 	var (
 		daprClient dapr.Client
 		etcdClient *clientv3.Client
@@ -96,11 +113,16 @@ func NewManager(ctx context.Context, coroutinePool *ants.Pool, searchClient pb.S
 		msgCh:         make(chan statem.MessageContext, 10000),
 		disposeCh:     make(chan statem.MessageContext, 1000),
 		coroutinePool: coroutinePool,
+		stopped:       make(chan struct{}),
+		actionCh:      make(chan func()),
+		resolveCh:     make(chan struct{}, 1),
+		firedTimers:   newFiredTimers(etcdClient),
 		lock:          sync.RWMutex{},
 	}
 
 	// set default container.
 	mgr.containers["default"] = NewContainer()
+	metrics.Register(newRuntimeCollector(mgr))
 	return mgr, nil
 }
 
@@ -109,7 +131,23 @@ func (m *Manager) SendMsg(msgCtx statem.MessageContext) {
 	log.Debug("actor send message", zap.String("msg", string(bytes)))
 
 	// 解耦actor之间的直接调用
-	m.msgCh <- msgCtx
+	atomic.AddInt64(&m.inflight, 1)
+	m.deliver(msgCtx)
+}
+
+// deliver puts msg into mailbox, blocks while mailbox is full.
+func (m *Manager) deliver(msg statem.MessageContext) {
+	select {
+	case m.msgCh <- msg:
+	default:
+		metrics.MailboxOverflows.Inc()
+		m.msgCh <- msg
+	}
+}
+
+// Accepting returns false once the manager is shutting down.
+func (m *Manager) Accepting() bool {
+	return atomic.LoadInt32(&m.closing) == 0
 }
 
 func (m *Manager) init() error {
@@ -135,7 +173,9 @@ func (m *Manager) init() error {
 				zap.String("type", info.Type), logger.EntityID(info.EntityID))
 		}
 	}
-	return nil
+
+	log.Info("initialize actor manager, timers loadding...")
+	return errors.Wrap(m.loadTimers(ctx), "load all timers")
 }
 
 func (m *Manager) watchResource() error {
@@ -149,6 +189,31 @@ func (m *Manager) watchResource() error {
 		pair := environment.EtcdPair{Key: string(ev.Kv.Key), Value: ev.Kv.Value}
 		effects, _ := m.actorEnv.OnMapperChanged(ev.Type, pair)
 		m.reloadActor(effects)
+		m.triggerResolve()
+	})
+
+	// entities created or deleted, references may be resolved to them.
+	entityWatcher, err := util.NewWatcher(m.ctx, config.Get().Etcd.Address)
+	if nil != err {
+		return errors.Wrap(err, "create entity watcher failed")
+	}
+
+	entityWatcher.Watch(util.EtcdEntityPrefix, true, func(ev *clientv3.Event) {
+		m.triggerResolve()
+	})
+
+	// watch timers, set or cancelled by other instances.
+	timerWatcher, err := util.NewWatcher(m.ctx, config.Get().Etcd.Address)
+	if nil != err {
+		return errors.Wrap(err, "create timer watcher failed")
+	}
+
+	timerWatcher.Watch(util.EtcdTimerPrefix, true, func(ev *clientv3.Event) {
+		// fired timers persisted by this instance are already in queue.
+		if m.firedTimers.Echo(string(ev.Kv.Key), ev.Kv.ModRevision) {
+			return
+		}
+		m.onTimerChanged(ev.Type, ev.Kv.Key, ev.Kv.Value, util.UnixMilli())
 	})
 
 	return nil
@@ -182,7 +247,17 @@ func (m *Manager) Start() error {
 	m.init()
 	// watch resource.
 	m.watchResource()
+	atomic.StoreInt32(&m.started, 1)
+	go m.runResolver(config.Get().Mapper.ResolveInterval)
+	go m.firedTimers.Run(m.ctx)
 	go func() {
+		defer close(m.stopped)
+		tick := config.Get().Flush.Tick
+		if tick <= 0 {
+			tick = time.Second
+		}
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-m.ctx.Done():
@@ -193,30 +268,16 @@ func (m *Manager) Start() error {
 				m.disposeCh <- msgCtx
 
 			case msgCtx := <-m.disposeCh:
-				eid := msgCtx.Headers.GetTargetID()
-				channelID := msgCtx.Headers.Get(statem.MessageCtxHeaderChannelID)
-				log.Debug("dispose message", logger.EntityID(eid), logger.MessageInst(msgCtx))
-				channelID, stateMachine := m.getStateMachine(channelID, eid)
-				if nil == stateMachine {
-					var err error
-					en := &statem.Base{
-						ID:     eid,
-						Owner:  msgCtx.Headers.GetOwner(),
-						Source: msgCtx.Headers.GetSource(),
-						Type:   msgCtx.Headers.Get(statem.MessageCtxHeaderType),
-					}
-					stateMachine, err = m.loadOrCreate(m.ctx, channelID, true, en)
-					if nil != err {
-						log.Error("dispatching message", zap.Error(err),
-							logger.EntityID(eid), zap.String("channel", channelID), logger.MessageInst(msgCtx))
-						continue
-					}
-				}
-
-				stateMachine.OnMessage(msgCtx.Message)
-			case <-m.shutdown:
-				log.Info("state machine manager exit.")
-				return
+				m.dispose(msgCtx)
+
+			case action := <-m.actionCh:
+				// operator actions, serialized with disposing.
+				action()
+
+			case now := <-ticker.C:
+				// time-based flushing and timers, serialized with disposing.
+				m.flushDue(now)
+				m.fireTimers(now)
 			}
 		}
 	}()
@@ -224,9 +285,142 @@ func (m *Manager) Start() error {
 	return nil
 }
 
-func (m *Manager) Shutdown() {
+func (m *Manager) dispose(msgCtx statem.MessageContext) {
+	defer atomic.AddInt64(&m.inflight, -1)
+
+	eid := msgCtx.Headers.GetTargetID()
+	channelID := msgCtx.Headers.Get(statem.MessageCtxHeaderChannelID)
+	log.Debug("dispose message", logger.EntityID(eid), logger.MessageInst(msgCtx))
+	channelID, stateMachine := m.getStateMachine(channelID, eid)
+	if nil == stateMachine {
+		var err error
+		en := &statem.Base{
+			ID:     eid,
+			Owner:  msgCtx.Headers.GetOwner(),
+			Source: msgCtx.Headers.GetSource(),
+			Type:   msgCtx.Headers.Get(statem.MessageCtxHeaderType),
+		}
+		stateMachine, err = m.loadOrCreate(m.ctx, channelID, true, en)
+		if nil != err {
+			log.Error("dispatching message", zap.Error(err),
+				logger.EntityID(eid), zap.String("channel", channelID), logger.MessageInst(msgCtx))
+			return
+		}
+	}
+
+	if owner := msgCtx.Headers.GetOwner(); owner != stateMachine.GetBase().Owner {
+		// tenant isolation, drop message from another owner, messages without owner included.
+		log.Warn("dispatching message, owner mismatched", logger.EntityID(eid),
+			zap.String("owner", owner), logger.MessageInst(msgCtx))
+		return
+	}
+
+	ctx, span := tracing.Start(tracing.Extract(m.ctx, msgCtx.Headers), "runtime.dispose",
+		attribute.String("entity.id", eid), attribute.String("channel.id", channelID))
+	defer span.End()
+
+	stateMachine.OnMessage(ctx, msgCtx.Message)
+
+	// properties received, rearm idle timers.
+	if msg, ok := msgCtx.Message.(statem.PropertyMessage); ok && (msg.StateID == "" || msg.StateID == eid) {
+		m.timers.Touch(eid, util.UnixMilli())
+	}
+}
+
+// Shutdown stops accepting messages, drains pending messages
+// and flushes every state machine before ctx deadline.
+func (m *Manager) Shutdown(ctx context.Context) (DrainReport, error) {
+	var report DrainReport
+	if !atomic.CompareAndSwapInt32(&m.closing, 0, 1) {
+		return report, ErrManagerShutdown
+	}
+
+	// 1. drain pending messages.
+	report.Messages = atomic.LoadInt64(&m.inflight)
+	err := m.drain(ctx)
+	if nil != err {
+		log.Error("drain messages", zap.Error(err), zap.Int64("pending", atomic.LoadInt64(&m.inflight)))
+	}
+
+	// 2. stop dispatching.
 	m.cancel()
-	m.shutdown <- struct{}{}
+	if atomic.LoadInt32(&m.started) == 1 {
+		select {
+		case <-m.stopped:
+		case <-ctx.Done():
+		}
+	}
+
+	// 3. persist fired timers, flush state machines.
+	if err := m.firedTimers.Flush(ctx); nil != err {
+		log.Error("persist fired timers", zap.Error(err))
+	}
+	m.flushAll(ctx, &report)
+
+	log.Info("state machine manager shutdown",
+		zap.Int64("messages", report.Messages),
+		zap.Int("flushed", report.Flushed),
+		zap.Int("failed", report.Failed),
+		zap.Int("timeout", report.TimedOut))
+	return report, errors.Wrap(err, "shutdown state machine manager")
+}
+
+// drain waits until all pending messages disposed.
+func (m *Manager) drain(ctx context.Context) error {
+	if atomic.LoadInt32(&m.started) == 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for atomic.LoadInt64(&m.inflight) > 0 {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "drain messages")
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// flushAll flushes state machines of all containers concurrently.
+func (m *Manager) flushAll(ctx context.Context, report *DrainReport) {
+	var states []statem.StateMachiner
+	for _, container := range m.listContainers() {
+		states = append(states, container.States()...)
+	}
+
+	results := make(chan error, len(states))
+	for _, sm := range states {
+		go func(sm statem.StateMachiner) {
+			results <- sm.Flush(ctx)
+		}(sm)
+	}
+
+	for range states {
+		select {
+		case err := <-results:
+			if nil != err {
+				report.Failed++
+				continue
+			}
+			report.Flushed++
+		case <-ctx.Done():
+			report.TimedOut = len(states) - report.Flushed - report.Failed
+			return
+		}
+	}
+}
+
+// flushDue flushes state machines whose flush policy is due.
+func (m *Manager) flushDue(now time.Time) {
+	for _, container := range m.listContainers() {
+		for _, sm := range container.States() {
+			if err := sm.FlushDue(m.ctx, now); nil != err {
+				log.Error("flush state machine", logger.EntityID(sm.GetID()), zap.Error(err))
+			}
+		}
+	}
 }
 
 func (m *Manager) GetDaprClient() dapr.Client {
@@ -238,7 +432,8 @@ func (m *Manager) getStateMachine(cid, eid string) (string, statem.StateMachiner
 		cid = "default"
 	}
 
-	if container, ok := m.containers[cid]; ok {
+	containers := m.listContainers()
+	if container, ok := containers[cid]; ok {
 		if sm := container.Get(eid); nil != sm {
 			if sm.GetStatus() == statem.SMStatusDeleted {
 				container.Remove(eid)
@@ -248,7 +443,7 @@ func (m *Manager) getStateMachine(cid, eid string) (string, statem.StateMachiner
 		}
 	}
 
-	for channelID, container := range m.containers {
+	for channelID, container := range containers {
 		if sm := container.Get(eid); sm != nil {
 			if sm.GetStatus() == statem.SMStatusDeleted {
 				container.Remove(eid)
@@ -257,10 +452,7 @@ func (m *Manager) getStateMachine(cid, eid string) (string, statem.StateMachiner
 
 			if channelID == "default" && cid != channelID {
 				container.Remove(sm.GetID())
-				if _, ok := m.containers[cid]; !ok {
-					m.containers[cid] = NewContainer()
-				}
-				m.containers[cid].Add(sm)
+				m.getContainer(cid).Add(sm)
 			}
 			return cid, sm
 		}
@@ -282,9 +474,18 @@ func (m *Manager) loadOrCreate(ctx context.Context, channelID string, flagCreate
 	if nil != err && !flagCreate {
 		return nil, errors.Wrap(err, "load state machine")
 	} else if en, err = statem.DecodeBase(res.Value); nil == err {
+		if en.DeletedAt > 0 {
+			// soft-deleted entity is not loaded until restored.
+			return nil, ErrEntityDeleted
+		}
 		base = en // decode value to statem.Base.
 	} else if !flagCreate {
 		return nil, errors.Wrap(err, "load state machine, state not found")
+	} else if _, err = m.etcdClient.Put(ctx, util.FormatEntity(base.ID), base.Type); nil != err {
+		// register entity created at runtime, used by reconciler.
+		log.Warn("register entity", logger.EntityID(base.ID), zap.Error(err))
+	} else if _, err = m.etcdClient.Put(ctx, util.FormatTenantEntity(base.Owner, base.ID), base.Type); nil != err {
+		log.Warn("register tenant entity", logger.EntityID(base.ID), zap.Error(err))
 	}
 
 	log.Debug("load or create state machiner",
@@ -298,6 +499,10 @@ func (m *Manager) loadOrCreate(ctx context.Context, channelID string, flagCreate
 		if sm, err = newSubscription(ctx, m, base); nil != err {
 			return nil, errors.Wrap(err, "load subscription")
 		}
+	case StateMachineTypeAlarm:
+		if sm, err = newAlarm(ctx, m, base); nil != err {
+			return nil, errors.Wrap(err, "load alarm")
+		}
 	default:
 		// default base entity type.
 		if sm, err = statem.NewState(ctx, m, base, nil); nil != err {
@@ -309,21 +514,68 @@ func (m *Manager) loadOrCreate(ctx context.Context, channelID string, flagCreate
 		channelID = "defult"
 	}
 
-	if _, has := m.containers[channelID]; !has {
-		m.containers[channelID] = NewContainer()
-	}
-
 	thisActorEnv := m.actorEnv.GetActorEnv(sm.GetID())
 	sm.LoadEnvironments(thisActorEnv)
 
+	if len(base.Configs) > 0 {
+		m.updateSearchMapping(ctx, base)
+	}
+
 	sm.Setup()
-	m.containers[channelID].Add(sm)
+	m.getContainer(channelID).Add(sm)
 	return sm, nil
 }
 
+// getContainer returns container of the channel, created if not exists.
+func (m *Manager) getContainer(channelID string) *Container {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, has := m.containers[channelID]; !has {
+		m.containers[channelID] = NewContainer()
+	}
+	return m.containers[channelID]
+}
+
+// listContainers returns containers keyed by channel id.
+func (m *Manager) listContainers() map[string]*Container {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	containers := make(map[string]*Container, len(m.containers))
+	for channelID, container := range m.containers {
+		containers[channelID] = container
+	}
+	return containers
+}
+
+// Stats returns statistics of containers, queues and coroutine pool.
+func (m *Manager) Stats() *pb.ActorStats {
+	stats := &pb.ActorStats{
+		Inflight:             atomic.LoadInt64(&m.inflight),
+		MsgQueueLength:       int64(len(m.msgCh)),
+		MsgQueueCapacity:     int64(cap(m.msgCh)),
+		DisposeQueueLength:   int64(len(m.disposeCh)),
+		DisposeQueueCapacity: int64(cap(m.disposeCh)),
+	}
+
+	for channelID, container := range m.listContainers() {
+		size := int64(container.Size())
+		stats.StateMachines += size
+		stats.Containers = append(stats.Containers, &pb.ContainerStats{Id: channelID, StateMachines: size})
+	}
+	sort.Slice(stats.Containers, func(i, j int) bool {
+		return stats.Containers[i].Id < stats.Containers[j].Id
+	})
+
+	if nil != m.coroutinePool {
+		stats.CoroutinesRunning = int64(m.coroutinePool.Running())
+		stats.CoroutinesCapacity = int64(m.coroutinePool.Cap())
+	}
+	return stats
+}
+
 func (m *Manager) HandleMsg(ctx context.Context, msg statem.MessageContext) {
 	// dispose message from pubsub.
-	m.msgCh <- msg
+	m.deliver(msg)
 }
 
 // Tools.
@@ -350,6 +602,7 @@ func (m *Manager) SetProperties(ctx context.Context, en *statem.Base) error {
 		},
 	}
 	msgCtx.Headers.SetOwner(en.Owner)
+	tracing.Inject(ctx, msgCtx.Headers)
 	msgCtx.Headers.SetTargetID(en.ID)
 	msgCtx.Headers.SetSource(en.Source)
 	msgCtx.Headers.Set(statem.MessageCtxHeaderType, en.Type)
@@ -383,6 +636,7 @@ func (m *Manager) PatchEntity(ctx context.Context, en *statem.Base, patchData []
 
 			// set headers.
 			msgCtx.Headers.SetOwner(en.Owner)
+			tracing.Inject(ctx, msgCtx.Headers)
 			msgCtx.Headers.SetTargetID(en.ID)
 			msgCtx.Headers.Set(statem.MessageCtxHeaderType, en.Type)
 			m.SendMsg(msgCtx)
@@ -416,6 +670,9 @@ func (m *Manager) SetConfigs(ctx context.Context, en *statem.Base) error {
 		return errors.Wrap(err, "set entity configs")
 	}
 
+	// update search index mapping.
+	m.updateSearchMapping(ctx, stateMachine.GetBase())
+
 	// flush entity configs.
 	return errors.Wrap(stateMachine.Flush(ctx), "set entity configs")
 }
@@ -444,6 +701,9 @@ func (m *Manager) PatchConfigs(ctx context.Context, en *statem.Base, patchData [
 		return errors.Wrap(err, "set entity configs")
 	}
 
+	// update search index mapping.
+	m.updateSearchMapping(ctx, stateMachine.GetBase())
+
 	// flush entity configs.
 	return errors.Wrap(stateMachine.Flush(ctx), "set entity configs")
 }
@@ -472,6 +732,9 @@ func (m *Manager) AppendConfigs(ctx context.Context, en *statem.Base) error {
 		return errors.Wrap(err, "append entity configs")
 	}
 
+	// update search index mapping.
+	m.updateSearchMapping(ctx, stateMachine.GetBase())
+
 	// flush entity configs.
 	return errors.Wrap(stateMachine.Flush(ctx), "append entity configs")
 }
@@ -500,32 +763,104 @@ func (m *Manager) RemoveConfigs(ctx context.Context, en *statem.Base, propertyID
 		return errors.Wrap(err, "remove entity configs")
 	}
 
+	// update search index mapping.
+	m.updateSearchMapping(ctx, stateMachine.GetBase())
+
 	// flush entity configs.
 	return errors.Wrap(stateMachine.Flush(ctx), "remove entity configs")
 }
 
-// DeleteStateMachine delete runtime.Entity.
+// SetRelationship adds or replaces a relationship from entity, flushes entity.
+func (m *Manager) SetRelationship(ctx context.Context, en *statem.Base, rel statem.Relationship) error {
+	err := m.updateRelationships(ctx, en, func(base *statem.Base) error {
+		base.SetRelationship(rel)
+		return nil
+	})
+	if nil == err {
+		m.triggerResolve()
+	}
+	return errors.Wrap(err, "set entity relationship")
+}
+
+// RemoveRelationship removes a relationship from entity, flushes entity.
+func (m *Manager) RemoveRelationship(ctx context.Context, en *statem.Base, typ, target string) error {
+	err := m.updateRelationships(ctx, en, func(base *statem.Base) error {
+		if !base.RemoveRelationship(typ, target) {
+			return ErrRelationshipNotFound
+		}
+		return nil
+	})
+	if nil == err {
+		m.triggerResolve()
+	}
+	return errors.Wrap(err, "remove entity relationship")
+}
+
+// updateRelationships updates relationships of state machine in the loop, restores them if flush failed.
+func (m *Manager) updateRelationships(ctx context.Context, en *statem.Base, update func(base *statem.Base) error) error {
+	return m.runInLoop(ctx, func() error {
+		channelID, stateMachine := m.findStateMachine(en.ID)
+		if nil == stateMachine {
+			var err error
+			if stateMachine, err = m.loadOrCreate(ctx, channelID, false, en); nil != err {
+				return errors.Wrap(err, "load state machine")
+			}
+		}
+
+		base := stateMachine.GetBase()
+		relationships := append([]statem.Relationship{}, base.Relationships...)
+		if err := update(base); nil != err {
+			return err
+		} else if err = stateMachine.Flush(ctx); nil != err {
+			base.Relationships = relationships
+			return errors.Wrap(err, "flush state machine")
+		}
+		return nil
+	})
+}
+
+// DeleteStateMarchin marks entity deleted, flushes and removes state machine from runtime.
 func (m *Manager) DeleteStateMarchin(ctx context.Context, base *statem.Base) (*statem.Base, error) {
-	var err error
-	channelID, stateMachine := m.getStateMachine("", base.ID)
-	if nil == stateMachine {
-		if stateMachine, err = m.loadOrCreate(m.ctx, channelID, true, base); nil != err {
-			log.Error("remove configs",
-				logger.EntityID(base.ID),
-				zap.Any("entity", base),
-				zap.String("channel", channelID))
-			return nil, errors.Wrap(err, "remove entity configs")
+	var deleted statem.Base
+	err := m.runInLoop(ctx, func() error {
+		channelID, stateMachine := m.findStateMachine(base.ID)
+		if nil == stateMachine {
+			var err error
+			if stateMachine, err = m.loadOrCreate(ctx, channelID, false, base); nil != err {
+				return errors.Wrap(err, "load state machine")
+			}
+			channelID, _ = m.findStateMachine(base.ID)
+		}
+
+		// persist deletion mark, the state is kept until purged.
+		status := stateMachine.GetStatus()
+		stateMachine.SetStatus(statem.SMStatusDeleted)
+		stateMachine.GetBase().DeletedAt = util.UnixMilli()
+		if err := stateMachine.Flush(ctx); nil != err {
+			stateMachine.GetBase().DeletedAt = 0
+			stateMachine.SetStatus(status)
+			return errors.Wrap(err, "flush state machine")
 		}
+
+		m.getContainer(channelID).Remove(base.ID)
+		m.removeTimers(ctx, base.ID)
+		deleted = stateMachine.GetBase().Copy()
+		log.Info("delete state machine", logger.EntityID(base.ID))
+		return nil
+	})
+
+	if nil != err {
+		log.Error("delete state machine", logger.EntityID(base.ID), zap.Error(err))
+		return nil, errors.Wrap(err, "delete state machine")
 	}
-	stateMachine.SetStatus(statem.SMStatusDeleted)
-	return stateMachine.GetBase(), nil
+	return &deleted, nil
 }
 
 // CleanEntity clean entity.
 func (m *Manager) CleanEntity(ctx context.Context, id string) error {
 	channelID, sm := m.getStateMachine("", id)
 	if nil != sm {
-		m.containers[channelID].Remove(id)
+		m.getContainer(channelID).Remove(id)
 	}
 	return nil
 }
@@ -546,6 +881,7 @@ func (m *Manager) AppendMapper(ctx context.Context, en *statem.Base) error {
 	}
 
 	msgCtx.Headers.SetOwner(en.Owner)
+	tracing.Inject(ctx, msgCtx.Headers)
 	msgCtx.Headers.SetTargetID(en.ID)
 	m.SendMsg(msgCtx)
 	return nil
@@ -567,6 +903,7 @@ func (m *Manager) RemoveMapper(ctx context.Context, en *statem.Base) error {
 	}
 
 	msgCtx.Headers.SetOwner(en.Owner)
+	tracing.Inject(ctx, msgCtx.Headers)
 	msgCtx.Headers.SetTargetID(en.ID)
 
 	m.SendMsg(msgCtx)
@@ -574,6 +911,16 @@ func (m *Manager) RemoveMapper(ctx context.Context, en *statem.Base) error {
 	return nil
 }
 
+// updateSearchMapping update search index mapping with entity configs.
+func (m *Manager) updateSearchMapping(ctx context.Context, base *statem.Base) {
+	if err := m.searchClient.UpdateMapping(ctx, base.Type, base.Configs); nil != err {
+		log.Warn("update search index mapping",
+			logger.EntityID(base.ID),
+			zap.String("type", base.Type),
+			zap.Error(err))
+	}
+}
+
 func (m *Manager) SearchFlush(ctx context.Context, values map[string]interface{}) error {
 	var err error
 	var val *structpb.Value
@@ -586,12 +933,19 @@ func (m *Manager) SearchFlush(ctx context.Context, values map[string]interface{}
 }
 
 func (m *Manager) TimeSeriesFlush(ctx context.Context, tds []tseries.TSeriesData) error {
+	m.lock.RLock()
+	tseriesClient := m.tseriesClient
+	m.lock.RUnlock()
+
 	var err error
 	for _, data := range tds {
 		data.Fields["value"] = data.Value
 		line := fmt.Sprintf("%s,%s %s", data.Measurement, util.ExtractMap(data.Tags), util.ExtractMap(data.Fields))
+		if data.Timestamp > 0 {
+			line = fmt.Sprintf("%s %d", line, data.Timestamp)
+		}
 
-		_, err = m.tseriesClient.Write(ctx, &tseries.TSeriesRequest{
+		_, err = tseriesClient.Write(ctx, &tseries.TSeriesRequest{
 			Data:     []string{line},
 			Metadata: map[string]string{},
 		})
@@ -617,3 +971,16 @@ func uuid() string {
 	uuid[6] = uuid[6]&^0xf0 | 0x40
 	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
 }
+
+// ReloadTimeSeries rebuilds time-series client from configuration, used at runtime.
+func (m *Manager) ReloadTimeSeries(cfg config.Metadata) error {
+	tseriesClient := tseries.NewTimeSerier(cfg.Name)
+	if err := tseriesClient.Init(resource.ParseFrom(cfg)); nil != err {
+		return errors.Wrap(err, "reload time-series client")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.tseriesClient = tseriesClient
+	return nil
+}
@@ -1,10 +1,128 @@
 package runtime
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/statem"
 )
 
 func Test_getStateMachine(t *testing.T) {
 	// pool, _ := ants.NewPool(200)
 	// stateManager, _ := NewManager(context.Background(), pool, nil)
 }
+
+type fakeStateMachine struct {
+	statem.StateMachiner
+	id     string
+	delay  time.Duration
+	base   *statem.Base
+	status statem.Status
+	// messages received.
+	messages []statem.Message
+}
+
+func (f *fakeStateMachine) GetID() string { return f.id }
+
+func (f *fakeStateMachine) GetBase() *statem.Base {
+	if nil == f.base {
+		f.base = &statem.Base{ID: f.id, Type: "DEVICE"}
+	}
+	return f.base
+}
+
+func (f *fakeStateMachine) GetStatus() statem.Status { return f.status }
+
+func (f *fakeStateMachine) SetStatus(status statem.Status) { f.status = status }
+
+func (f *fakeStateMachine) OnMessage(ctx context.Context, msg statem.Message) bool {
+	f.messages = append(f.messages, msg)
+	return false
+}
+
+func (f *fakeStateMachine) Inspect() statem.Inspection {
+	return statem.Inspection{Status: statem.SMStatusActive, Tentacles: []string{"device2.temp"}}
+}
+
+func (f *fakeStateMachine) Flush(ctx context.Context) error {
+	select {
+	case <-time.After(f.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func Test_disposeOwner(t *testing.T) {
+	mgr := &Manager{
+		ctx:        context.Background(),
+		containers: map[string]*Container{"default": NewContainer()},
+	}
+	sm := &fakeStateMachine{id: "device1", base: &statem.Base{ID: "device1", Type: "DEVICE", Owner: "admin"}}
+	mgr.containers["default"].Add(sm)
+
+	for _, owner := range []string{"", "other", "admin"} {
+		msgCtx := statem.MessageContext{
+			Headers: statem.Header{},
+			Message: statem.PropertyMessage{StateID: "device1", Properties: map[string]constraint.Node{"temp": constraint.IntNode(20)}},
+		}
+		msgCtx.Headers.SetTargetID("device1")
+		msgCtx.Headers.SetOwner(owner)
+		mgr.dispose(msgCtx)
+	}
+
+	// messages without owner or from other owners are dropped.
+	assert.Len(t, sm.messages, 1)
+}
+
+func Test_Shutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mgr := &Manager{
+		ctx:         ctx,
+		cancel:      cancel,
+		containers:  map[string]*Container{"default": NewContainer(), "channel": NewContainer()},
+		stopped:     make(chan struct{}),
+		firedTimers: newFiredTimers(fake.NewKV()),
+	}
+
+	mgr.containers["default"].Add(&fakeStateMachine{id: "device1"})
+	mgr.containers["default"].Add(&fakeStateMachine{id: "device2"})
+	mgr.containers["channel"].Add(&fakeStateMachine{id: "device3", delay: time.Minute})
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer shutdownCancel()
+
+	report, err := mgr.Shutdown(shutdownCtx)
+	assert.Nil(t, err)
+	assert.False(t, mgr.Accepting())
+	assert.Equal(t, 2, report.Flushed)
+	assert.Equal(t, 1, report.Failed+report.TimedOut)
+
+	_, err = mgr.Shutdown(context.Background())
+	assert.ErrorIs(t, err, ErrManagerShutdown)
+}
+
+func Test_Stats(t *testing.T) {
+	mgr := &Manager{
+		msgCh:      make(chan statem.MessageContext, 10),
+		disposeCh:  make(chan statem.MessageContext, 20),
+		containers: map[string]*Container{"default": NewContainer(), "channel": NewContainer()},
+	}
+
+	mgr.containers["default"].Add(&fakeStateMachine{id: "device1"})
+	mgr.containers["default"].Add(&fakeStateMachine{id: "device2"})
+	mgr.containers["channel"].Add(&fakeStateMachine{id: "device3"})
+	mgr.msgCh <- statem.MessageContext{}
+
+	stats := mgr.Stats()
+	assert.Equal(t, int64(3), stats.StateMachines)
+	assert.Len(t, stats.Containers, 2)
+	assert.Equal(t, "channel", stats.Containers[0].Id)
+	assert.Equal(t, int64(2), stats.Containers[1].StateMachines)
+	assert.Equal(t, int64(1), stats.MsgQueueLength)
+	assert.Equal(t, int64(20), stats.DisposeQueueCapacity)
+}
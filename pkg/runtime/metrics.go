@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	stateMachinesDesc = prometheus.NewDesc("core_runtime_state_machines",
+		"Number of resident state machines per container.", []string{"container"}, nil)
+	mailboxDepthDesc = prometheus.NewDesc("core_runtime_mailbox_depth",
+		"Number of messages in runtime mailbox, waiting to be dispatched to state machines.", nil, nil)
+	mailboxCapacityDesc = prometheus.NewDesc("core_runtime_mailbox_capacity",
+		"Capacity of runtime mailbox.", nil, nil)
+	queueLengthDesc = prometheus.NewDesc("core_runtime_queue_length",
+		"Number of messages in runtime queue.", []string{"queue"}, nil)
+	queueCapacityDesc = prometheus.NewDesc("core_runtime_queue_capacity",
+		"Capacity of runtime queue.", []string{"queue"}, nil)
+	inflightDesc = prometheus.NewDesc("core_runtime_inflight_messages",
+		"Number of messages sent but not yet disposed.", nil, nil)
+	poolRunningDesc = prometheus.NewDesc("core_runtime_coroutine_pool_running",
+		"Number of running goroutines in coroutine pool.", nil, nil)
+	poolCapacityDesc = prometheus.NewDesc("core_runtime_coroutine_pool_capacity",
+		"Capacity of coroutine pool.", nil, nil)
+)
+
+// runtimeCollector collects metrics of manager at scraping.
+type runtimeCollector struct {
+	mgr *Manager
+}
+
+func newRuntimeCollector(mgr *Manager) prometheus.Collector {
+	return &runtimeCollector{mgr: mgr}
+}
+
+func (c *runtimeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stateMachinesDesc
+	ch <- mailboxDepthDesc
+	ch <- mailboxCapacityDesc
+	ch <- queueLengthDesc
+	ch <- queueCapacityDesc
+	ch <- inflightDesc
+	ch <- poolRunningDesc
+	ch <- poolCapacityDesc
+}
+
+func (c *runtimeCollector) Collect(ch chan<- prometheus.Metric) {
+	for channelID, container := range c.mgr.listContainers() {
+		ch <- prometheus.MustNewConstMetric(stateMachinesDesc, prometheus.GaugeValue, float64(container.Size()), channelID)
+	}
+
+	ch <- prometheus.MustNewConstMetric(mailboxDepthDesc, prometheus.GaugeValue, float64(len(c.mgr.msgCh)))
+	ch <- prometheus.MustNewConstMetric(mailboxCapacityDesc, prometheus.GaugeValue, float64(cap(c.mgr.msgCh)))
+	ch <- prometheus.MustNewConstMetric(queueLengthDesc, prometheus.GaugeValue, float64(len(c.mgr.disposeCh)), "dispose")
+	ch <- prometheus.MustNewConstMetric(queueCapacityDesc, prometheus.GaugeValue, float64(cap(c.mgr.disposeCh)), "dispose")
+	ch <- prometheus.MustNewConstMetric(inflightDesc, prometheus.GaugeValue, float64(atomic.LoadInt64(&c.mgr.inflight)))
+
+	if pool := c.mgr.coroutinePool; nil != pool {
+		ch <- prometheus.MustNewConstMetric(poolRunningDesc, prometheus.GaugeValue, float64(pool.Running()))
+		ch <- prometheus.MustNewConstMetric(poolCapacityDesc, prometheus.GaugeValue, float64(pool.Cap()))
+	}
+}
@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/metrics"
+	"github.com/tkeel-io/core/pkg/statem"
+)
+
+func Test_runtimeCollector(t *testing.T) {
+	mgr := &Manager{
+		containers: map[string]*Container{"default": NewContainer()},
+		msgCh:      make(chan statem.MessageContext, 10),
+		disposeCh:  make(chan statem.MessageContext, 5),
+	}
+	mgr.containers["default"].Add(&fakeStateMachine{id: "device1"})
+	mgr.SendMsg(statem.MessageContext{})
+
+	expected := `
+# HELP core_runtime_inflight_messages Number of messages sent but not yet disposed.
+# TYPE core_runtime_inflight_messages gauge
+core_runtime_inflight_messages 1
+# HELP core_runtime_mailbox_depth Number of messages in runtime mailbox, waiting to be dispatched to state machines.
+# TYPE core_runtime_mailbox_depth gauge
+core_runtime_mailbox_depth 1
+# HELP core_runtime_queue_length Number of messages in runtime queue.
+# TYPE core_runtime_queue_length gauge
+core_runtime_queue_length{queue="dispose"} 0
+# HELP core_runtime_state_machines Number of resident state machines per container.
+# TYPE core_runtime_state_machines gauge
+core_runtime_state_machines{container="default"} 1
+`
+	err := testutil.CollectAndCompare(newRuntimeCollector(mgr), strings.NewReader(expected),
+		"core_runtime_inflight_messages", "core_runtime_mailbox_depth", "core_runtime_queue_length", "core_runtime_state_machines")
+	assert.Nil(t, err)
+}
+
+func TestManager_deliverOverflow(t *testing.T) {
+	mgr := &Manager{msgCh: make(chan statem.MessageContext, 1)}
+	overflows := testutil.ToFloat64(metrics.MailboxOverflows)
+
+	mgr.SendMsg(statem.MessageContext{})
+	assert.Equal(t, overflows, testutil.ToFloat64(metrics.MailboxOverflows))
+
+	// blocks until mailbox has room.
+	done := make(chan struct{})
+	go func() {
+		mgr.SendMsg(statem.MessageContext{})
+		close(done)
+	}()
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.MailboxOverflows) == overflows+1
+	}, time.Second, 10*time.Millisecond)
+	<-mgr.msgCh
+	<-done
+	assert.Len(t, mgr.msgCh, 1)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/mapper"
+	"github.com/tkeel-io/core/pkg/relation"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/tql"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// resolveDelay coalesces changes in a burst, and waits them indexed.
+	resolveDelay = time.Second
+	// maxReferenceEntities limits entities resolved by a reference.
+	maxReferenceEntities = 1000
+	referencePageSize    = 100
+)
+
+// triggerResolve schedules resolving of mapper references.
+func (m *Manager) triggerResolve() {
+	select {
+	case m.resolveCh <- struct{}{}:
+	default:
+	}
+}
+
+// runResolver resolves mapper references when triggered, and periodically if interval is positive.
+func (m *Manager) runResolver(interval time.Duration) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-tick:
+		case <-m.resolveCh:
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-time.After(resolveDelay):
+			}
+		}
+		m.resolveReferences(m.ctx)
+	}
+}
+
+// resolveReferences resolves references of mappers, reloads state machines whose tentacles changed.
+func (m *Manager) resolveReferences(ctx context.Context) {
+	for _, mp := range m.actorEnv.ReferenceMappers() {
+		resolved, err := m.resolveMapper(ctx, mp)
+		if nil != err {
+			// keep previous resolution.
+			log.Error("resolve mapper references", logger.EntityID(mp.TargetEntity()),
+				zap.String("mapper", mp.ID()), zap.Error(err))
+			continue
+		}
+
+		err = m.runInLoop(ctx, func() error {
+			return m.reloadActor(m.actorEnv.ResolveMapper(mp.TargetEntity(), mp.ID(), resolved))
+		})
+		if nil != err {
+			log.Error("reload resolved mapper", logger.EntityID(mp.TargetEntity()),
+				zap.String("mapper", mp.ID()), zap.Error(err))
+		}
+	}
+}
+
+// resolveMapper returns entities of mapper references, nothing if target entity not exists.
+func (m *Manager) resolveMapper(ctx context.Context, mp mapper.Mapper) (map[string][]string, error) {
+	resolved := make(map[string][]string)
+	item, err := m.daprClient.GetState(ctx, EntityStateName, mp.TargetEntity())
+	if nil != err {
+		return nil, errors.Wrap(err, "get target entity")
+	} else if nil == item || len(item.Value) == 0 {
+		return resolved, nil
+	}
+
+	target, err := statem.DecodeBase(item.Value)
+	if nil != err {
+		return nil, errors.Wrap(err, "decode target entity")
+	} else if target.DeletedAt > 0 {
+		return resolved, nil
+	}
+
+	for _, ref := range mp.References() {
+		if resolved[ref.Name], err = m.resolveReference(ctx, target, ref); nil != err {
+			return nil, errors.Wrap(err, "resolve reference "+ref.Name)
+		}
+	}
+	return resolved, nil
+}
+
+// resolveReference searches entities of reference owned by owner of target entity.
+func (m *Manager) resolveReference(ctx context.Context, target *statem.Base, ref tql.Reference) ([]string, error) {
+	conds := []*pb.SearchCondition{{
+		Field:    "owner",
+		Operator: "$eq",
+		Value:    structpb.NewStringValue(target.Owner),
+	}}
+
+	switch ref.Kind {
+	case tql.ReferenceRelated:
+		if ref.Direction == relation.DirectionIn {
+			field := statem.SearchFieldRelated
+			if ref.RelationshipType != "" {
+				field = statem.SearchFieldRelationships + "." + ref.RelationshipType
+			}
+			conds = append(conds, &pb.SearchCondition{Field: field, Operator: "$eq", Value: structpb.NewStringValue(target.ID)})
+			break
+		}
+
+		var targets []*structpb.Value
+		for _, rel := range target.GetRelationships(ref.RelationshipType) {
+			targets = append(targets, structpb.NewStringValue(rel.Target))
+		}
+		if len(targets) == 0 {
+			return nil, nil
+		}
+		conds = append(conds, &pb.SearchCondition{
+			Field:    "id",
+			Operator: "$in",
+			Value:    structpb.NewListValue(&structpb.ListValue{Values: targets}),
+		})
+	case tql.ReferenceSearch:
+		for _, cond := range ref.Conditions {
+			value := cond.Value
+			if cond.This {
+				value = target.ID
+			}
+			val, err := structpb.NewValue(value)
+			if nil != err {
+				return nil, errors.Wrap(err, "condition "+cond.Field)
+			}
+			conds = append(conds, &pb.SearchCondition{Field: cond.Field, Operator: "$eq", Value: val})
+		}
+	}
+
+	var ids []string
+	for pageNum := int32(1); len(ids) < maxReferenceEntities; pageNum++ {
+		resp, err := m.searchClient.Search(ctx, &pb.SearchRequest{
+			Owner:     target.Owner,
+			PageNum:   pageNum,
+			PageSize:  referencePageSize,
+			Condition: conds,
+		})
+		if nil != err {
+			return nil, errors.Wrap(err, "search entities")
+		}
+
+		for _, item := range resp.Items {
+			if id := item.GetStructValue().GetFields()["id"].GetStringValue(); id != "" && id != target.ID {
+				ids = append(ids, id)
+			}
+		}
+		if len(resp.Items) < referencePageSize {
+			break
+		}
+	}
+
+	if len(ids) > maxReferenceEntities {
+		ids = ids[:maxReferenceEntities]
+	}
+	return ids, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/mapper"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/tql"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestManager_resolveReference(t *testing.T) {
+	searchClient := fake.NewSearch(t)
+
+	room := &statem.Base{ID: "room1", Type: "ROOM", Owner: "admin"}
+	room.SetRelationship(statem.Relationship{Type: "contains", Target: "sensor1"})
+	room.SetRelationship(statem.Relationship{Type: "contains", Target: "sensor3"})
+	entities := []*statem.Base{
+		room,
+		{ID: "sensor1", Type: "thermometer", Owner: "admin", KValues: map[string]constraint.Node{"room": constraint.NewNode("room1")}},
+		{ID: "sensor2", Type: "thermometer", Owner: "admin", KValues: map[string]constraint.Node{"room": constraint.NewNode("room1")}},
+		{ID: "sensor3", Type: "thermometer", Owner: "other", KValues: map[string]constraint.Node{"room": constraint.NewNode("room1")}},
+	}
+	state := fake.NewState()
+	for _, base := range entities {
+		bytes, err := statem.EncodeBase(base)
+		assert.Nil(t, err)
+		state.Set(base.ID, bytes)
+		val, err := structpb.NewValue(base.SearchValues())
+		assert.Nil(t, err)
+		_, err = searchClient.Index(context.Background(), &pb.IndexObject{Obj: val})
+		assert.Nil(t, err)
+	}
+
+	mgr := &Manager{daprClient: state, searchClient: searchClient}
+
+	// related, entities of other owners are excluded.
+	ids, err := mgr.resolveReference(context.Background(), room, tql.Reference{
+		Name: "#sensors", Kind: tql.ReferenceRelated, RelationshipType: "contains", Direction: "out"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sensor1"}, ids)
+
+	ids, err = mgr.resolveReference(context.Background(), entities[1], tql.Reference{
+		Name: "#room", Kind: tql.ReferenceRelated, RelationshipType: "contains", Direction: "in"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"room1"}, ids)
+
+	// search.
+	ids, err = mgr.resolveReference(context.Background(), room, tql.Reference{
+		Name: "#sensors", Kind: tql.ReferenceSearch, Conditions: []tql.Condition{
+			{Field: "type", Value: "thermometer"}, {Field: "room", This: true}}})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"sensor1", "sensor2"}, ids)
+
+	// target entity not exists.
+	assert.Nil(t, state.DeleteState(context.Background(), "", "room1"))
+	mp, err := mapper.NewMapper("mapper1", "insert into room1 select #sensors.temp as temp from #sensors = related('contains')")
+	assert.Nil(t, err)
+	resolved, err := mgr.resolveMapper(context.Background(), mp)
+	assert.Nil(t, err)
+	assert.Empty(t, resolved)
+}
@@ -18,6 +18,7 @@ package runtime
 
 import (
 	"context"
+	"time"
 
 	dapr "github.com/dapr/go-sdk/client"
 	"github.com/pkg/errors"
@@ -127,6 +128,15 @@ func (s *subscription) Flush(ctx context.Context) error {
 	return errors.Wrap(s.stateMachine.Flush(ctx), "flush subscription")
 }
 
+func (s *subscription) FlushDue(ctx context.Context, now time.Time) error {
+	return errors.Wrap(s.stateMachine.FlushDue(ctx, now), "flush subscription")
+}
+
+// Inspect returns runtime snapshot of subscription.
+func (s *subscription) Inspect() statem.Inspection {
+	return s.stateMachine.Inspect()
+}
+
 // Setup setup filter.
 func (s *subscription) Setup() error {
 	return errors.Wrap(s.stateMachine.Setup(), "subscription setup")
@@ -187,8 +197,8 @@ func (s *subscription) RemoveConfigs(propertyIDs []string) error {
 }
 
 // OnMessage recv message from pubsub.
-func (s *subscription) OnMessage(msg statem.Message) bool {
-	return s.stateMachine.OnMessage(msg)
+func (s *subscription) OnMessage(ctx context.Context, msg statem.Message) bool {
+	return s.stateMachine.OnMessage(ctx, msg)
 }
 
 // InvokeMsg dispose entity message.
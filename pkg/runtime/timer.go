@@ -0,0 +1,375 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/util"
+	"github.com/tkeel-io/kit/log"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// timerQueue holds armed timers, keyed by entity id and timer id.
+type timerQueue struct {
+	lock   sync.Mutex
+	timers map[string]map[string]*statem.Timer
+}
+
+// Set adds or replaces timer, armed at now.
+func (q *timerQueue) Set(timer statem.Timer, now int64) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if nil == q.timers {
+		q.timers = make(map[string]map[string]*statem.Timer)
+	}
+	if _, has := q.timers[timer.EntityID]; !has {
+		q.timers[timer.EntityID] = make(map[string]*statem.Timer)
+	}
+
+	timer.Arm(now)
+	q.timers[timer.EntityID][timer.ID] = &timer
+}
+
+// Remove removes timer, returns false if not exists.
+func (q *timerQueue) Remove(entityID, timerID string) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if _, has := q.timers[entityID][timerID]; !has {
+		return false
+	}
+
+	delete(q.timers[entityID], timerID)
+	if len(q.timers[entityID]) == 0 {
+		delete(q.timers, entityID)
+	}
+	return true
+}
+
+// RemoveEntity removes all timers of entity, returns false if entity has no timer.
+func (q *timerQueue) RemoveEntity(entityID string) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	_, has := q.timers[entityID]
+	delete(q.timers, entityID)
+	return has
+}
+
+// List returns timers of entity sorted by id.
+func (q *timerQueue) List(entityID string) []statem.Timer {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	timers := make([]statem.Timer, 0, len(q.timers[entityID]))
+	for _, timer := range q.timers[entityID] {
+		timers = append(timers, *timer)
+	}
+	sort.Slice(timers, func(i, j int) bool { return timers[i].ID < timers[j].ID })
+	return timers
+}
+
+// Touch rearms idle timers of entity which receives properties at now.
+func (q *timerQueue) Touch(entityID string, now int64) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for _, timer := range q.timers[entityID] {
+		timer.Touch(now)
+	}
+}
+
+// Fire advances due timers, returns them. fired one-shot timers are removed.
+func (q *timerQueue) Fire(now int64) []statem.Timer {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	var fired []statem.Timer
+	for entityID, timers := range q.timers {
+		for timerID, timer := range timers {
+			if !timer.Due(now) {
+				continue
+			} else if !timer.Fire(now) {
+				delete(timers, timerID)
+			}
+			fired = append(fired, *timer)
+		}
+		if len(timers) == 0 {
+			delete(q.timers, entityID)
+		}
+	}
+
+	sort.Slice(fired, func(i, j int) bool {
+		if fired[i].EntityID != fired[j].EntityID {
+			return fired[i].EntityID < fired[j].EntityID
+		}
+		return fired[i].ID < fired[j].ID
+	})
+	return fired
+}
+
+// SetTimer persists timer of entity, replaces the existing one with the same id.
+func (m *Manager) SetTimer(ctx context.Context, timer statem.Timer) error {
+	if err := timer.Validate(); nil != err {
+		return errors.Wrap(err, "set timer")
+	}
+
+	timer.Arm(util.UnixMilli())
+	bytes, err := json.Marshal(timer)
+	if nil != err {
+		return errors.Wrap(err, "set timer")
+	} else if _, err = m.etcdClient.Put(ctx, util.FormatTimer(timer.EntityID, timer.ID), string(bytes)); nil != err {
+		return errors.Wrap(err, "set timer")
+	}
+
+	m.timers.Set(timer, util.UnixMilli())
+	log.Info("set timer", logger.EntityID(timer.EntityID), zap.String("timer", timer.ID),
+		zap.Int64("due_time", timer.DueTime), zap.Int64("interval", timer.Interval), zap.Bool("idle", timer.Idle))
+	return nil
+}
+
+// CancelTimer removes timer of entity.
+func (m *Manager) CancelTimer(ctx context.Context, entityID, timerID string) error {
+	res, err := m.etcdClient.Delete(ctx, util.FormatTimer(entityID, timerID))
+	if nil != err {
+		return errors.Wrap(err, "cancel timer")
+	}
+
+	removed := m.timers.Remove(entityID, timerID)
+	if res.Deleted == 0 && !removed {
+		return errors.Wrapf(statem.ErrTimerNotFound, "timer %q", timerID)
+	}
+	log.Info("cancel timer", logger.EntityID(entityID), zap.String("timer", timerID))
+	return nil
+}
+
+// ListTimers returns timers of entity.
+func (m *Manager) ListTimers(entityID string) []statem.Timer {
+	return m.timers.List(entityID)
+}
+
+// removeTimers removes timers of deleted entity, all persisted timers are loaded in queue.
+func (m *Manager) removeTimers(ctx context.Context, entityID string) {
+	if !m.timers.RemoveEntity(entityID) {
+		return
+	} else if _, err := m.etcdClient.Delete(ctx, util.FormatTimer(entityID, ""), clientv3.WithPrefix()); nil != err {
+		log.Warn("remove timers", logger.EntityID(entityID), zap.Error(err))
+	}
+}
+
+// loadTimers loads persisted timers, timers due while core is down are fired at once.
+func (m *Manager) loadTimers(ctx context.Context) error {
+	res, err := m.etcdClient.Get(ctx, util.EtcdTimerPrefix, clientv3.WithPrefix())
+	if nil != err {
+		return errors.Wrap(err, "load timers")
+	}
+
+	now := util.UnixMilli()
+	for _, kv := range res.Kvs {
+		m.onTimerChanged(mvccpb.PUT, kv.Key, kv.Value, now)
+	}
+	return nil
+}
+
+// onTimerChanged updates timer queue with persisted timer.
+func (m *Manager) onTimerChanged(typ mvccpb.Event_EventType, key, value []byte, now int64) {
+	if typ == mvccpb.DELETE {
+		// timer id contains no '.'.
+		key := strings.TrimPrefix(string(key), util.EtcdTimerPrefix+".")
+		if index := strings.LastIndex(key, "."); index > 0 {
+			m.timers.Remove(key[:index], key[index+1:])
+		}
+		return
+	}
+
+	var timer statem.Timer
+	if err := json.Unmarshal(value, &timer); nil != err {
+		log.Error("decode timer", zap.String("key", string(key)), zap.Error(err))
+		return
+	}
+	m.timers.Set(timer, now)
+}
+
+// fireTimers delivers messages of due timers into entities, serialized with disposing.
+// fired timers are persisted out of the dispatch loop.
+func (m *Manager) fireTimers(now time.Time) {
+	nowMilli := now.UnixNano() / 1e6
+	fired := m.timers.Fire(nowMilli)
+	for _, timer := range fired {
+		msgCtx := statem.MessageContext{
+			Headers: statem.Header{},
+			Message: timer.Message(nowMilli),
+		}
+		msgCtx.Headers.SetOwner(timer.Owner)
+		msgCtx.Headers.SetTargetID(timer.EntityID)
+
+		atomic.AddInt64(&m.inflight, 1)
+		m.dispose(msgCtx)
+	}
+	m.firedTimers.Add(fired)
+}
+
+// timerTxnOps is the max number of ops in a txn, the default limit of etcd.
+const timerTxnOps = 128
+
+// firedTimers persists due time of fired periodic timers and deletes fired one-shot timers.
+// timers fired again before persisted are coalesced, pending timers are written in one txn.
+type firedTimers struct {
+	kv     clientv3.KV
+	notify chan struct{}
+
+	lock    sync.Mutex
+	pending map[string]statem.Timer
+
+	// revisions of own writes by key, their watch echoes are ignored.
+	// held while committing, so echoes arriving before the response wait for the revision.
+	revLock   sync.Mutex
+	revisions map[string]int64
+}
+
+func newFiredTimers(kv clientv3.KV) *firedTimers {
+	return &firedTimers{
+		kv:        kv,
+		notify:    make(chan struct{}, 1),
+		pending:   make(map[string]statem.Timer),
+		revisions: make(map[string]int64),
+	}
+}
+
+// Add queues fired timers to persist, never blocks.
+func (f *firedTimers) Add(timers []statem.Timer) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, timer := range timers {
+		// due time of idle timer is not persisted.
+		if !timer.Idle {
+			f.pending[util.FormatTimer(timer.EntityID, timer.ID)] = timer
+		}
+	}
+
+	if len(f.pending) > 0 {
+		select {
+		case f.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run persists queued timers until ctx is done.
+func (f *firedTimers) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.notify:
+			if err := f.Flush(ctx); nil != err {
+				log.Error("persist fired timers", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Flush persists queued timers, timers failed are queued again unless fired since.
+func (f *firedTimers) Flush(ctx context.Context) error {
+	f.lock.Lock()
+	pending := f.pending
+	f.pending = make(map[string]statem.Timer)
+	f.lock.Unlock()
+
+	keys := make([]string, 0, len(pending))
+	for key := range pending {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for start := 0; start < len(keys); start += timerTxnOps {
+		end := start + timerTxnOps
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		if err := f.commit(ctx, keys[start:end], pending); nil != err {
+			f.requeue(keys[start:], pending)
+			return errors.Wrap(err, "persist fired timers")
+		}
+	}
+	return nil
+}
+
+func (f *firedTimers) commit(ctx context.Context, keys []string, timers map[string]statem.Timer) error {
+	ops := make([]clientv3.Op, 0, len(keys))
+	for _, key := range keys {
+		timer := timers[key]
+		if timer.Interval <= 0 {
+			ops = append(ops, clientv3.OpDelete(key))
+			continue
+		}
+
+		bytes, err := json.Marshal(timer)
+		if nil != err {
+			return errors.Wrap(err, "encode timer")
+		}
+		// do not put timer cancelled while firing.
+		ops = append(ops, clientv3.OpTxn(
+			[]clientv3.Cmp{clientv3.Compare(clientv3.CreateRevision(key), ">", 0)},
+			[]clientv3.Op{clientv3.OpPut(key, string(bytes))}, nil))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	f.revLock.Lock()
+	defer f.revLock.Unlock()
+	res, err := f.kv.Txn(ctx).Then(ops...).Commit()
+	if nil != err {
+		return errors.Wrap(err, "commit txn")
+	}
+	for _, key := range keys {
+		f.revisions[key] = res.Header.Revision
+	}
+	return nil
+}
+
+func (f *firedTimers) requeue(keys []string, timers map[string]statem.Timer) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, key := range keys {
+		if _, has := f.pending[key]; !has {
+			f.pending[key] = timers[key]
+		}
+	}
+}
+
+// Echo returns true if change of key at revision is written by fired timers.
+// revisions not newer than the change are forgotten, watch delivers changes of key in order.
+func (f *firedTimers) Echo(key string, revision int64) bool {
+	f.revLock.Lock()
+	defer f.revLock.Unlock()
+	own, has := f.revisions[key]
+	if !has || revision < own {
+		return false
+	}
+	delete(f.revisions, key)
+	return revision == own
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/util"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+func TestTimerQueue(t *testing.T) {
+	var queue timerQueue
+	queue.Set(statem.Timer{ID: "once", EntityID: "device1", DueTime: 1000}, 0)
+	queue.Set(statem.Timer{ID: "tick", EntityID: "device1", Interval: 1000}, 0)
+	queue.Set(statem.Timer{ID: "offline", EntityID: "device2", Interval: 1000, Idle: true}, 0)
+	assert.Len(t, queue.List("device1"), 2)
+	assert.Equal(t, "once", queue.List("device1")[0].ID)
+
+	// idle timer is rearmed by properties.
+	queue.Touch("device2", 500)
+	fired := queue.Fire(1000)
+	assert.Len(t, fired, 2)
+	assert.Equal(t, "once", fired[0].ID)
+	assert.Equal(t, int64(2000), fired[1].DueTime)
+	assert.Len(t, queue.List("device1"), 1)
+
+	fired = queue.Fire(1500)
+	assert.Len(t, fired, 1)
+	assert.Equal(t, "offline", fired[0].ID)
+	assert.Empty(t, queue.Fire(1800))
+
+	assert.True(t, queue.Remove("device1", "tick"))
+	assert.False(t, queue.Remove("device1", "tick"))
+	assert.True(t, queue.RemoveEntity("device2"))
+	assert.False(t, queue.RemoveEntity("device2"))
+	assert.Empty(t, queue.Fire(10000))
+}
+
+func TestManager_onTimerChanged(t *testing.T) {
+	mgr := &Manager{}
+	timer := statem.Timer{ID: "tick", EntityID: "device.1", Owner: "admin", Interval: 1000}
+	bytes, _ := json.Marshal(timer)
+	key := []byte(util.FormatTimer(timer.EntityID, timer.ID))
+
+	mgr.onTimerChanged(mvccpb.PUT, key, bytes, 0)
+	assert.Len(t, mgr.ListTimers("device.1"), 1)
+	assert.Equal(t, int64(1000), mgr.ListTimers("device.1")[0].DueTime)
+	mgr.onTimerChanged(mvccpb.PUT, key, []byte("invalid"), 0)
+	assert.Len(t, mgr.ListTimers("device.1"), 1)
+	mgr.onTimerChanged(mvccpb.DELETE, key, nil, 0)
+	assert.Empty(t, mgr.ListTimers("device.1"))
+}
+
+func TestFiredTimers(t *testing.T) {
+	kv := fake.NewKV()
+	tickKey, onceKey := util.FormatTimer("device1", "tick"), util.FormatTimer("device1", "once")
+	kv.Set(tickKey, "{}")
+	kv.Set(onceKey, "{}")
+
+	fired := newFiredTimers(kv)
+	fired.Add([]statem.Timer{
+		{ID: "tick", EntityID: "device1", Interval: 1000, DueTime: 2000},
+		{ID: "once", EntityID: "device1", DueTime: 1000},
+		// cancelled while firing.
+		{ID: "cancelled", EntityID: "device1", Interval: 1000, DueTime: 2000},
+		{ID: "offline", EntityID: "device2", Interval: 1000, Idle: true},
+	})
+	// fired again before persisted.
+	fired.Add([]statem.Timer{{ID: "tick", EntityID: "device1", Interval: 1000, DueTime: 3000}})
+	assert.Len(t, fired.notify, 1)
+
+	assert.Nil(t, fired.Flush(context.Background()))
+	assert.Empty(t, fired.pending)
+	values := kv.Values()
+	assert.Len(t, values, 1)
+	var timer statem.Timer
+	assert.Nil(t, json.Unmarshal([]byte(values[tickKey]), &timer))
+	assert.Equal(t, int64(3000), timer.DueTime)
+
+	// echoes of own writes are ignored once.
+	res, err := kv.Get(context.Background(), tickKey)
+	assert.Nil(t, err)
+	revision := res.Kvs[0].ModRevision
+	assert.False(t, fired.Echo(tickKey, revision-1))
+	assert.True(t, fired.Echo(tickKey, revision))
+	assert.False(t, fired.Echo(tickKey, revision))
+	assert.True(t, fired.Echo(onceKey, revision))
+	assert.False(t, fired.Echo(util.FormatTimer("device1", "other"), revision))
+}
@@ -33,12 +33,32 @@ type WatchKey = mapper.WatchKey
 const (
 	StateMachineTypeBasic        = "BASIC"
 	StateMachineTypeSubscription = "SUBSCRIPTION"
+	StateMachineTypeAlarm        = "ALARM"
 )
 
 var (
-	ErrInvalidParams       = errors.New("invalid params")
-	ErrInvalidTQLKey       = errors.New("invalid TQL key")
-	ErrSubscriptionInvalid = errors.New("invalid subscription")
+	ErrInvalidParams        = errors.New("invalid params")
+	ErrInvalidTQLKey        = errors.New("invalid TQL key")
+	ErrSubscriptionInvalid  = errors.New("invalid subscription")
+	ErrManagerShutdown      = errors.New("state machine manager shutdown")
+	ErrStateMachineNotFound = errors.New("state machine not loaded")
+	ErrEntityDeleted        = errors.New("entity deleted")
+	ErrRelationshipNotFound = errors.New("relationship not found")
+	ErrManagerNotReady      = errors.New("state machine manager not ready")
+	ErrDaprUnhealthy        = errors.New("dapr sidecar unhealthy")
+	ErrStateMachineNotAlarm = errors.New("state machine is not alarm")
 )
 
+// DrainReport reports result of draining on shutdown.
+type DrainReport struct {
+	// Messages is the number of pending messages when shutdown.
+	Messages int64
+	// Flushed is the number of state machines flushed.
+	Flushed int
+	// Failed is the number of state machines failed to flush.
+	Failed int
+	// TimedOut is the number of state machines not flushed before deadline.
+	TimedOut int
+}
+
 type SMGenerator func(ctx context.Context, base *statem.Base) (statem.StateMachiner, error)
@@ -17,11 +17,59 @@ limitations under the License.
 package server
 
 import (
-	"github.com/tkeel-io/kit/transport/grpc"
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/kit/log"
+	"github.com/tkeel-io/kit/transport"
+	kitgrpc "github.com/tkeel-io/kit/transport/grpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
-// NewHTTPServer new a GRPC server.
-func NewGRPCServer(addr string) *grpc.Server {
-	s := grpc.NewServer(addr)
-	return s
+var _ transport.Server = (*GRPCServer)(nil)
+
+// GRPCServer is a grpc transport server accepting server options, e.g. interceptors.
+type GRPCServer struct {
+	Addr string
+	srv  *grpc.Server
+}
+
+// NewGRPCServer new a GRPC server.
+func NewGRPCServer(addr string, opts ...grpc.ServerOption) *GRPCServer {
+	if addr == "" {
+		addr = kitgrpc.DefaultPort
+	}
+	return &GRPCServer{
+		Addr: addr,
+		srv:  grpc.NewServer(opts...),
+	}
+}
+
+func (s *GRPCServer) GetServe() *grpc.Server {
+	return s.srv
+}
+
+func (s *GRPCServer) Type() transport.Type {
+	return transport.TypeGRPC
+}
+
+func (s *GRPCServer) Start(ctx context.Context) error {
+	l, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return errors.Wrap(err, "listen grpc address")
+	}
+	log.Debug("GRPC Server listen", zap.String("addr", s.Addr))
+	go func() {
+		if err := s.srv.Serve(l); err != nil {
+			log.Error("grpc serve", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+func (s *GRPCServer) Stop(ctx context.Context) error {
+	s.srv.Stop()
+	return nil
 }
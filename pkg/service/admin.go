@@ -0,0 +1,235 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/audit"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/purger"
+	"github.com/tkeel-io/core/pkg/reconciler"
+	"github.com/tkeel-io/core/pkg/runtime"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type AdminService struct {
+	pb.UnimplementedAdminServer
+	reconciler   *reconciler.Reconciler
+	purger       *purger.Purger
+	stateManager *runtime.Manager
+	auditStore   audit.Store
+}
+
+// NewAdminService returns a new AdminService, auditStore is nil if audit store disabled.
+func NewAdminService(reconciler *reconciler.Reconciler, purger *purger.Purger, stateManager *runtime.Manager, auditStore audit.Store) *AdminService {
+	return &AdminService{reconciler: reconciler, purger: purger, stateManager: stateManager, auditStore: auditStore}
+}
+
+func (s *AdminService) Reconcile(ctx context.Context, req *pb.ReconcileRequest) (*pb.ReconcileResponse, error) {
+	report, err := s.reconciler.Reconcile(ctx, req.DryRun)
+	if nil != err {
+		log.Error("reconcile", zap.Error(err))
+		return nil, errors.Wrap(err, "reconcile")
+	}
+	return &pb.ReconcileResponse{Report: report}, nil
+}
+
+func (s *AdminService) GetReconcileReport(ctx context.Context, req *pb.GetReconcileReportRequest) (*pb.ReconcileResponse, error) {
+	return &pb.ReconcileResponse{Report: s.reconciler.LastReport()}, nil
+}
+
+func (s *AdminService) ListDeletedEntities(ctx context.Context, req *pb.ListDeletedEntitiesRequest) (*pb.ListDeletedEntitiesResponse, error) {
+	tombstones, err := s.purger.Deleted(ctx)
+	if nil != err {
+		log.Error("list deleted entities", zap.Error(err))
+		return nil, errors.Wrap(err, "list deleted entities")
+	}
+
+	retention := config.Get().Deletion.Retention.Milliseconds()
+	out := &pb.ListDeletedEntitiesResponse{Total: int64(len(tombstones))}
+	for _, tombstone := range tombstones {
+		out.Items = append(out.Items, &pb.DeletedEntity{
+			Id:         tombstone.ID,
+			Type:       tombstone.Type,
+			Owner:      tombstone.Owner,
+			DeleteTime: tombstone.DeletedAt,
+			PurgeTime:  tombstone.DeletedAt + retention,
+		})
+	}
+	return out, nil
+}
+
+func (s *AdminService) PurgeEntities(ctx context.Context, req *pb.PurgeEntitiesRequest) (*pb.PurgeJobResponse, error) {
+	var err error
+	var job *pb.PurgeJob
+	if req.Expired {
+		job, err = s.purger.PurgeExpired(ctx)
+	} else {
+		job, err = s.purger.Purge(req.Ids, purger.TriggerManual)
+	}
+
+	if nil != err {
+		log.Error("purge entities", zap.Strings("entities", req.Ids), zap.Bool("expired", req.Expired), zap.Error(err))
+		return nil, errors.Wrap(err, "purge entities")
+	}
+	return &pb.PurgeJobResponse{Job: job}, nil
+}
+
+func (s *AdminService) ListPurgeJobs(ctx context.Context, req *pb.ListPurgeJobsRequest) (*pb.ListPurgeJobsResponse, error) {
+	jobs := s.purger.Jobs()
+	return &pb.ListPurgeJobsResponse{Total: int64(len(jobs)), Items: jobs}, nil
+}
+
+func (s *AdminService) GetPurgeJob(ctx context.Context, req *pb.GetPurgeJobRequest) (*pb.PurgeJobResponse, error) {
+	job, err := s.purger.Job(req.Id)
+	if nil != err {
+		return nil, errors.Wrap(err, "get purge job")
+	}
+	return &pb.PurgeJobResponse{Job: job}, nil
+}
+
+func (s *AdminService) GetActorStats(ctx context.Context, req *pb.GetActorStatsRequest) (*pb.ActorStatsResponse, error) {
+	return &pb.ActorStatsResponse{Stats: s.stateManager.Stats()}, nil
+}
+
+func (s *AdminService) ListActors(ctx context.Context, req *pb.ListActorsRequest) (*pb.ListActorsResponse, error) {
+	actors, err := s.stateManager.ListActors(ctx, req.Container)
+	if nil != err {
+		log.Error("list actors", zap.Error(err))
+		return nil, errors.Wrap(err, "list actors")
+	}
+	return &pb.ListActorsResponse{Total: int64(len(actors)), Items: actors}, nil
+}
+
+func (s *AdminService) GetActor(ctx context.Context, req *pb.ActorRequest) (*pb.ActorResponse, error) {
+	actor, err := s.stateManager.GetActor(ctx, req.Id)
+	if nil != err {
+		log.Error("get actor", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "get actor")
+	}
+	return &pb.ActorResponse{Actor: actor}, nil
+}
+
+func (s *AdminService) FlushActor(ctx context.Context, req *pb.ActorRequest) (*pb.ActorResponse, error) {
+	actor, err := s.stateManager.FlushActor(ctx, req.Id)
+	if nil != err {
+		log.Error("flush actor", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "flush actor")
+	}
+	return &pb.ActorResponse{Actor: actor}, nil
+}
+
+func (s *AdminService) DetachActor(ctx context.Context, req *pb.ActorRequest) (*pb.DetachActorResponse, error) {
+	if err := s.stateManager.DetachActor(ctx, req.Id); nil != err {
+		log.Error("detach actor", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "detach actor")
+	}
+	return &pb.DetachActorResponse{Id: req.Id, Status: "detached"}, nil
+}
+
+func (s *AdminService) ReloadActor(ctx context.Context, req *pb.ActorRequest) (*pb.ActorResponse, error) {
+	actor, err := s.stateManager.ReloadActor(ctx, req.Id)
+	if nil != err {
+		log.Error("reload actor", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "reload actor")
+	}
+	return &pb.ActorResponse{Actor: actor}, nil
+}
+
+func (s *AdminService) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	if nil == s.auditStore {
+		return nil, ErrAuditDisabled
+	}
+
+	query := audit.Query{
+		EntityID: req.EntityId,
+		Actor:    req.Actor,
+		Type:     req.Type,
+		Limit:    int(req.Limit),
+	}
+	if req.StartTime > 0 {
+		query.StartTime = time.Unix(0, req.StartTime*int64(time.Millisecond))
+	}
+	if req.EndTime > 0 {
+		query.EndTime = time.Unix(0, req.EndTime*int64(time.Millisecond))
+	}
+
+	events, err := s.auditStore.Query(ctx, query)
+	if nil != err {
+		log.Error("list audit events", logger.EntityID(req.EntityId), zap.Error(err))
+		return nil, errors.Wrap(err, "list audit events")
+	}
+
+	out := &pb.ListAuditEventsResponse{Total: int64(len(events))}
+	for _, event := range events {
+		item, err := auditEvent2PB(event)
+		if nil != err {
+			return nil, errors.Wrap(err, "list audit events")
+		}
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
+
+func auditEvent2PB(event *audit.Event) (*pb.AuditEvent, error) {
+	item := &pb.AuditEvent{
+		Id:         event.ID,
+		Type:       event.Type,
+		Source:     event.Source,
+		EntityId:   event.Subject,
+		Time:       event.Time.UnixNano() / int64(time.Millisecond),
+		Actor:      event.Data.Actor,
+		Owner:      event.Data.Owner,
+		EntityType: event.Data.EntityType,
+		Version:    event.Data.Version,
+	}
+
+	var err error
+	if item.Before, err = toValue(event.Data.Before); nil != err {
+		return nil, err
+	} else if item.After, err = toValue(event.Data.After); nil != err {
+		return nil, err
+	}
+
+	// changes in json form.
+	changes := make([]interface{}, 0, len(event.Data.Diff))
+	for _, change := range event.Data.Diff {
+		changes = append(changes, map[string]interface{}{
+			"path":   change.Path,
+			"op":     change.Op,
+			"before": change.Before,
+			"after":  change.After,
+		})
+	}
+	item.Diff, err = structpb.NewValue(changes)
+	return item, errors.Wrap(err, "encode audit diff")
+}
+
+func toValue(snapshot map[string]interface{}) (*structpb.Value, error) {
+	if nil == snapshot {
+		return nil, nil
+	}
+	val, err := structpb.NewValue(snapshot)
+	return val, errors.Wrap(err, "encode audit snapshot")
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/alarm"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/entities"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/runtime"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type acknowledger interface {
+	AcknowledgeAlarm(ctx context.Context, id, user string) (map[string]constraint.Node, error)
+}
+
+// AlarmService manages alarms, alarms are entities of type ALARM.
+type AlarmService struct {
+	pb.UnimplementedAlarmServer
+	entityManager entities.EntityManager
+	acknowledger  acknowledger
+	searchClient  pb.SearchHTTPServer
+}
+
+func NewAlarmService(entityManager entities.EntityManager, acknowledger acknowledger, searchClient pb.SearchHTTPServer) *AlarmService {
+	return &AlarmService{
+		entityManager: entityManager,
+		acknowledger:  acknowledger,
+		searchClient:  searchClient,
+	}
+}
+
+func (s *AlarmService) CreateAlarm(ctx context.Context, req *pb.CreateAlarmRequest) (out *pb.AlarmResponse, err error) {
+	if nil == req.Alarm {
+		return out, ErrEntityEmptyRequest
+	}
+
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	entity.Type = runtime.StateMachineTypeAlarm
+	parseHeaderFrom(ctx, entity)
+
+	rule, err := alarm.NewRule(req.Alarm.Condition, req.Alarm.Severity, req.Alarm.Hysteresis, req.Alarm.Duration)
+	if nil != err {
+		log.Error("create alarm", zap.Error(err), logger.EntityID(req.Id))
+		return out, errors.Wrap(err, "create alarm")
+	}
+
+	rule.Description = req.Alarm.Description
+	state := alarm.State{Status: alarm.StatusCleared}
+	entity.KValues = rule.Properties()
+	for key, val := range state.Properties() {
+		entity.KValues[key] = val
+	}
+
+	if entity, err = s.entityManager.CreateEntity(ctx, entity); nil != err {
+		log.Error("create alarm", zap.Error(err), logger.EntityID(req.Id))
+		return out, errors.Wrap(err, "create alarm")
+	}
+
+	// set mapper watching sources of condition, id of entity may be generated.
+	entity.Mappers = []statem.MapperDesc{{
+		Name:      alarm.MapperName,
+		TQLString: rule.Condition.TQL(entity.ID),
+	}}
+
+	if _, err = s.entityManager.AppendMapper(ctx, entity); nil != err {
+		log.Error("create alarm", zap.Error(err), logger.EntityID(entity.ID))
+		if _, err0 := s.entityManager.DeleteEntity(ctx, entity); nil != err0 {
+			log.Error("destroy alarm", zap.Error(err0), logger.EntityID(entity.ID))
+		}
+		return out, errors.Wrap(err, "create alarm")
+	}
+
+	return alarm2pb(entity), nil
+}
+
+func (s *AlarmService) DeleteAlarm(ctx context.Context, req *pb.DeleteAlarmRequest) (out *pb.DeleteAlarmResponse, err error) {
+	var entity *Entity
+	if entity, err = s.getAlarm(ctx, req.Id, req.Owner, req.Source); nil != err {
+		log.Error("delete alarm", zap.Error(err), logger.EntityID(req.Id))
+		return out, err
+	} else if _, err = s.entityManager.DeleteEntity(ctx, entity); nil != err {
+		log.Error("delete alarm", zap.Error(err), logger.EntityID(req.Id))
+		return out, errors.Wrap(err, "delete alarm")
+	}
+
+	return &pb.DeleteAlarmResponse{Id: req.Id, Status: "ok"}, nil
+}
+
+func (s *AlarmService) GetAlarm(ctx context.Context, req *pb.GetAlarmRequest) (out *pb.AlarmResponse, err error) {
+	var entity *Entity
+	if entity, err = s.getAlarm(ctx, req.Id, req.Owner, req.Source); nil != err {
+		log.Error("get alarm", zap.Error(err), logger.EntityID(req.Id))
+		return out, err
+	}
+	return alarm2pb(entity), nil
+}
+
+// ListAlarms lists alarms of owner from search engine.
+func (s *AlarmService) ListAlarms(ctx context.Context, req *pb.ListAlarmsRequest) (out *pb.ListAlarmsResponse, err error) {
+	searchReq := &pb.SearchRequest{
+		Owner:    parseOwnerFrom(ctx, req.Owner),
+		PageNum:  req.PageNum,
+		PageSize: req.PageSize,
+	}
+
+	conditions := map[string]string{
+		"type":              runtime.StateMachineTypeAlarm,
+		alarm.FieldStatus:   req.Status,
+		alarm.FieldSeverity: req.Severity,
+	}
+	for field, value := range conditions {
+		if value != "" {
+			searchReq.Condition = append(searchReq.Condition, &pb.SearchCondition{
+				Field: field, Operator: "$eq", Value: structpb.NewStringValue(value),
+			})
+		}
+	}
+	searchReq.Condition = scopeByOwner(searchReq.Condition, searchReq.Owner)
+
+	var resp *pb.SearchResponse
+	if resp, err = s.searchClient.Search(ctx, searchReq); nil != err {
+		log.Error("list alarms", zap.Error(err))
+		return out, errors.Wrap(err, "list alarms")
+	}
+
+	out = &pb.ListAlarmsResponse{Total: resp.Total, PageNum: resp.PageNum, PageSize: resp.PageSize}
+	for _, item := range resp.Items {
+		kv, ok := item.AsInterface().(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		entity := &Entity{
+			ID:      interface2string(kv["id"]),
+			Owner:   interface2string(kv["owner"]),
+			Source:  interface2string(kv["source"]),
+			KValues: make(map[string]constraint.Node),
+		}
+		for key, val := range kv {
+			entity.KValues[key] = constraint.NewNode(val)
+		}
+		out.Items = append(out.Items, alarm2pb(entity))
+	}
+	return out, nil
+}
+
+// AcknowledgeAlarm acknowledges an active alarm.
+func (s *AlarmService) AcknowledgeAlarm(ctx context.Context, req *pb.AcknowledgeAlarmRequest) (out *pb.AlarmResponse, err error) {
+	var entity *Entity
+	if entity, err = s.getAlarm(ctx, req.Id, req.Owner, req.Source); nil != err {
+		log.Error("acknowledge alarm", zap.Error(err), logger.EntityID(req.Id))
+		return out, err
+	}
+
+	user := req.User
+	if user == "" {
+		user = entity.Owner
+	}
+
+	// checked and acknowledged by the alarm state machine, state store lags behind it.
+	properties, err := s.acknowledger.AcknowledgeAlarm(ctx, entity.ID, user)
+	if nil != err {
+		log.Error("acknowledge alarm", zap.Error(err), logger.EntityID(req.Id))
+		return out, errors.Wrap(err, "acknowledge alarm")
+	}
+
+	for key, val := range properties {
+		entity.KValues[key] = val
+	}
+	return alarm2pb(entity), nil
+}
+
+// getAlarm returns alarm owned by owner.
+func (s *AlarmService) getAlarm(ctx context.Context, id, owner, source string) (*Entity, error) {
+	var entity = &Entity{ID: id, Owner: owner, Source: source, Type: runtime.StateMachineTypeAlarm}
+	parseHeaderFrom(ctx, entity)
+
+	base, err := s.entityManager.GetProperties(ctx, entity)
+	if nil != err {
+		return nil, errors.Wrap(err, "get alarm")
+	} else if err = ownedBy(entity.Owner, base); nil != err {
+		return nil, err
+	} else if base.Type != runtime.StateMachineTypeAlarm {
+		return nil, errors.Wrapf(ErrAlarmNotFound, "entity %s is %s", id, base.Type)
+	}
+	return base, nil
+}
+
+func alarm2pb(entity *Entity) *pb.AlarmResponse {
+	state := alarm.DecodeState(entity.KValues)
+	out := &pb.AlarmResponse{
+		Id:             entity.ID,
+		Source:         entity.Source,
+		Owner:          entity.Owner,
+		Status:         state.Status,
+		Value:          state.Value,
+		RaisedAt:       state.RaisedAt,
+		AcknowledgedAt: state.AcknowledgedAt,
+		AcknowledgedBy: state.AcknowledgedBy,
+		ClearedAt:      state.ClearedAt,
+		RaiseCount:     state.RaiseCount,
+	}
+
+	rule, err := alarm.DecodeRule(entity.KValues)
+	if nil != err {
+		log.Warn("decode alarm rule", logger.EntityID(entity.ID), zap.Error(err))
+		return out
+	}
+
+	var duration string
+	if rule.Duration > 0 {
+		duration = rule.Duration.String()
+	}
+	out.Alarm = &pb.AlarmObject{
+		Condition:   rule.Condition.String(),
+		Severity:    rule.Severity,
+		Hysteresis:  rule.Hysteresis,
+		Duration:    duration,
+		Description: rule.Description,
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/alarm"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/entities"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/service/mock"
+	"github.com/tkeel-io/core/pkg/statem"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// alarmManager stores entities in memory and indexes them into search.
+type alarmManager struct {
+	mock.EntityManagerMock
+	entities     map[string]*statem.Base
+	searchClient pb.SearchHTTPServer
+}
+
+func (m *alarmManager) CreateEntity(ctx context.Context, en *statem.Base) (*statem.Base, error) {
+	m.entities[en.ID] = en
+	val, err := structpb.NewValue(en.SearchValues())
+	if nil != err {
+		return nil, err
+	}
+	_, err = m.searchClient.Index(ctx, &pb.IndexObject{Obj: val})
+	return en, err
+}
+
+func (m *alarmManager) GetProperties(ctx context.Context, en *statem.Base) (*statem.Base, error) {
+	if base, has := m.entities[en.ID]; has {
+		return base, nil
+	}
+	return nil, entities.ErrEntityNotFound
+}
+
+// alarmActors holds alarm states of state machines, ahead of state store.
+type alarmActors map[string]*alarm.State
+
+func (a alarmActors) AcknowledgeAlarm(ctx context.Context, id, user string) (map[string]constraint.Node, error) {
+	state, has := a[id]
+	if !has {
+		return nil, entities.ErrEntityNotFound
+	} else if err := state.Acknowledge(user, 1); nil != err {
+		return nil, err
+	}
+	return state.Properties(), nil
+}
+
+func Test_AlarmService(t *testing.T) {
+	searchClient := fake.NewSearch(t)
+	manager := &alarmManager{entities: map[string]*statem.Base{}, searchClient: searchClient}
+	manager.entities["device1"] = &statem.Base{ID: "device1", Type: "DEVICE", Owner: "admin"}
+	actors := alarmActors{"alarm1": {Status: alarm.StatusCleared}}
+	service := NewAlarmService(manager, actors, searchClient)
+	ctx := context.Background()
+
+	_, err := service.CreateAlarm(ctx, &pb.CreateAlarmRequest{Id: "alarm0", Owner: "admin", Alarm: &pb.AlarmObject{Condition: "temp > 80"}})
+	assert.ErrorIs(t, err, alarm.ErrConditionInvalid)
+
+	for _, req := range []*pb.CreateAlarmRequest{
+		{Id: "alarm1", Owner: "admin", Alarm: &pb.AlarmObject{Condition: "device1.temp > 80", Severity: alarm.SeverityCritical, Hysteresis: 5, Duration: "5m"}},
+		{Id: "alarm2", Owner: "admin", Alarm: &pb.AlarmObject{Condition: "device1.humidity < 20"}},
+	} {
+		out, err := service.CreateAlarm(ctx, req)
+		assert.Nil(t, err)
+		assert.Equal(t, alarm.StatusCleared, out.Status)
+	}
+	assert.Equal(t, []statem.MapperDesc{{Name: alarm.MapperName, TQLString: "insert into alarm1 select device1.temp as value"}}, manager.entities["alarm1"].Mappers)
+
+	out, err := service.GetAlarm(ctx, &pb.GetAlarmRequest{Id: "alarm1", Owner: "admin"})
+	assert.Nil(t, err)
+	assert.Equal(t, &pb.AlarmObject{Condition: "device1.temp > 80", Severity: alarm.SeverityCritical, Hysteresis: 5, Duration: "5m0s"}, out.Alarm)
+	_, err = service.GetAlarm(ctx, &pb.GetAlarmRequest{Id: "device1", Owner: "admin"})
+	assert.ErrorIs(t, err, ErrAlarmNotFound)
+	_, err = service.GetAlarm(ctx, &pb.GetAlarmRequest{Id: "alarm1", Owner: "other"})
+	assert.ErrorIs(t, err, ErrEntityForbidden)
+
+	// only active alarm is acknowledged.
+	_, err = service.AcknowledgeAlarm(ctx, &pb.AcknowledgeAlarmRequest{Id: "alarm1", Owner: "admin"})
+	assert.ErrorIs(t, err, alarm.ErrAlarmNotActive)
+	// status held by state machine is checked, state store lags behind it.
+	actors["alarm1"].Status = alarm.StatusActive
+	out, err = service.AcknowledgeAlarm(ctx, &pb.AcknowledgeAlarmRequest{Id: "alarm1", Owner: "admin", User: "operator"})
+	assert.Nil(t, err)
+	assert.Equal(t, alarm.StatusAcknowledged, out.Status)
+	assert.Equal(t, "operator", out.AcknowledgedBy)
+	assert.Equal(t, alarm.StatusAcknowledged, actors["alarm1"].Status)
+
+	// list alarms of owner by severity.
+	list, err := service.ListAlarms(ctx, &pb.ListAlarmsRequest{Owner: "admin", Severity: alarm.SeverityWarning})
+	assert.Nil(t, err)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, "alarm2", list.Items[0].Id)
+	assert.Equal(t, "device1.humidity < 20", list.Items[0].Alarm.Condition)
+	list, err = service.ListAlarms(ctx, &pb.ListAlarmsRequest{Owner: "admin"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), list.Total)
+	list, err = service.ListAlarms(ctx, &pb.ListAlarmsRequest{Owner: "other"})
+	assert.Nil(t, err)
+	assert.Empty(t, list.Items)
+
+	_, err = service.DeleteAlarm(ctx, &pb.DeleteAlarmRequest{Id: "device1", Owner: "admin"})
+	assert.ErrorIs(t, err, ErrAlarmNotFound)
+	_, err = service.DeleteAlarm(ctx, &pb.DeleteAlarmRequest{Id: "alarm2", Owner: "admin"})
+	assert.Nil(t, err)
+}
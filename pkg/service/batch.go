@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxBatchItems limits items of a batch request.
+	MaxBatchItems = 5000
+	// batchConcurrency is number of items handled concurrently.
+	batchConcurrency = 16
+)
+
+// status of batch items.
+const (
+	BatchItemStatusSucceeded = "SUCCEEDED"
+	BatchItemStatusFailed    = "FAILED"
+	BatchItemStatusSkipped   = "SKIPPED"
+)
+
+func (s *EntityService) BatchCreateEntities(ctx context.Context, req *pb.BatchCreateEntitiesRequest) (*pb.BatchEntitiesResponse, error) {
+	ids := make([]string, len(req.Entities))
+	for index, item := range req.Entities {
+		ids[index] = item.Id
+	}
+
+	return runBatch(ctx, ids, req.FailFast, func(ctx context.Context, index int, result *pb.BatchItemResult) error {
+		entity, err := s.CreateEntity(ctx, req.Entities[index])
+		if nil == err {
+			result.Id, result.Entity = entity.Id, entity
+		}
+		return err
+	})
+}
+
+func (s *EntityService) BatchUpdateProperties(ctx context.Context, req *pb.BatchUpdatePropertiesRequest) (*pb.BatchEntitiesResponse, error) {
+	ids := make([]string, len(req.Entities))
+	for index, item := range req.Entities {
+		ids[index] = item.Id
+	}
+
+	return runBatch(ctx, ids, req.FailFast, func(ctx context.Context, index int, result *pb.BatchItemResult) error {
+		entity, err := s.UpdateEntity(ctx, req.Entities[index])
+		if nil == err {
+			result.Entity = entity
+		}
+		return err
+	})
+}
+
+func (s *EntityService) BatchDeleteEntities(ctx context.Context, req *pb.BatchDeleteEntitiesRequest) (*pb.BatchEntitiesResponse, error) {
+	ids := make([]string, len(req.Entities))
+	for index, item := range req.Entities {
+		ids[index] = item.Id
+	}
+
+	return runBatch(ctx, ids, req.FailFast, func(ctx context.Context, index int, result *pb.BatchItemResult) error {
+		out, err := s.DeleteEntity(ctx, req.Entities[index])
+		if nil == err {
+			result.PurgeTime = out.PurgeTime
+		}
+		return err
+	})
+}
+
+// runBatch handles items concurrently and returns results in the order of items.
+// if failFast, no item is started after the first failure, the rest are skipped.
+func runBatch(ctx context.Context, ids []string, failFast bool, handle func(context.Context, int, *pb.BatchItemResult) error) (*pb.BatchEntitiesResponse, error) {
+	if len(ids) == 0 {
+		return nil, ErrEntityEmptyRequest
+	} else if len(ids) > MaxBatchItems {
+		return nil, errors.Wrapf(ErrBatchTooLarge, "%d items, at most %d", len(ids), MaxBatchItems)
+	}
+
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, has := seen[id]; has && id != "" {
+			return nil, errors.Wrap(ErrBatchDuplicateID, id)
+		}
+		seen[id] = struct{}{}
+	}
+
+	results := make([]*pb.BatchItemResult, len(ids))
+	for index, id := range ids {
+		results[index] = &pb.BatchItemResult{Id: id, Status: BatchItemStatusSkipped}
+	}
+
+	var (
+		failed  int32
+		wg      sync.WaitGroup
+		indexes = make(chan int)
+	)
+	for n := 0; n < batchConcurrency && n < len(ids); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				result := results[index]
+				if err := handle(ctx, index, result); nil != err {
+					log.Warn("handle batch item", logger.EntityID(result.Id), zap.Error(err))
+					result.Status, result.Error = BatchItemStatusFailed, err.Error()
+					atomic.StoreInt32(&failed, 1)
+					continue
+				}
+				result.Status = BatchItemStatusSucceeded
+			}
+		}()
+	}
+
+	for index := range ids {
+		if nil != ctx.Err() || (failFast && atomic.LoadInt32(&failed) == 1) {
+			break
+		}
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	out := &pb.BatchEntitiesResponse{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case BatchItemStatusSucceeded:
+			out.Succeeded++
+		case BatchItemStatusFailed:
+			out.Failed++
+		default:
+			out.Skipped++
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/service/mock"
+	"github.com/tkeel-io/core/pkg/statem"
+)
+
+var errCreate = errors.New("create failed")
+
+// batchManager fails to create entities whose id prefixed with "bad".
+type batchManager struct {
+	mock.EntityManagerMock
+}
+
+func (m *batchManager) CreateEntity(ctx context.Context, en *statem.Base) (*statem.Base, error) {
+	if len(en.ID) >= 3 && en.ID[:3] == "bad" {
+		return nil, errCreate
+	}
+	return en, nil
+}
+
+func TestEntityService_BatchCreateEntities(t *testing.T) {
+	srv, err := NewEntityService(context.Background(), &batchManager{}, mock.NewSearchMock())
+	assert.Nil(t, err)
+
+	req := &pb.BatchCreateEntitiesRequest{Entities: []*pb.CreateEntityRequest{
+		{Id: "device1", Owner: "admin", Type: "DEVICE"},
+		{Id: "bad1", Owner: "admin", Type: "DEVICE"},
+		{Id: "device2", Owner: "admin", Type: "DEVICE"},
+	}}
+	out, err := srv.BatchCreateEntities(context.Background(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), out.Succeeded)
+	assert.Equal(t, int32(1), out.Failed)
+	assert.Equal(t, BatchItemStatusSucceeded, out.Results[0].Status)
+	assert.Equal(t, "device1", out.Results[0].Entity.Id)
+	assert.Equal(t, BatchItemStatusFailed, out.Results[1].Status)
+	assert.Contains(t, out.Results[1].Error, errCreate.Error())
+	assert.Equal(t, "device2", out.Results[2].Id)
+
+	req.Entities = append(req.Entities, &pb.CreateEntityRequest{Id: "device1"})
+	_, err = srv.BatchCreateEntities(context.Background(), req)
+	assert.ErrorIs(t, err, ErrBatchDuplicateID)
+
+	_, err = srv.BatchCreateEntities(context.Background(), &pb.BatchCreateEntitiesRequest{})
+	assert.ErrorIs(t, err, ErrEntityEmptyRequest)
+}
+
+func Test_runBatch(t *testing.T) {
+	ids := make([]string, 1000)
+	for index := range ids {
+		ids[index] = fmt.Sprintf("device%d", index)
+	}
+
+	handle := func(ctx context.Context, index int, result *pb.BatchItemResult) error {
+		if index == 0 {
+			return errCreate
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+
+	// best effort.
+	out, err := runBatch(context.Background(), ids, false, handle)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(999), out.Succeeded)
+	assert.Equal(t, int32(1), out.Failed)
+	assert.Equal(t, int32(0), out.Skipped)
+
+	// fail fast, items in flight are completed.
+	out, err = runBatch(context.Background(), ids, true, handle)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), out.Failed)
+	assert.True(t, out.Skipped > 900)
+	assert.Equal(t, BatchItemStatusSkipped, out.Results[999].Status)
+
+	_, err = runBatch(context.Background(), make([]string, MaxBatchItems+1), false, handle)
+	assert.ErrorIs(t, err, ErrBatchTooLarge)
+}
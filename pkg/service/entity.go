@@ -24,10 +24,12 @@ import (
 
 	"github.com/pkg/errors"
 	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/config"
 	"github.com/tkeel-io/core/pkg/constraint"
 	"github.com/tkeel-io/core/pkg/entities"
 	"github.com/tkeel-io/core/pkg/logger"
 	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/util"
 	"github.com/tkeel-io/kit/log"
 	"go.uber.org/zap"
 
@@ -105,6 +107,10 @@ func (s *EntityService) UpdateEntity(ctx context.Context, req *pb.UpdateEntityRe
 	entity.Source = req.Source
 
 	parseHeaderFrom(ctx, entity)
+	if err = s.checkOwner(ctx, entity); nil != err {
+		return nil, err
+	}
+
 	entity.KValues = make(map[string]constraint.Node)
 	switch kv := req.Properties.AsInterface().(type) {
 	case map[string]interface{}:
@@ -142,6 +148,10 @@ func (s *EntityService) PatchEntity(ctx context.Context, req *pb.PatchEntityRequ
 	entity.Owner = req.Owner
 	entity.Source = req.Source
 	parseHeaderFrom(ctx, entity)
+	if err = s.checkOwner(ctx, entity); nil != err {
+		return nil, err
+	}
+
 	entity.KValues = make(map[string]constraint.Node)
 
 	switch kv := req.Properties.AsInterface().(type) {
@@ -197,16 +207,37 @@ func (s *EntityService) DeleteEntity(ctx context.Context, req *pb.DeleteEntityRe
 	entity.Owner = req.Owner
 	entity.Source = req.Source
 	parseHeaderFrom(ctx, entity)
+	if err = s.checkOwner(ctx, entity); nil != err {
+		return nil, err
+	}
 
-	// delete entity.
-	_, err = s.entityManager.DeleteEntity(ctx, entity)
-	if nil != err {
+	// soft-delete entity, purged after retention.
+	var base *Entity
+	if base, err = s.entityManager.DeleteEntity(ctx, entity); nil != err {
 		return
 	}
 
 	out = &pb.DeleteEntityResponse{}
 	out.Id = req.Id
 	out.Status = "ok"
+	out.PurgeTime = base.DeletedAt + config.Get().Deletion.Retention.Milliseconds()
+	return
+}
+
+func (s *EntityService) RestoreEntity(ctx context.Context, req *pb.RestoreEntityRequest) (out *pb.EntityResponse, err error) {
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Type = req.Type
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	parseHeaderFrom(ctx, entity)
+
+	if entity, err = s.entityManager.RestoreEntity(ctx, entity); nil != err {
+		log.Error("restore entity", logger.EntityID(req.Id), zap.Error(err))
+		return out, errors.Wrap(err, "restore entity")
+	}
+
+	out = s.entity2EntityResponse(entity)
 	return
 }
 
@@ -225,9 +256,13 @@ func (s *EntityService) GetEntityProps(ctx context.Context, in *pb.GetEntityProp
 	}
 
 	// get entity from entity manager.
+	owner := entity.Owner
 	if entity, err = s.entityManager.GetProperties(ctx, entity); nil != err {
 		log.Error("patch entity failed.", logger.EntityID(in.Id), zap.Error(err))
 		return
+	} else if err = ownedBy(owner, entity); nil != err {
+		log.Error("get entity properties", logger.EntityID(in.Id), zap.Error(err))
+		return out, err
 	}
 
 	props := make(map[string]constraint.Node)
@@ -255,9 +290,62 @@ func (s *EntityService) GetEntityProps(ctx context.Context, in *pb.GetEntityProp
 
 	entity.KValues = props
 	out = s.entity2EntityResponse(entity)
+	out.Metas = metas2pb(entity, pids, util.UnixMilli())
+	if in.History > 0 {
+		out.History = history2pb(entity, pids, int(in.History))
+	}
 	return out, errors.Wrap(err, "patch entity properties")
 }
 
+// metas2pb returns timestamps and quality of properties at now, keyed by property id.
+func metas2pb(entity *Entity, pids []string, now int64) *structpb.Value {
+	metas := make(map[string]interface{})
+	for _, pid := range pids {
+		pid = strings.TrimSpace(pid)
+		if meta, has := entity.GetPropertyMeta(pid, now); has {
+			metas[pid] = map[string]interface{}{
+				"source_time":  meta.SourceTime,
+				"receive_time": meta.ReceiveTime,
+				"quality":      meta.Quality,
+			}
+		}
+	}
+
+	out, err := structpb.NewValue(metas)
+	if nil != err {
+		log.Error("convert entity metas", logger.EntityID(entity.ID), zap.Error(err))
+	}
+	return out
+}
+
+// history2pb returns last values of properties, keyed by root property id.
+func history2pb(entity *Entity, pids []string, n int) *structpb.Value {
+	history := make(map[string]interface{})
+	for _, pid := range pids {
+		pid = strings.TrimSpace(pid)
+		if index := strings.IndexAny(pid, ".["); index > 0 {
+			pid = pid[:index]
+		}
+
+		values := entity.GetHistory(pid, n)
+		if len(values) == 0 {
+			continue
+		}
+
+		items := make([]interface{}, 0, len(values))
+		for _, val := range values {
+			items = append(items, map[string]interface{}{"value": constraint.NewNode(val.Value).Value(), "ts": val.Timestamp})
+		}
+		history[pid] = items
+	}
+
+	out, err := structpb.NewValue(history)
+	if nil != err {
+		log.Error("convert entity history", logger.EntityID(entity.ID), zap.Error(err))
+	}
+	return out
+}
+
 func (s *EntityService) GetEntity(ctx context.Context, req *pb.GetEntityRequest) (out *pb.EntityResponse, err error) {
 	var entity = new(Entity)
 	entity.ID = req.Id
@@ -267,9 +355,13 @@ func (s *EntityService) GetEntity(ctx context.Context, req *pb.GetEntityRequest)
 	parseHeaderFrom(ctx, entity)
 
 	// get entity from entity manager.
+	owner := entity.Owner
 	if entity, err = s.entityManager.GetProperties(ctx, entity); nil != err {
 		log.Error("get entity failed.", logger.EntityID(req.Id), zap.Error(err))
 		return out, errors.Wrap(err, "get entity failed")
+	} else if err = ownedBy(owner, entity); nil != err {
+		log.Error("get entity failed.", logger.EntityID(req.Id), zap.Error(err))
+		return out, err
 	}
 
 	out = s.entity2EntityResponse(entity)
@@ -278,12 +370,15 @@ func (s *EntityService) GetEntity(ctx context.Context, req *pb.GetEntityRequest)
 
 func (s *EntityService) ListEntity(ctx context.Context, req *pb.ListEntityRequest) (out *pb.ListEntityResponse, err error) {
 	searchReq := &pb.SearchRequest{}
+	searchReq.Owner = parseOwnerFrom(ctx, req.Owner)
 	searchReq.Query = req.Query
 	searchReq.PageNum = req.PageNum
 	searchReq.PageSize = req.PageSize
 	searchReq.OrderBy = req.OrderBy
 	searchReq.IsDescending = req.IsDescending
-	searchReq.Condition = req.Condition
+	searchReq.Condition = scopeByOwner(req.Condition, searchReq.Owner)
+	searchReq.Sort = req.Sort
+	searchReq.Aggregations = req.Aggregations
 
 	var resp *pb.SearchResponse
 	if resp, err = s.searchClient.Search(ctx, searchReq); err != nil {
@@ -295,6 +390,7 @@ func (s *EntityService) ListEntity(ctx context.Context, req *pb.ListEntityReques
 	out.Total = resp.Total
 	out.PageNum = resp.PageNum
 	out.PageSize = resp.PageSize
+	out.Aggregations = resp.Aggregations
 	for _, item := range resp.Items {
 		switch kv := item.AsInterface().(type) {
 		case map[string]interface{}:
@@ -309,6 +405,8 @@ func (s *EntityService) ListEntity(ctx context.Context, req *pb.ListEntityReques
 			delete(kv, "type")
 			delete(kv, "version")
 			delete(kv, "last_time")
+			delete(kv, statem.SearchFieldRelated)
+			delete(kv, statem.SearchFieldRelationships)
 			properties, _ := structpb.NewValue(kv)
 			entityItem := &pb.EntityResponse{
 				Id:         id,
@@ -336,6 +434,10 @@ func (s *EntityService) AppendMapper(ctx context.Context, req *pb.AppendMapperRe
 	entity.Source = req.Source
 
 	parseHeaderFrom(ctx, entity)
+	if err = s.checkOwner(ctx, entity); nil != err {
+		return nil, err
+	}
+
 	mapperDesc := statem.MapperDesc{}
 	if req.Mapper != nil {
 		mapperDesc.Name = req.Mapper.Name
@@ -361,6 +463,9 @@ func (s *EntityService) RemoveMapper(ctx context.Context, req *pb.RemoveMapperRe
 	entity.Owner = req.Owner
 	entity.Source = req.Source
 	parseHeaderFrom(ctx, entity)
+	if err = s.checkOwner(ctx, entity); nil != err {
+		return nil, err
+	}
 
 	entity.Mappers = []statem.MapperDesc{{Name: req.MapperName}}
 	if entity, err = s.entityManager.RemoveMapper(ctx, entity); nil != err {
@@ -380,6 +485,9 @@ func (s *EntityService) SetConfigs(ctx context.Context, in *pb.SetConfigsRequest
 	entity.Owner = in.Owner
 	entity.Source = in.Source
 	parseHeaderFrom(ctx, entity)
+	if err = s.checkOwner(ctx, entity); nil != err {
+		return nil, err
+	}
 
 	if entity.Configs, err = parseConfigFrom(ctx, in.Configs.AsInterface()); nil != err {
 		log.Error("set entity configs", logger.EntityID(in.Id), zap.Error(err))
@@ -403,6 +511,9 @@ func (s *EntityService) AppendConfigs(ctx context.Context, in *pb.AppendConfigsR
 	entity.Owner = in.Owner
 	entity.Source = in.Source
 	parseHeaderFrom(ctx, entity)
+	if err = s.checkOwner(ctx, entity); nil != err {
+		return nil, err
+	}
 
 	if entity.Configs, err = parseConfigFrom(ctx, in.Configs.AsInterface()); nil != err {
 		log.Error("append entity configs", logger.EntityID(in.Id), zap.Error(err))
@@ -426,6 +537,9 @@ func (s *EntityService) RemoveConfigs(ctx context.Context, in *pb.RemoveConfigsR
 	entity.Owner = in.Owner
 	entity.Source = in.Source
 	parseHeaderFrom(ctx, entity)
+	if err = s.checkOwner(ctx, entity); nil != err {
+		return nil, err
+	}
 
 	// set properties.
 	propertyIDs := strings.Split(in.PropertyIds, ",")
@@ -448,8 +562,13 @@ func (s *EntityService) QueryConfigs(ctx context.Context, in *pb.QueryConfigsReq
 
 	// set properties.
 	propertyIDs := strings.Split(in.PropertyIds, ",")
+	owner := entity.Owner
 	if entity, err = s.entityManager.QueryConfigs(ctx, entity, propertyIDs); nil != err {
 		log.Error("query entity configs", logger.EntityID(in.Id), zap.Error(err))
+		return out, errors.Wrap(err, "query entity configs")
+	} else if err = ownedBy(owner, entity); nil != err {
+		log.Error("query entity configs", logger.EntityID(in.Id), zap.Error(err))
+		return out, err
 	}
 
 	configs := make(map[string]constraint.Config)
@@ -468,6 +587,10 @@ func (s *EntityService) PatchConfigs(ctx context.Context, in *pb.PatchConfigsReq
 	entity.Owner = in.Owner
 	entity.Source = in.Source
 	parseHeaderFrom(ctx, entity)
+	if err = s.checkOwner(ctx, entity); nil != err {
+		return nil, err
+	}
+
 	entity.KValues = make(map[string]constraint.Node)
 
 	switch kv := in.Configs.AsInterface().(type) {
@@ -542,8 +665,9 @@ func parseHeaderFrom(ctx context.Context, en *statem.Base) {
 			if en.Type == "" {
 				en.Type = h.Get(HeaderType)
 			}
-			if en.Owner == "" {
-				en.Owner = h.Get(HeaderOwner)
+			// owner in header is the tenant of caller.
+			if owner := h.Get(HeaderOwner); owner != "" {
+				en.Owner = owner
 			}
 			if en.Source == "" {
 				en.Source = h.Get(HeaderSource)
@@ -586,6 +710,7 @@ func (s *EntityService) entity2EntityResponse(entity *Entity) (out *pb.EntityRes
 	out.Type = entity.Type
 	out.Owner = entity.Owner
 	out.Source = entity.Source
+	out.Relationships = relationships2pb(entity.ID, entity.Relationships)
 
 	return out
 }
@@ -106,6 +106,17 @@ func Test_DeleteEntity(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func Test_RestoreEntity(t *testing.T) {
+	out, err := entityService.RestoreEntity(context.Background(), &pb.RestoreEntityRequest{
+		Id:     "device123",
+		Owner:  "admin",
+		Type:   "DEVICE",
+		Source: "dm",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "device123", out.Id)
+}
+
 func Test_GetEntityProps(t *testing.T) {
 	_, err := entityService.GetEntityProps(context.Background(), &pb.GetEntityPropsRequest{
 		Id:     "device123",
@@ -117,6 +128,40 @@ func Test_GetEntityProps(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func Test_history2pb(t *testing.T) {
+	entity := &Entity{
+		ID: "device123",
+		History: map[string]*statem.HistoryRing{
+			"temp": {Values: []statem.HistoryValue{{Value: int8(20), Timestamp: 1}, {Value: 21.5, Timestamp: 2}, {Value: float64(22), Timestamp: 3}}},
+		},
+	}
+
+	out := history2pb(entity, []string{"temp.value", "humidity"}, 2)
+	assert.Equal(t, map[string]interface{}{
+		"temp": []interface{}{
+			map[string]interface{}{"value": 21.5, "ts": float64(2)},
+			map[string]interface{}{"value": float64(22), "ts": float64(3)},
+		},
+	}, out.AsInterface())
+}
+
+func Test_metas2pb(t *testing.T) {
+	entity := &Entity{
+		ID:      "device123",
+		Configs: map[string]constraint.Config{"temp": {ID: "temp", Define: map[string]interface{}{constraint.DefineFieldUpdateInterval: float64(10)}}},
+		Metas: map[string]statem.PropertyMeta{
+			"temp":    {SourceTime: 1000, ReceiveTime: 2000, Quality: statem.QualityGood},
+			"metrics": {SourceTime: 1000, ReceiveTime: 2000, Quality: statem.QualityBad},
+		},
+	}
+
+	out := metas2pb(entity, []string{"temp", "metrics.cpu", "humidity"}, 20000)
+	assert.Equal(t, map[string]interface{}{
+		"temp":        map[string]interface{}{"source_time": float64(1000), "receive_time": float64(2000), "quality": statem.QualityStale},
+		"metrics.cpu": map[string]interface{}{"source_time": float64(1000), "receive_time": float64(2000), "quality": statem.QualityBad},
+	}, out.AsInterface())
+}
+
 func Test_GetEntity(t *testing.T) {
 	_, err := entityService.GetEntity(context.Background(), &pb.GetEntityRequest{
 		Id:     "device123",
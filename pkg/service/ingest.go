@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/entities"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/tenant"
+	"github.com/tkeel-io/core/pkg/tracing"
+	"github.com/tkeel-io/kit/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+// HeaderIngestWindow is the response header of ingest streams, the initial credits of a client.
+const HeaderIngestWindow = "x-ingest-window"
+
+// IngestService receives batched property updates over gRPC streams,
+// without the routing of Dapr sidecar.
+type IngestService struct {
+	pb.UnimplementedIngestServer
+	entityManager entities.EntityManager
+	lock          sync.RWMutex
+	window        int
+	maxBatchSize  int
+}
+
+func NewIngestService(entityManager entities.EntityManager, window, maxBatchSize int) *IngestService {
+	s := &IngestService{entityManager: entityManager}
+	s.Reload(window, maxBatchSize)
+	return s
+}
+
+// Reload changes window and max batch size, the window of opened streams is not changed.
+func (s *IngestService) Reload(window, maxBatchSize int) {
+	if window <= 0 {
+		window = 1
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.window = window
+	s.maxBatchSize = maxBatchSize
+}
+
+func (s *IngestService) limits() (window, maxBatchSize int) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.window, s.maxBatchSize
+}
+
+// Stream handles batches in order and acks every batch, at most window batches are buffered,
+// receiving is blocked if client sends more batches than its credits.
+func (s *IngestService) Stream(stream pb.Ingest_StreamServer) error {
+	ctx := stream.Context()
+	window, _ := s.limits()
+	if err := stream.SendHeader(metadata.Pairs(HeaderIngestWindow, strconv.Itoa(window))); nil != err {
+		return errors.Wrap(err, "send ingest header")
+	}
+
+	errCh := make(chan error, 1)
+	batches := make(chan *pb.IngestBatch, window-1)
+	go func() {
+		defer close(batches)
+		for {
+			batch, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			} else if nil != err {
+				errCh <- err
+				return
+			}
+
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for batch := range batches {
+		ack := s.handleBatch(ctx, batch)
+		ack.Credits = int32(window - len(batches))
+		if err := stream.Send(ack); nil != err {
+			return errors.Wrap(err, "send ingest ack")
+		}
+	}
+
+	select {
+	case err := <-errCh:
+		return errors.Wrap(err, "receive ingest batch")
+	default:
+		return nil
+	}
+}
+
+// handleBatch sends updates of batch to entities in order. if status of ack is RETRY,
+// updates after the first `accepted + dropped` should be sent again.
+func (s *IngestService) handleBatch(ctx context.Context, batch *pb.IngestBatch) *pb.IngestAck {
+	ack := &pb.IngestAck{Seq: batch.Seq, Status: SubscriptionResponseStatusSuccess}
+	if _, maxBatchSize := s.limits(); maxBatchSize > 0 && len(batch.Updates) > maxBatchSize {
+		log.Warn("drop ingest batch", zap.String("channel", batch.Channel),
+			zap.Int64("seq", batch.Seq), zap.Int("size", len(batch.Updates)))
+		ack.Status = SubscriptionResponseStatusDrop
+		ack.Dropped = int32(len(batch.Updates))
+		ack.Message = fmt.Sprintf("batch size %d exceeds %d", len(batch.Updates), maxBatchSize)
+		return ack
+	}
+
+	ctx, span := tracing.Start(ctx, "ingest.Stream",
+		attribute.String("channel.id", batch.Channel),
+		attribute.Int64("batch.seq", batch.Seq),
+		attribute.Int("batch.size", len(batch.Updates)))
+	defer span.End()
+
+	for _, update := range batch.Updates {
+		if update.Id == "" || len(update.Properties.GetFields()) == 0 ||
+			(update.Quality != "" && !statem.ValidQuality(update.Quality)) {
+			ack.Dropped++
+			continue
+		}
+
+		properties := make(map[string]constraint.Node, len(update.Properties.GetFields()))
+		for key, val := range update.Properties.AsMap() {
+			properties[key] = constraint.NewNode(val)
+		}
+
+		msgCtx := statem.MessageContext{
+			Headers: statem.Header{},
+			Message: statem.PropertyMessage{
+				StateID:    update.Id,
+				Operator:   constraint.PatchOpReplace.String(),
+				Properties: properties,
+				Timestamp:  update.Timestamp,
+				Quality:    update.Quality,
+			},
+		}
+
+		msgCtx.Headers.SetTargetID(update.Id)
+		msgCtx.Headers.SetOwner(update.Owner)
+		msgCtx.Headers.SetSource(update.Source)
+		msgCtx.Headers.Set(statem.MessageCtxHeaderType, update.Type)
+		msgCtx.Headers.Set(statem.MessageCtxHeaderChannelID, batch.Channel)
+		tracing.Inject(ctx, msgCtx.Headers)
+
+		if err := s.entityManager.OnMessage(ctx, msgCtx); nil != err {
+			if errors.Is(err, tenant.ErrMessageRateLimited) {
+				ack.Dropped++
+				continue
+			}
+			// e.g. shutting down, the rest of batch to be resent.
+			log.Warn("handle ingest batch", zap.String("channel", batch.Channel),
+				zap.Int64("seq", batch.Seq), zap.Error(err))
+			ack.Status = SubscriptionResponseStatusRetry
+			ack.Message = err.Error()
+			return ack
+		}
+		ack.Accepted++
+	}
+	return ack
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/runtime"
+	"github.com/tkeel-io/core/pkg/service/mock"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ingestManager records messages, fails messages of entities in errs.
+type ingestManager struct {
+	mock.EntityManagerMock
+	lock     sync.Mutex
+	messages []statem.MessageContext
+	errs     map[string]error
+}
+
+func (m *ingestManager) OnMessage(ctx context.Context, msgCtx statem.MessageContext) error {
+	if err := m.errs[msgCtx.Headers.GetTargetID()]; nil != err {
+		return err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.messages = append(m.messages, msgCtx)
+	return nil
+}
+
+func TestIngestService_Stream(t *testing.T) {
+	manager := &ingestManager{errs: map[string]error{
+		"limited": tenant.ErrMessageRateLimited,
+		"closing": runtime.ErrManagerShutdown,
+	}}
+
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterIngestServer(srv, NewIngestService(manager, 4, 3))
+	go srv.Serve(listener)
+	defer srv.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return listener.Dial() }))
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	stream, err := pb.NewIngestClient(conn).Stream(context.Background())
+	assert.Nil(t, err)
+	header, err := stream.Header()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"4"}, header.Get(HeaderIngestWindow))
+
+	props, err := structpb.NewStruct(map[string]interface{}{"temp": 20})
+	assert.Nil(t, err)
+	update := func(id string) *pb.PropertyUpdate {
+		return &pb.PropertyUpdate{Id: id, Owner: "admin", Type: "DEVICE", Properties: props}
+	}
+
+	batches := []*pb.IngestBatch{
+		{Channel: "channel1", Seq: 1, Updates: []*pb.PropertyUpdate{
+			{Id: "device1", Owner: "admin", Properties: props, Timestamp: 1000, Quality: statem.QualityBad}, update("limited"), {Id: "empty"},
+		}},
+		{Channel: "channel1", Seq: 2, Updates: []*pb.PropertyUpdate{update("device1"), update("device2"), update("device3"), update("device4")}},
+		{Channel: "channel2", Seq: 3, Updates: []*pb.PropertyUpdate{update("device2"), {Id: "stale", Properties: props, Quality: statem.QualityStale}, update("closing")}},
+	}
+	for _, batch := range batches {
+		assert.Nil(t, stream.Send(batch))
+	}
+	assert.Nil(t, stream.CloseSend())
+
+	var acks []*pb.IngestAck
+	for {
+		ack, err := stream.Recv()
+		if nil != err {
+			break
+		}
+		acks = append(acks, ack)
+	}
+
+	assert.Len(t, acks, 3)
+	assert.Equal(t, int64(1), acks[0].Seq)
+	assert.Equal(t, SubscriptionResponseStatusSuccess, acks[0].Status)
+	assert.Equal(t, int32(1), acks[0].Accepted)
+	assert.Equal(t, int32(2), acks[0].Dropped)
+	assert.Equal(t, SubscriptionResponseStatusDrop, acks[1].Status)
+	assert.Equal(t, int32(4), acks[1].Dropped)
+	assert.Equal(t, SubscriptionResponseStatusRetry, acks[2].Status)
+	assert.Equal(t, int32(1), acks[2].Accepted)
+	assert.Equal(t, int32(1), acks[2].Dropped)
+	for _, ack := range acks {
+		assert.True(t, ack.Credits > 0 && ack.Credits <= 4)
+	}
+
+	assert.Len(t, manager.messages, 2)
+	assert.Equal(t, "channel1", manager.messages[0].Headers.Get(statem.MessageCtxHeaderChannelID))
+	assert.Equal(t, "admin", manager.messages[0].Headers.GetOwner())
+	msg, _ := manager.messages[0].Message.(statem.PropertyMessage)
+	assert.Equal(t, int64(1000), msg.Timestamp)
+	assert.Equal(t, statem.QualityBad, msg.Quality)
+	assert.Equal(t, "device2", manager.messages[1].Headers.GetTargetID())
+	assert.Equal(t, "channel2", manager.messages[1].Headers.Get(statem.MessageCtxHeaderChannelID))
+}
+
+func TestIngestService_Reload(t *testing.T) {
+	s := NewIngestService(&ingestManager{}, 4, 3)
+	s.Reload(0, 10)
+	window, maxBatchSize := s.limits()
+	assert.Equal(t, 1, window)
+	assert.Equal(t, 10, maxBatchSize)
+
+	ack := s.handleBatch(context.Background(), &pb.IngestBatch{Seq: 1, Updates: make([]*pb.PropertyUpdate, 11)})
+	assert.Equal(t, SubscriptionResponseStatusDrop, ack.Status)
+}
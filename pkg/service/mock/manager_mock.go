@@ -5,6 +5,7 @@ import (
 
 	pb "github.com/tkeel-io/core/api/core/v1"
 	"github.com/tkeel-io/core/pkg/entities"
+	"github.com/tkeel-io/core/pkg/runtime"
 	"github.com/tkeel-io/core/pkg/statem"
 	"github.com/tkeel-io/kit/log"
 	"go.uber.org/zap"
@@ -20,9 +21,15 @@ func NewEntityManagerMock() entities.EntityManager {
 // Start start Entity manager.
 func (m *EntityManagerMock) Start() error { return nil }
 
+// Shutdown drains messages and flushes entities.
+func (m *EntityManagerMock) Shutdown(ctx context.Context) (runtime.DrainReport, error) {
+	return runtime.DrainReport{}, nil
+}
+
 // OnMessage handle message.
-func (m *EntityManagerMock) OnMessage(ctx context.Context, msgCtx statem.MessageContext) {
+func (m *EntityManagerMock) OnMessage(ctx context.Context, msgCtx statem.MessageContext) error {
 	log.Debug("handle message", zap.Any("headers", msgCtx.Headers), zap.Any("message", msgCtx.Message))
+	return nil
 }
 
 // CreateEntity create entity.
@@ -30,11 +37,16 @@ func (m *EntityManagerMock) CreateEntity(ctx context.Context, en *statem.Base) (
 	return en, nil
 }
 
-// DeleteEntity delete entity.
+// DeleteEntity soft-deletes entity.
 func (m *EntityManagerMock) DeleteEntity(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
 	return en, nil
 }
 
+// RestoreEntity restores soft-deleted entity.
+func (m *EntityManagerMock) RestoreEntity(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
+	return en, nil
+}
+
 // GetProperties returns entity properties.
 func (m *EntityManagerMock) GetProperties(ctx context.Context, en *statem.Base) (base *statem.Base, err error) {
 	return en, nil
@@ -85,6 +97,34 @@ func (m *EntityManagerMock) RemoveConfigs(ctx context.Context, en *statem.Base,
 	return en, nil
 }
 
+// AddRelationship adds relationship from entity.
+func (m *EntityManagerMock) AddRelationship(ctx context.Context, en *statem.Base, rel statem.Relationship) (base *statem.Base, err error) {
+	en.SetRelationship(rel)
+	return en, nil
+}
+
+// RemoveRelationship removes relationship from entity.
+func (m *EntityManagerMock) RemoveRelationship(ctx context.Context, en *statem.Base, typ, target string) (base *statem.Base, err error) {
+	en.RemoveRelationship(typ, target)
+	return en, nil
+}
+
+// SetTimer sets timer of entity.
+func (m *EntityManagerMock) SetTimer(ctx context.Context, en *statem.Base, timer statem.Timer) (*statem.Timer, error) {
+	timer.EntityID, timer.Owner = en.ID, en.Owner
+	return &timer, nil
+}
+
+// CancelTimer removes timer of entity.
+func (m *EntityManagerMock) CancelTimer(ctx context.Context, en *statem.Base, timerID string) error {
+	return nil
+}
+
+// ListTimers returns timers of entity.
+func (m *EntityManagerMock) ListTimers(ctx context.Context, en *statem.Base) ([]statem.Timer, error) {
+	return nil, nil
+}
+
 // QueryConfigs returns entity configs.
 func (m *EntityManagerMock) QueryConfigs(ctx context.Context, en *statem.Base, propertyIDs []string) (base *statem.Base, err error) {
 	return en, nil
@@ -0,0 +1,322 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/entities"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/relation"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const relationshipPageSize = 100
+
+func (s *EntityService) AddRelationship(ctx context.Context, req *pb.AddRelationshipRequest) (out *pb.EntityResponse, err error) {
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Type = req.Type
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	parseHeaderFrom(ctx, entity)
+
+	if nil == req.Relationship {
+		log.Error("add relationship", logger.EntityID(req.Id), zap.Error(ErrEntityEmptyRequest))
+		return nil, ErrEntityEmptyRequest
+	} else if _, err = s.ownedEntity(ctx, entity); nil != err {
+		return nil, err
+	}
+
+	rel := statem.Relationship{Type: req.Relationship.Type, Target: req.Relationship.Target}
+	switch properties := req.Relationship.Properties.AsInterface().(type) {
+	case map[string]interface{}:
+		rel.Properties = properties
+	case nil:
+	default:
+		log.Error("add relationship", logger.EntityID(req.Id), zap.Error(ErrEntityInvalidParams))
+		return nil, ErrEntityInvalidParams
+	}
+
+	if entity, err = s.entityManager.AddRelationship(ctx, entity, rel); nil != err {
+		log.Error("add relationship", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "add relationship")
+	}
+
+	out = s.entity2EntityResponse(entity)
+	return out, nil
+}
+
+func (s *EntityService) RemoveRelationship(ctx context.Context, req *pb.RemoveRelationshipRequest) (out *pb.EntityResponse, err error) {
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Type = req.Type
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	parseHeaderFrom(ctx, entity)
+
+	if _, err = s.ownedEntity(ctx, entity); nil != err {
+		return nil, err
+	} else if entity, err = s.entityManager.RemoveRelationship(ctx, entity, req.RelationshipType, req.Target); nil != err {
+		log.Error("remove relationship", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "remove relationship")
+	}
+
+	out = s.entity2EntityResponse(entity)
+	return out, nil
+}
+
+func (s *EntityService) ListRelationships(ctx context.Context, req *pb.ListRelationshipsRequest) (out *pb.ListRelationshipsResponse, err error) {
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Type = req.Type
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	parseHeaderFrom(ctx, entity)
+
+	var edges []relation.Edge
+	graph := s.newEntityGraph(entity.Owner)
+	if _, err = s.ownedEntity(ctx, entity); nil != err {
+		return nil, err
+	} else if edges, err = graph.Edges(ctx, entity.ID, req.RelationshipType, directionOf(req.Direction)); nil != err {
+		log.Error("list relationships", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "list relationships")
+	}
+
+	out = &pb.ListRelationshipsResponse{Total: int64(len(edges))}
+	for _, edge := range edges {
+		out.Items = append(out.Items, edge2Relationship(edge))
+	}
+	return out, nil
+}
+
+func (s *EntityService) TraverseRelationships(ctx context.Context, req *pb.TraverseRelationshipsRequest) (out *pb.TraverseRelationshipsResponse, err error) {
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Type = req.Type
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	parseHeaderFrom(ctx, entity)
+
+	var nodes []relation.Node
+	graph := s.newEntityGraph(entity.Owner)
+	if _, err = s.ownedEntity(ctx, entity); nil != err {
+		return nil, err
+	} else if nodes, err = relation.Traverse(ctx, graph, entity.ID,
+		req.RelationshipType, directionOf(req.Direction), int(req.MaxDepth)); nil != err {
+		log.Error("traverse relationships", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "traverse relationships")
+	}
+
+	out = &pb.TraverseRelationshipsResponse{Total: int64(len(nodes))}
+	for _, node := range nodes {
+		out.Items = append(out.Items, &pb.RelatedEntity{
+			Id:           node.ID,
+			Depth:        int32(node.Depth),
+			Relationship: edge2Relationship(node.Edge),
+		})
+	}
+	return out, nil
+}
+
+func (s *EntityService) FindRelationshipPath(ctx context.Context, req *pb.FindRelationshipPathRequest) (out *pb.FindRelationshipPathResponse, err error) {
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Type = req.Type
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	parseHeaderFrom(ctx, entity)
+
+	var path []relation.Edge
+	graph := s.newEntityGraph(entity.Owner)
+	if _, err = s.ownedEntity(ctx, entity); nil != err {
+		return nil, err
+	} else if _, err = s.ownedEntity(ctx, &Entity{ID: req.Target, Owner: entity.Owner}); nil != err {
+		return nil, err
+	} else if path, err = relation.Path(ctx, graph, entity.ID, req.Target,
+		req.RelationshipType, directionOf(req.Direction), int(req.MaxDepth)); nil != err {
+		log.Error("find relationship path", logger.EntityID(req.Id), zap.String("target", req.Target), zap.Error(err))
+		return nil, errors.Wrap(err, "find relationship path")
+	}
+
+	out = &pb.FindRelationshipPathResponse{Path: make([]*pb.Relationship, 0, len(path))}
+	for _, edge := range path {
+		out.Path = append(out.Path, edge2Relationship(edge))
+	}
+	return out, nil
+}
+
+// ownedEntity returns entity which belongs to the owner of request.
+func (s *EntityService) ownedEntity(ctx context.Context, en *Entity) (*Entity, error) {
+	base, err := s.entityManager.GetProperties(ctx, en)
+	if nil != err {
+		log.Error("get entity", logger.EntityID(en.ID), zap.Error(err))
+		return nil, errors.Wrap(err, "get entity")
+	} else if err = ownedBy(en.Owner, base); nil != err {
+		log.Error("get entity", logger.EntityID(en.ID), zap.String("owner", en.Owner), zap.Error(err))
+		return nil, err
+	}
+	return base, nil
+}
+
+func directionOf(direction string) string {
+	if direction == "" {
+		return relation.DirectionOut
+	}
+	return direction
+}
+
+func relationships2pb(id string, rels []statem.Relationship) []*pb.Relationship {
+	out := make([]*pb.Relationship, 0, len(rels))
+	for _, rel := range rels {
+		out = append(out, edge2Relationship(relation.Edge{
+			Type:       rel.Type,
+			Source:     id,
+			Target:     rel.Target,
+			Properties: rel.Properties,
+		}))
+	}
+	return out
+}
+
+func edge2Relationship(edge relation.Edge) *pb.Relationship {
+	rel := &pb.Relationship{Type: edge.Type, Source: edge.Source, Target: edge.Target}
+	if len(edge.Properties) > 0 {
+		var err error
+		if rel.Properties, err = structpb.NewValue(edge.Properties); nil != err {
+			log.Error("convert relationship", logger.EntityID(edge.Source), zap.Error(err))
+		}
+	}
+	return rel
+}
+
+// entityGraph is graph of entities of an owner, outgoing edges are stored with
+// the entity and incoming edges are found by searching related entities.
+type entityGraph struct {
+	owner         string
+	entityManager entities.EntityManager
+	searchClient  pb.SearchHTTPServer
+}
+
+func (s *EntityService) newEntityGraph(owner string) *entityGraph {
+	return &entityGraph{owner: owner, entityManager: s.entityManager, searchClient: s.searchClient}
+}
+
+func (g *entityGraph) Edges(ctx context.Context, id, typ, direction string) ([]relation.Edge, error) {
+	var edges []relation.Edge
+	switch direction {
+	case relation.DirectionOut, relation.DirectionIn, relation.DirectionBoth:
+	default:
+		return nil, errors.Wrap(relation.ErrInvalidDirection, direction)
+	}
+
+	if direction != relation.DirectionIn {
+		base, err := g.entity(ctx, id)
+		if nil != err {
+			return nil, err
+		} else if nil != base {
+			// skip edges to deleted or invisible entities.
+			for _, edge := range outEdges(base, typ, "") {
+				if target, err := g.entity(ctx, edge.Target); nil != err {
+					return nil, err
+				} else if nil != target {
+					edges = append(edges, edge)
+				}
+			}
+		}
+	}
+
+	if direction != relation.DirectionOut {
+		sources, err := g.relatedTo(ctx, id)
+		if nil != err {
+			return nil, err
+		}
+		for _, source := range sources {
+			base, err := g.entity(ctx, source)
+			if nil != err {
+				return nil, err
+			} else if nil != base {
+				edges = append(edges, outEdges(base, typ, id)...)
+			}
+		}
+	}
+	return edges, nil
+}
+
+// entity returns entity of the owner, nil if the entity is deleted or invisible.
+func (g *entityGraph) entity(ctx context.Context, id string) (*Entity, error) {
+	base, err := g.entityManager.GetProperties(ctx, &Entity{ID: id, Owner: g.owner})
+	if errors.Is(err, entities.ErrEntityNotFound) || errors.Is(err, entities.ErrEntityDeleted) {
+		// dangling relationship.
+		return nil, nil
+	} else if nil != err {
+		return nil, errors.Wrap(err, "get entity")
+	} else if base.Owner != g.owner {
+		return nil, nil
+	}
+	return base, nil
+}
+
+// relatedTo returns ids of entities which have relationships to the entity.
+func (g *entityGraph) relatedTo(ctx context.Context, id string) ([]string, error) {
+	var ids []string
+	for pageNum := int32(1); ; pageNum++ {
+		resp, err := g.searchClient.Search(ctx, &pb.SearchRequest{
+			Owner:    g.owner,
+			PageNum:  pageNum,
+			PageSize: relationshipPageSize,
+			Condition: scopeByOwner([]*pb.SearchCondition{{
+				Field:    statem.SearchFieldRelated,
+				Operator: "$eq",
+				Value:    structpb.NewStringValue(id),
+			}}, g.owner),
+		})
+		if nil != err {
+			return nil, errors.Wrap(err, "search related entities")
+		}
+
+		for _, item := range resp.Items {
+			if source := item.GetStructValue().GetFields()["id"].GetStringValue(); source != "" && source != id {
+				ids = append(ids, source)
+			}
+		}
+		if len(resp.Items) < relationshipPageSize {
+			return ids, nil
+		}
+	}
+}
+
+// outEdges returns edges of the type from entity, to target if not empty.
+func outEdges(base *Entity, typ, target string) []relation.Edge {
+	var edges []relation.Edge
+	for _, rel := range base.GetRelationships(typ) {
+		if target == "" || rel.Target == target {
+			edges = append(edges, relation.Edge{
+				Type:       rel.Type,
+				Source:     base.ID,
+				Target:     rel.Target,
+				Properties: rel.Properties,
+			})
+		}
+	}
+	return edges
+}
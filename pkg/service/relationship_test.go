@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/entities"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/relation"
+	"github.com/tkeel-io/core/pkg/service/mock"
+	"github.com/tkeel-io/core/pkg/statem"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// graphManager stores entities in memory, relationships are indexed into search.
+type graphManager struct {
+	mock.EntityManagerMock
+	entities     map[string]*statem.Base
+	searchClient pb.SearchHTTPServer
+}
+
+func (m *graphManager) GetProperties(ctx context.Context, en *statem.Base) (*statem.Base, error) {
+	if base, has := m.entities[en.ID]; has {
+		return base, nil
+	}
+	return nil, entities.ErrEntityNotFound
+}
+
+func (m *graphManager) AddRelationship(ctx context.Context, en *statem.Base, rel statem.Relationship) (*statem.Base, error) {
+	base := m.entities[en.ID]
+	base.SetRelationship(rel)
+	val, err := structpb.NewValue(base.SearchValues())
+	if nil != err {
+		return nil, err
+	}
+	_, err = m.searchClient.Index(ctx, &pb.IndexObject{Obj: val})
+	return base, err
+}
+
+func newGraphService(t *testing.T) *EntityService {
+	searchClient := fake.NewSearch(t)
+
+	manager := &graphManager{entities: map[string]*statem.Base{}, searchClient: searchClient}
+	for _, id := range []string{"building1", "room1", "room2", "switch1", "switch2", "device1", "device2"} {
+		manager.entities[id] = &statem.Base{ID: id, Type: "DEVICE", Owner: "admin"}
+	}
+	manager.entities["device3"] = &statem.Base{ID: "device3", Type: "DEVICE", Owner: "other"}
+
+	service, err := NewEntityService(context.Background(), manager, searchClient)
+	assert.Nil(t, err)
+
+	for _, rel := range []struct{ source, typ, target string }{
+		{"building1", statem.RelationshipContains, "room1"},
+		{"building1", statem.RelationshipContains, "room2"},
+		{"room1", statem.RelationshipContains, "device1"},
+		{"room2", statem.RelationshipContains, "device2"},
+		{"room2", statem.RelationshipContains, "switch2"},
+		{"switch1", statem.RelationshipConnectedTo, "switch2"},
+		{"device1", statem.RelationshipConnectedTo, "switch1"},
+		{"device2", statem.RelationshipLocatedIn, "room2"},
+		{"device2", statem.RelationshipConnectedTo, "device3"},
+	} {
+		out, err := service.AddRelationship(context.Background(), &pb.AddRelationshipRequest{
+			Id:           rel.source,
+			Owner:        "admin",
+			Relationship: &pb.Relationship{Type: rel.typ, Target: rel.target},
+		})
+		assert.Nil(t, err)
+		assert.NotEmpty(t, out.Relationships)
+	}
+	return service
+}
+
+func Test_AddRelationship(t *testing.T) {
+	properties, err := structpb.NewValue(map[string]interface{}{"port": 1})
+	assert.Nil(t, err)
+
+	out, err := entityService.AddRelationship(context.Background(), &pb.AddRelationshipRequest{
+		Id:           "switch1",
+		Owner:        "admin",
+		Relationship: &pb.Relationship{Type: statem.RelationshipConnectedTo, Target: "switch2", Properties: properties},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []*pb.Relationship{{Type: statem.RelationshipConnectedTo, Source: "switch1", Target: "switch2", Properties: properties}}, out.Relationships)
+
+	_, err = entityService.AddRelationship(context.Background(), &pb.AddRelationshipRequest{Id: "switch1", Owner: "admin"})
+	assert.ErrorIs(t, err, ErrEntityEmptyRequest)
+}
+
+func Test_ListRelationships(t *testing.T) {
+	service := newGraphService(t)
+
+	out, err := service.ListRelationships(context.Background(), &pb.ListRelationshipsRequest{Id: "room2", Owner: "admin"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), out.Total)
+	assert.Equal(t, "device2", out.Items[0].Target)
+
+	out, err = service.ListRelationships(context.Background(), &pb.ListRelationshipsRequest{
+		Id: "room2", Owner: "admin", Direction: relation.DirectionIn,
+	})
+	assert.Nil(t, err)
+	var sources []string
+	for _, item := range out.Items {
+		sources = append(sources, item.Source+"/"+item.Type)
+	}
+	assert.ElementsMatch(t, []string{"building1/contains", "device2/located-in"}, sources)
+
+	_, err = service.ListRelationships(context.Background(), &pb.ListRelationshipsRequest{Id: "device3", Owner: "admin"})
+	assert.ErrorIs(t, err, ErrEntityForbidden)
+
+	// search entities by relationships.
+	resp, err := service.searchClient.Search(context.Background(), &pb.SearchRequest{
+		Condition: []*pb.SearchCondition{{
+			Field:    statem.SearchFieldRelationships + "." + statem.RelationshipContains,
+			Operator: "$eq",
+			Value:    structpb.NewStringValue("device2"),
+		}},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, resp.Items, 1)
+	assert.Equal(t, "room2", resp.Items[0].GetStructValue().GetFields()["id"].GetStringValue())
+}
+
+func Test_TraverseRelationships(t *testing.T) {
+	service := newGraphService(t)
+
+	// all entities in building recursively.
+	out, err := service.TraverseRelationships(context.Background(), &pb.TraverseRelationshipsRequest{
+		Id: "building1", Owner: "admin", RelationshipType: statem.RelationshipContains,
+	})
+	assert.Nil(t, err)
+	var ids []string
+	for _, item := range out.Items {
+		ids = append(ids, item.Id)
+	}
+	assert.Equal(t, []string{"room1", "room2", "device1", "device2", "switch2"}, ids)
+	assert.Equal(t, int32(2), out.Items[2].Depth)
+	assert.Equal(t, "room1", out.Items[2].Relationship.Source)
+
+	// children only.
+	out, err = service.TraverseRelationships(context.Background(), &pb.TraverseRelationshipsRequest{
+		Id: "building1", Owner: "admin", RelationshipType: statem.RelationshipContains, MaxDepth: 1,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), out.Total)
+
+	// entity of another owner is invisible.
+	out, err = service.TraverseRelationships(context.Background(), &pb.TraverseRelationshipsRequest{
+		Id: "device2", Owner: "admin", RelationshipType: statem.RelationshipConnectedTo,
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, out.Items)
+}
+
+func Test_FindRelationshipPath(t *testing.T) {
+	service := newGraphService(t)
+
+	out, err := service.FindRelationshipPath(context.Background(), &pb.FindRelationshipPathRequest{
+		Id: "switch2", Owner: "admin", Target: "device1", Direction: relation.DirectionBoth,
+	})
+	assert.Nil(t, err)
+	var path []string
+	for _, edge := range out.Path {
+		path = append(path, edge.Source+"->"+edge.Target)
+	}
+	assert.Equal(t, []string{"switch1->switch2", "device1->switch1"}, path)
+
+	_, err = service.FindRelationshipPath(context.Background(), &pb.FindRelationshipPathRequest{
+		Id: "switch2", Owner: "admin", Target: "device1",
+	})
+	assert.ErrorIs(t, err, relation.ErrPathNotFound)
+}
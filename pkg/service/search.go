@@ -40,6 +40,10 @@ func (s *SearchService) Index(ctx context.Context, req *pb.IndexObject) (*pb.Ind
 	return out, nil
 }
 func (s *SearchService) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	// scope search to the tenant of caller.
+	req.Owner = parseOwnerFrom(ctx, req.Owner)
+	req.Condition = scopeByOwner(req.Condition, req.Owner)
+
 	out, err := s.searchClient.Search(ctx, req)
 	if err != nil {
 		return out, errors.Wrap(err, "search failed")
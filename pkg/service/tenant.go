@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/entities"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// parseOwnerFrom returns owner of caller, owner in header or metadata takes precedence.
+func parseOwnerFrom(ctx context.Context, owner string) string {
+	if h, ok := ctx.Value(struct{}{}).(http.Header); ok {
+		if headerOwner := h.Get(HeaderOwner); headerOwner != "" {
+			return headerOwner
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(HeaderOwner); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return owner
+}
+
+// scopeByOwner restricts search conditions to entities of the owner.
+func scopeByOwner(conditions []*pb.SearchCondition, owner string) []*pb.SearchCondition {
+	return append(conditions, &pb.SearchCondition{
+		Field:    "owner",
+		Operator: "$eq",
+		Value:    structpb.NewStringValue(owner),
+	})
+}
+
+// checkOwner checks that the entity belongs to the owner of request.
+func (s *EntityService) checkOwner(ctx context.Context, en *Entity) error {
+	base, err := s.entityManager.GetProperties(ctx, en)
+	if errors.Is(err, entities.ErrEntityNotFound) {
+		// entity will be created by the caller.
+		return nil
+	} else if nil != err {
+		log.Error("check entity owner", logger.EntityID(en.ID), zap.Error(err))
+		return errors.Wrap(err, "check entity owner")
+	} else if err = ownedBy(en.Owner, base); nil != err {
+		log.Error("check entity owner", logger.EntityID(en.ID), zap.String("owner", en.Owner), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func ownedBy(owner string, en *Entity) error {
+	if en.Owner != owner {
+		return ErrEntityForbidden
+	}
+	return nil
+}
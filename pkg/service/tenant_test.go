@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	transportHTTP "github.com/tkeel-io/kit/transport/http"
+)
+
+func Test_parseOwnerFrom(t *testing.T) {
+	assert.Equal(t, "admin", parseOwnerFrom(context.Background(), "admin"))
+
+	header := http.Header{}
+	header.Set(HeaderOwner, "tomas")
+	ctx := transportHTTP.ContextWithHeader(context.Background(), header)
+	assert.Equal(t, "tomas", parseOwnerFrom(ctx, "admin"))
+
+	entity := &Entity{Owner: "admin"}
+	parseHeaderFrom(ctx, entity)
+	assert.Equal(t, "tomas", entity.Owner)
+}
+
+func Test_scopeByOwner(t *testing.T) {
+	conditions := scopeByOwner([]*pb.SearchCondition{{Field: "type", Operator: "$eq"}}, "admin")
+	assert.Len(t, conditions, 2)
+	assert.Equal(t, "owner", conditions[1].Field)
+	assert.Equal(t, "admin", conditions[1].Value.GetStringValue())
+}
+
+func Test_ownedBy(t *testing.T) {
+	assert.Nil(t, ownedBy("admin", &Entity{Owner: "admin"}))
+	assert.Equal(t, ErrEntityForbidden, ownedBy("tomas", &Entity{Owner: "admin"}))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func (s *EntityService) SetTimer(ctx context.Context, req *pb.SetTimerRequest) (out *pb.Timer, err error) {
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Type = req.Type
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	parseHeaderFrom(ctx, entity)
+
+	var timer *statem.Timer
+	if nil == req.Timer {
+		log.Error("set timer", logger.EntityID(req.Id), zap.Error(ErrEntityEmptyRequest))
+		return nil, ErrEntityEmptyRequest
+	} else if timer, err = pb2timer(req.Timer); nil != err {
+		log.Error("set timer", logger.EntityID(req.Id), zap.Error(err))
+		return nil, err
+	} else if _, err = s.ownedEntity(ctx, entity); nil != err {
+		return nil, err
+	} else if timer, err = s.entityManager.SetTimer(ctx, entity, *timer); nil != err {
+		log.Error("set timer", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "set timer")
+	}
+
+	return timer2pb(timer), nil
+}
+
+func (s *EntityService) DeleteTimer(ctx context.Context, req *pb.DeleteTimerRequest) (out *pb.Timer, err error) {
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Type = req.Type
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	parseHeaderFrom(ctx, entity)
+
+	if _, err = s.ownedEntity(ctx, entity); nil != err {
+		return nil, err
+	} else if err = s.entityManager.CancelTimer(ctx, entity, req.TimerId); nil != err {
+		log.Error("delete timer", logger.EntityID(req.Id), zap.String("timer", req.TimerId), zap.Error(err))
+		return nil, errors.Wrap(err, "delete timer")
+	}
+
+	return &pb.Timer{Id: req.TimerId}, nil
+}
+
+func (s *EntityService) ListTimers(ctx context.Context, req *pb.ListTimersRequest) (out *pb.ListTimersResponse, err error) {
+	var entity = new(Entity)
+	entity.ID = req.Id
+	entity.Type = req.Type
+	entity.Owner = req.Owner
+	entity.Source = req.Source
+	parseHeaderFrom(ctx, entity)
+
+	var timers []statem.Timer
+	if _, err = s.ownedEntity(ctx, entity); nil != err {
+		return nil, err
+	} else if timers, err = s.entityManager.ListTimers(ctx, entity); nil != err {
+		log.Error("list timers", logger.EntityID(req.Id), zap.Error(err))
+		return nil, errors.Wrap(err, "list timers")
+	}
+
+	out = &pb.ListTimersResponse{Total: int64(len(timers))}
+	for index := range timers {
+		out.Items = append(out.Items, timer2pb(&timers[index]))
+	}
+	return out, nil
+}
+
+func pb2timer(in *pb.Timer) (*statem.Timer, error) {
+	timer := &statem.Timer{ID: in.Id, DueTime: in.DueTime, Idle: in.Idle}
+	if in.Interval != "" {
+		interval, err := time.ParseDuration(in.Interval)
+		if nil != err {
+			return nil, errors.Wrapf(statem.ErrTimerInvalid, "interval %q", in.Interval)
+		}
+		timer.Interval = interval.Milliseconds()
+	}
+
+	switch properties := in.Properties.AsInterface().(type) {
+	case map[string]interface{}:
+		timer.Properties = properties
+	case nil:
+	default:
+		return nil, ErrEntityInvalidParams
+	}
+	return timer, nil
+}
+
+func timer2pb(timer *statem.Timer) *pb.Timer {
+	out := &pb.Timer{Id: timer.ID, DueTime: timer.DueTime, Idle: timer.Idle}
+	if timer.Interval > 0 {
+		out.Interval = (time.Duration(timer.Interval) * time.Millisecond).String()
+	}
+	if len(timer.Properties) > 0 {
+		out.Properties, _ = structpb.NewValue(timer.Properties)
+	}
+	return out
+}
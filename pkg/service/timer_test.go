@@ -0,0 +1,39 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/statem"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func Test_pb2timer(t *testing.T) {
+	properties, _ := structpb.NewValue(map[string]interface{}{"status": "offline"})
+	timer, err := pb2timer(&pb.Timer{Id: "offline", Interval: "1m", Idle: true, Properties: properties})
+	assert.Nil(t, err)
+	assert.Equal(t, &statem.Timer{ID: "offline", Interval: 60000, Idle: true, Properties: map[string]interface{}{"status": "offline"}}, timer)
+	assert.Equal(t, &pb.Timer{Id: "offline", Interval: "1m0s", Idle: true, Properties: properties}, timer2pb(timer))
+
+	_, err = pb2timer(&pb.Timer{Id: "offline", Interval: "1 minute"})
+	assert.ErrorIs(t, err, statem.ErrTimerInvalid)
+	_, err = pb2timer(&pb.Timer{Id: "offline", DueTime: 1000, Properties: structpb.NewStringValue("offline")})
+	assert.ErrorIs(t, err, ErrEntityInvalidParams)
+}
@@ -19,11 +19,16 @@ package service
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	pb "github.com/tkeel-io/core/api/core/v1"
 	"github.com/tkeel-io/core/pkg/constraint"
 	"github.com/tkeel-io/core/pkg/entities"
 	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/tenant"
+	"github.com/tkeel-io/core/pkg/tracing"
 	"github.com/tkeel-io/kit/log"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -54,6 +59,10 @@ func NewTopicService(ctx context.Context, entityManager entities.EntityManager)
 }
 
 func (s *TopicService) TopicEventHandler(ctx context.Context, req *pb.TopicEventRequest) (out *pb.TopicEventResponse, err error) {
+	ctx, span := tracing.Start(ctx, "pubsub.TopicEventHandler",
+		attribute.String("event.id", req.Id), attribute.String("pubsub.topic", req.Topic))
+	defer span.End()
+
 	var values map[string]interface{}
 	var properties map[string]constraint.Node
 	switch kv := req.Data.AsInterface().(type) {
@@ -79,22 +88,40 @@ func (s *TopicService) TopicEventHandler(ctx context.Context, req *pb.TopicEvent
 		return &pb.TopicEventResponse{Status: SubscriptionResponseStatusDrop}, nil
 	}
 
+	// metadata of properties.
+	timestamp, _ := values["timestamp"].(float64)
+	quality := interface2string(values["quality"])
+	if quality != "" && !statem.ValidQuality(quality) {
+		log.Warn("invalid event quality", zap.String("id", req.Id), zap.String("quality", quality))
+		return &pb.TopicEventResponse{Status: SubscriptionResponseStatusDrop}, nil
+	}
+
 	msgCtx := statem.MessageContext{
 		Headers: statem.Header{},
 		Message: statem.PropertyMessage{
 			StateID:    interface2string(values["id"]),
 			Operator:   constraint.PatchOpReplace.String(),
 			Properties: properties,
+			Timestamp:  int64(timestamp),
+			Quality:    quality,
 		},
 	}
 
 	msgCtx.Headers.SetTargetID(interface2string(values["id"]))
 	msgCtx.Headers.SetOwner(interface2string(values["owner"]))
-	msgCtx.Headers.SetOwner(interface2string(values["type"]))
-	msgCtx.Headers.SetOwner(interface2string(values["source"]))
+	msgCtx.Headers.SetSource(interface2string(values["source"]))
+	msgCtx.Headers.Set(statem.MessageCtxHeaderType, interface2string(values["type"]))
 
+	tracing.Inject(ctx, msgCtx.Headers)
 	log.Debug("received event", zap.String("id", req.Id), zap.Any("event", req))
 
-	s.entityManager.OnMessage(ctx, msgCtx)
+	if err = s.entityManager.OnMessage(ctx, msgCtx); nil != err {
+		if errors.Is(err, tenant.ErrMessageRateLimited) {
+			return &pb.TopicEventResponse{Status: SubscriptionResponseStatusDrop}, nil
+		}
+		// redelivered by pubsub, e.g. shutting down.
+		log.Warn("handle event", zap.String("id", req.Id), zap.Error(err))
+		return &pb.TopicEventResponse{Status: SubscriptionResponseStatusRetry}, nil
+	}
 	return &pb.TopicEventResponse{Status: SubscriptionResponseStatusSuccess}, nil
 }
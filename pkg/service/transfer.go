@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	pb "github.com/tkeel-io/core/api/core/v1"
+	"github.com/tkeel-io/core/pkg/transfer"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+)
+
+// transferChunkSize is max size of data in a chunk.
+const transferChunkSize = 64 << 10
+
+type exporter interface {
+	Export(ctx context.Context, w io.Writer, opts transfer.ExportOptions) (int, error)
+}
+
+type importer interface {
+	Import(ctx context.Context, r io.Reader, opts transfer.ImportOptions) (*transfer.Report, error)
+}
+
+type TransferService struct {
+	pb.UnimplementedTransferServer
+	exporter exporter
+	importer importer
+}
+
+func NewTransferService(exporter *transfer.Exporter, importer *transfer.Importer) *TransferService {
+	return &TransferService{exporter: exporter, importer: importer}
+}
+
+// Export writes entities of the caller's owner into stream.
+func (s *TransferService) Export(req *pb.ExportRequest, stream pb.Transfer_ExportServer) error {
+	owner := parseOwnerFrom(stream.Context(), req.Owner)
+	if owner == "" {
+		return errors.Wrap(ErrOwnerRequired, "export entities")
+	}
+
+	w := bufio.NewWriterSize(&chunkWriter{stream: stream}, transferChunkSize)
+	opts := transfer.ExportOptions{Format: req.Format, Owner: owner, Type: req.Type}
+	if _, err := s.exporter.Export(stream.Context(), w, opts); nil != err {
+		log.Error("export entities", zap.Error(err))
+		return errors.Wrap(err, "export entities")
+	}
+	return errors.Wrap(w.Flush(), "export entities")
+}
+
+// Import creates or updates entities of the caller's owner from stream.
+func (s *TransferService) Import(stream pb.Transfer_ImportServer) error {
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return ErrEntityEmptyRequest
+	} else if nil != err {
+		return errors.Wrap(err, "import entities")
+	}
+
+	owner := parseOwnerFrom(stream.Context(), first.Owner)
+	if owner == "" {
+		return errors.Wrap(ErrOwnerRequired, "import entities")
+	}
+
+	opts := transfer.ImportOptions{Mode: first.Mode, DryRun: first.DryRun, Owner: owner}
+	if opts.Mode == "" {
+		opts.Mode = transfer.ModeCreate
+	}
+
+	report, err := s.importer.Import(stream.Context(), &chunkReader{stream: stream, data: first.Data}, opts)
+	if nil != err {
+		log.Error("import entities", zap.Error(err))
+		return errors.Wrap(err, "import entities")
+	}
+
+	out := &pb.ImportResponse{
+		DryRun:  report.DryRun,
+		Created: int32(report.Created),
+		Updated: int32(report.Updated),
+		Skipped: int32(report.Skipped),
+		Failed:  int32(report.Failed),
+	}
+	for _, result := range report.Results {
+		out.Results = append(out.Results, &pb.ImportResult{Id: result.ID, Status: result.Status, Error: result.Error})
+	}
+	return errors.Wrap(stream.SendAndClose(out), "import entities")
+}
+
+// chunkWriter sends every write as a chunk.
+type chunkWriter struct {
+	stream pb.Transfer_ExportServer
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := w.stream.Send(&pb.ExportChunk{Data: data}); nil != err {
+		return 0, errors.Wrap(err, "send chunk")
+	}
+	return len(p), nil
+}
+
+// chunkReader reads data of received chunks.
+type chunkReader struct {
+	stream pb.Transfer_ImportServer
+	data   []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		chunk, err := r.stream.Recv()
+		if nil != err {
+			// io.EOF is returned as is.
+			return 0, err //nolint
+		}
+		r.data = chunk.Data
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
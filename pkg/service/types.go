@@ -29,6 +29,12 @@ var (
 	ErrEntityInvalidParams   = errors.New("invalid params")
 	ErrEntityEmptyRequest    = errors.New("empty request")
 	ErrEntityPropertyIDEmpty = errors.New("emtpty property id")
+	ErrEntityForbidden       = errors.New("entity belongs to another owner")
+	ErrAuditDisabled         = errors.New("audit store disabled")
+	ErrBatchTooLarge         = errors.New("too many batch items")
+	ErrBatchDuplicateID      = errors.New("duplicate entity id in batch")
+	ErrAlarmNotFound         = errors.New("alarm not found")
+	ErrOwnerRequired         = errors.New("owner required")
 )
 
 type Entity = statem.Base
@@ -19,12 +19,19 @@ package statem
 import (
 	"context"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/config"
 	"github.com/tkeel-io/core/pkg/constraint"
 	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/metrics"
 	"github.com/tkeel-io/core/pkg/resource/tseries"
+	"github.com/tkeel-io/core/pkg/tracing"
+	"github.com/tkeel-io/core/pkg/util"
 	"github.com/tkeel-io/kit/log"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -33,34 +40,92 @@ func (s *statem) Flush(ctx context.Context) error {
 }
 
 func (s *statem) FlushState() error {
-	return errors.Wrap(s.flushState(s.ctx), "flush state-machine state")
+	err := observeFlush(s.ctx, metrics.SinkState, s.flushState)
+	return errors.Wrap(err, "flush state-machine state")
 }
 
 func (s *statem) FlushSearch() error {
-	return errors.Wrap(s.flushSearch(s.ctx), "flush state-machine state")
+	err := observeFlush(s.ctx, metrics.SinkSearch, s.flushSearch)
+	return errors.Wrap(err, "flush state-machine state")
 }
 
 func (s *statem) FlushTimeSeries() error {
-	return errors.Wrap(s.flushTimeSeries(s.ctx), "flush state-machine time-series")
+	err := observeFlush(s.ctx, metrics.SinkTimeSeries, s.flushTimeSeries)
+	return errors.Wrap(err, "flush state-machine time-series")
 }
 
+// flush flushes all sinks regardless of flush policies.
 func (s *statem) flush(ctx context.Context) error {
 	var err error
-	// flush state properties to es.
-	if err = s.flushSearch(ctx); nil == err {
-		log.Debug("entity flush Search completed", logger.EntityID(s.ID))
-	}
-	// flush state properties to state.
-	if err = s.flushState(ctx); nil == err {
-		log.Debug("entity flush State completed", logger.EntityID(s.ID))
+	now := time.Now()
+	for _, flusher := range s.flushers {
+		if e := s.flushSink(ctx, flusher, now); nil != e {
+			err = e
+		}
 	}
-	// flush state properties to TSDB.
-	if err = s.flushTimeSeries(ctx); nil == err {
-		log.Debug("entity flush TimeSeries completed", logger.EntityID(s.ID))
+	return errors.Wrap(err, "entity flush data failed")
+}
+
+// FlushDue flushes sinks whose flush policy is due, called by timer.
+func (s *statem) FlushDue(ctx context.Context, now time.Time) error {
+	var err error
+	cfg := config.Get().Flush
+	for _, flusher := range s.flushers {
+		if s.flushDue(cfg, flusher, now) {
+			if e := s.flushSink(ctx, flusher, now); nil != e {
+				err = e
+			}
+		}
 	}
 	return errors.Wrap(err, "entity flush data failed")
 }
 
+// onFlushMessage records message to sinks, flushes sinks whose flush policy is due.
+func (s *statem) onFlushMessage(ctx context.Context) {
+	now := time.Now()
+	s.collectTimeSeries(now)
+	for _, flusher := range s.flushers {
+		flusher.received(now)
+	}
+
+	if err := s.FlushDue(ctx, now); nil != err {
+		log.Error("flush entity", logger.EntityID(s.ID), zap.Error(err))
+	}
+}
+
+func (s *statem) flushDue(cfg config.Flush, flusher *sinkFlusher, now time.Time) bool {
+	if flusher.sink == metrics.SinkTimeSeries && len(s.tseriesSamples) >= maxPendingSamples {
+		return true
+	}
+	return flusher.due(flushPolicy(cfg, s.Type, flusher.sink), now)
+}
+
+func (s *statem) flushSink(ctx context.Context, flusher *sinkFlusher, now time.Time) error {
+	if err := observeFlush(ctx, flusher.sink, flusher.flush); nil != err {
+		return err
+	}
+
+	log.Debug("entity flush completed", logger.EntityID(s.ID), zap.String("sink", flusher.sink))
+	flusher.flushed(now)
+	atomic.StoreInt64(&s.lastFlushTime, util.UnixMilli())
+	return nil
+}
+
+// observeFlush records latency, errors and span of sink flushing.
+func observeFlush(ctx context.Context, sink string, flush func(context.Context) error) error {
+	ctx, span := tracing.Start(ctx, "statem.flush", attribute.String("sink", sink))
+	defer span.End()
+
+	start := time.Now()
+	err := flush(ctx)
+	metrics.FlushDuration.WithLabelValues(sink).Observe(time.Since(start).Seconds())
+	if nil != err {
+		metrics.FlushErrors.WithLabelValues(sink).Inc()
+		tracing.RecordError(span, err)
+	}
+	return err
+}
+
 func (s *statem) flushState(ctx context.Context) error {
 	s.Mappers = []MapperDesc{}
 	for _, m := range s.mappers {
@@ -106,17 +171,10 @@ func (s *statem) flushSearch(ctx context.Context) error {
 	}
 
 	// flush all.
-	for key, val := range s.KValues {
-		flushData[key] = val.Value()
+	for key, val := range s.Base.SearchValues() {
+		flushData[key] = val
 	}
 
-	// basic fields.
-	flushData["id"] = s.ID
-	flushData["type"] = s.Type
-	flushData["owner"] = s.Owner
-	flushData["source"] = s.Source
-	flushData["version"] = s.Version
-	flushData["last_time"] = s.LastTime
 	if err = s.stateManager.SearchFlush(ctx, flushData); nil != err {
 		log.Error("flush state Search.", zap.Any("data", flushData), zap.Error(err))
 	}
@@ -126,8 +184,26 @@ func (s *statem) flushSearch(ctx context.Context) error {
 }
 
 func (s *statem) flushTimeSeries(ctx context.Context) error {
+	if len(s.tseriesSamples) == 0 {
+		return nil
+	}
+
+	flushData := s.tseriesSamples
+	err := s.stateManager.TimeSeriesFlush(ctx, flushData)
+	if nil != err {
+		log.Error("flush timeseries Search.", zap.Any("data", flushData), zap.Error(err))
+		return errors.Wrap(err, "timeseries flush failed")
+	}
+
+	log.Debug("flush timeseries Search.", zap.Any("data", flushData))
+	s.tseriesSamples = nil
+	return nil
+}
+
+// collectTimeSeries buffers time-series samples of current properties, so that
+// every sample is written even if flushing is batched.
+func (s *statem) collectTimeSeries(now time.Time) {
 	var err error
-	var flushData []tseries.TSeriesData
 	for _, JSONPath := range s.tseriesConstraints {
 		var val constraint.Node
 		var ct *constraint.Constraint
@@ -140,18 +216,12 @@ func (s *statem) flushTimeSeries(ctx context.Context) error {
 				Tags:        s.generateTags(),
 				Fields:      map[string]string{},
 				Value:       val.String(),
+				Timestamp:   now.UnixNano(),
 			}
-			flushData = append(flushData, point)
+			s.tseriesSamples = append(s.tseriesSamples, point)
 		}
 		log.Warn("patch.copy entity property failed", logger.EntityID(s.ID), zap.String("property_key", JSONPath), zap.Error(err))
 	}
-
-	if err = s.stateManager.TimeSeriesFlush(ctx, flushData); nil != err {
-		log.Error("flush timeseries Search.", zap.Any("data", flushData), zap.Error(err))
-	}
-
-	log.Debug("flush timeseries Search.", zap.Any("data", flushData))
-	return errors.Wrap(err, "timeseries flush failed")
 }
 
 // generateTags generate entity tags.
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+// HistoryValue is a value of property kept in history.
+type HistoryValue struct {
+	Value     interface{} `json:"value" msgpack:"value" mapstructure:"value"`
+	Timestamp int64       `json:"ts" msgpack:"ts" mapstructure:"ts"`
+}
+
+// HistoryRing is a fixed-size ring buffer of property values.
+type HistoryRing struct {
+	Values []HistoryValue `json:"values" msgpack:"values" mapstructure:"values"`
+	// Next is the index of the next written value, the oldest value once full.
+	Next int `json:"next" msgpack:"next" mapstructure:"next"`
+}
+
+// Push writes value into ring of size, overwrites the oldest value once full.
+func (r *HistoryRing) Push(val HistoryValue, size int) {
+	if len(r.Values) > size || (len(r.Values) < size && r.Next != len(r.Values)) {
+		// size changed, rearrange values oldest first.
+		values := r.List()
+		if len(values) > size {
+			values = values[len(values)-size:]
+		}
+		r.Values, r.Next = values, len(values)%size
+	}
+
+	if len(r.Values) < size {
+		r.Values = append(r.Values, val)
+	} else {
+		r.Values[r.Next] = val
+	}
+	r.Next = (r.Next + 1) % size
+}
+
+// List returns values oldest first.
+func (r *HistoryRing) List() []HistoryValue {
+	if r.Next >= len(r.Values) {
+		return append([]HistoryValue{}, r.Values...)
+	}
+	return append(append([]HistoryValue{}, r.Values[r.Next:]...), r.Values[:r.Next]...)
+}
+
+// GetHistory returns last n values of property, oldest first.
+func (b *Base) GetHistory(propertyKey string, n int) []HistoryValue {
+	ring, has := b.History[propertyKey]
+	if !has {
+		return nil
+	}
+
+	values := ring.List()
+	if n > 0 && len(values) > n {
+		values = values[len(values)-n:]
+	}
+	return values
+}
+
+// propertyRoot returns root property id of property key.
+func propertyRoot(propertyKey string) string {
+	if index := strings.IndexAny(propertyKey, ".["); index > 0 {
+		return propertyKey[:index]
+	}
+	return propertyKey
+}
+
+// keepPrevious keeps values of properties before they are changed, values
+// are inputs of TQL prev and delta.
+func (s *statem) keepPrevious(stateID string, properties map[string]constraint.Node) {
+	if _, has := s.prevProps[stateID]; !has {
+		s.prevProps[stateID] = make(map[string]constraint.Node)
+	}
+
+	prevProps := s.prevProps[stateID]
+	for key := range properties {
+		if stateID != s.ID {
+			// properties of other entities are cached by property key.
+			if val, has := s.cacheProps[stateID][key]; has {
+				prevProps[key] = val
+			}
+			continue
+		}
+
+		root := propertyRoot(key)
+		if val, has := s.KValues[root]; has {
+			prevProps[root] = val
+		}
+	}
+}
+
+// getPrevious returns value of property before it was changed.
+func (s *statem) getPrevious(stateID, propertyKey string) constraint.Node {
+	prevProps := s.prevProps[stateID]
+	if val, has := prevProps[propertyKey]; has {
+		return val
+	} else if stateID != s.ID {
+		return nil
+	}
+
+	val, err := s.getProperty(prevProps, propertyKey)
+	if nil != err || val.Type() == constraint.Null {
+		return nil
+	}
+	return val
+}
+
+// recordHistory appends values of changed properties to history if enabled.
+func (s *statem) recordHistory(properties map[string]constraint.Node) {
+	timestamp := time.Now().UnixNano() / 1e6
+	recorded := make(map[string]bool)
+	for key := range properties {
+		root := propertyRoot(key)
+		if recorded[root] {
+			continue
+		}
+
+		recorded[root] = true
+		cfg := s.Configs[root]
+		size := cfg.HistorySize()
+		val, has := s.KValues[root]
+		if size == 0 || !has {
+			delete(s.History, root)
+			continue
+		}
+
+		if nil == s.History {
+			s.History = make(map[string]*HistoryRing)
+		}
+		ring := s.History[root]
+		if nil == ring {
+			ring = &HistoryRing{}
+			s.History[root] = ring
+		}
+		ring.Push(HistoryValue{Value: val.Value(), Timestamp: timestamp}, size)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+func TestHistory(t *testing.T) {
+	base := Base{
+		ID:      "device123",
+		KValues: map[string]constraint.Node{},
+		Configs: map[string]constraint.Config{
+			"temp": {ID: "temp", Type: constraint.PropertyTypeFloat, Define: map[string]interface{}{constraint.DefineFieldHistory: float64(3)}},
+		},
+	}
+
+	sm, err := NewState(context.Background(), NewStateManagerMock(), &base, nil)
+	assert.Nil(t, err)
+	state, _ := sm.(*statem)
+
+	for _, val := range []float64{20, 21, 22, 23} {
+		state.invokePropertyMsg(PropertyMessage{
+			StateID:    "device123",
+			Operator:   "replace",
+			Properties: map[string]constraint.Node{"temp": constraint.NewNode(val), "humidity": constraint.NewNode(val)},
+		})
+	}
+
+	// history of last 3 values, properties without history are not kept.
+	values := state.GetHistory("temp", 0)
+	assert.Len(t, values, 3)
+	assert.Equal(t, float64(21), values[0].Value)
+	assert.Equal(t, float64(23), values[2].Value)
+	assert.Len(t, state.GetHistory("temp", 2), 2)
+	assert.Empty(t, state.GetHistory("humidity", 0))
+
+	// previous values of this and other entities.
+	assert.Equal(t, float64(22), state.getPrevious("device123", "temp").Value())
+	assert.Nil(t, state.getPrevious("device234", "temp"))
+	state.invokePropertyMsg(PropertyMessage{StateID: "device234", Properties: map[string]constraint.Node{"temp": constraint.NewNode(10)}})
+	assert.Nil(t, state.getPrevious("device234", "temp"))
+	state.invokePropertyMsg(PropertyMessage{StateID: "device234", Properties: map[string]constraint.Node{"temp": constraint.NewNode(12)}})
+	assert.Equal(t, int64(10), state.getPrevious("device234", "temp").Value())
+
+	// history is persisted with entity.
+	bytes, err := EncodeBase(&state.Base)
+	assert.Nil(t, err)
+	decoded, err := DecodeBase(bytes)
+	assert.Nil(t, err)
+	decodedValues := decoded.GetHistory("temp", 0)
+	assert.Len(t, decodedValues, 3)
+	assert.Equal(t, float64(23), decodedValues[2].Value)
+	assert.Equal(t, values[2].Timestamp, decodedValues[2].Timestamp)
+}
+
+func TestHistoryRing(t *testing.T) {
+	ring := &HistoryRing{}
+	for i := 1; i <= 5; i++ {
+		ring.Push(HistoryValue{Value: i}, 3)
+	}
+	assert.Equal(t, []HistoryValue{{Value: 3}, {Value: 4}, {Value: 5}}, ring.List())
+	assert.Len(t, ring.Values, 3)
+
+	// size shrinks.
+	ring.Push(HistoryValue{Value: 6}, 2)
+	assert.Equal(t, []HistoryValue{{Value: 5}, {Value: 6}}, ring.List())
+
+	// size grows.
+	ring.Push(HistoryValue{Value: 7}, 4)
+	ring.Push(HistoryValue{Value: 8}, 4)
+	ring.Push(HistoryValue{Value: 9}, 4)
+	assert.Equal(t, []HistoryValue{{Value: 6}, {Value: 7}, {Value: 8}, {Value: 9}}, ring.List())
+}
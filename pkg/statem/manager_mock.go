@@ -27,3 +27,7 @@ func (s *StateManagerMock) RemoveConfigs(context.Context, *Base, []string) error
 func (s *StateManagerMock) PatchConfigs(context.Context, *Base, []*PatchData) error {
 	return nil
 }
+func (s *StateManagerMock) SetTimer(context.Context, Timer) error { return nil }
+func (s *StateManagerMock) CancelTimer(ctx context.Context, entityID, timerID string) error {
+	return nil
+}
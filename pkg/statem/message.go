@@ -44,6 +44,19 @@ type PropertyMessage struct {
 	StateID    string                     `json:"state_id"`
 	Operator   string                     `json:"operator"`
 	Properties map[string]constraint.Node `json:"properties"`
+	// Timestamp of properties given by source in milliseconds, receive time if not set.
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// Quality of properties, good if not set.
+	Quality string `json:"quality,omitempty"`
+}
+
+// TimerMessage is delivered into entity when timer fires.
+type TimerMessage struct {
+	MessageBase
+
+	TimerID    string                     `json:"timer_id"`
+	FireTime   int64                      `json:"fire_time"`
+	Properties map[string]constraint.Node `json:"properties"`
 }
 
 type MapperMessage struct {
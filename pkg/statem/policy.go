@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/metrics"
+)
+
+// flush modes.
+const (
+	FlushEveryMessage = "every_message"
+	FlushEveryN       = "every_n"
+	FlushInterval     = "interval"
+	FlushOnIdle       = "on_idle"
+
+	// maxPendingSamples forces time-series flushing when too many samples buffered.
+	maxPendingSamples = 1000
+)
+
+// sinkFlusher flushes a sink of state machine according to flush policy.
+type sinkFlusher struct {
+	sink  string
+	flush func(context.Context) error
+
+	pending      int
+	firstPending time.Time
+	lastMessage  time.Time
+	lastFlush    time.Time
+}
+
+func newSinkFlusher(sink string, flush func(context.Context) error) *sinkFlusher {
+	return &sinkFlusher{sink: sink, flush: flush}
+}
+
+// received records a message to be flushed.
+func (f *sinkFlusher) received(now time.Time) {
+	if f.pending == 0 {
+		f.firstPending = now
+	}
+	f.pending++
+	f.lastMessage = now
+}
+
+// flushed resets pending messages.
+func (f *sinkFlusher) flushed(now time.Time) {
+	f.pending = 0
+	f.lastFlush = now
+}
+
+// due reports whether pending messages should be flushed now.
+func (f *sinkFlusher) due(policy config.FlushPolicy, now time.Time) bool {
+	if f.pending == 0 {
+		return false
+	}
+
+	switch policy.Mode {
+	case FlushEveryN:
+		if f.pending >= policy.Count {
+			return true
+		}
+		return policy.Interval > 0 && now.Sub(f.firstPending) >= policy.Interval
+	case FlushInterval:
+		return now.Sub(f.lastFlush) >= policy.Interval
+	case FlushOnIdle:
+		return now.Sub(f.lastMessage) >= policy.Interval
+	default:
+		return true
+	}
+}
+
+// flushPolicy returns flush policy of the sink for entity type.
+func flushPolicy(cfg config.Flush, entityType, sink string) config.FlushPolicy {
+	if policies, ok := cfg.Types[strings.ToLower(entityType)]; ok {
+		if policy := sinkPolicy(policies, sink); policy.Mode != "" {
+			return policy
+		}
+	}
+	return sinkPolicy(cfg.Default, sink)
+}
+
+func sinkPolicy(policies config.FlushPolicies, sink string) config.FlushPolicy {
+	switch sink {
+	case metrics.SinkState:
+		return policies.State
+	case metrics.SinkSearch:
+		return policies.Search
+	case metrics.SinkTimeSeries:
+		return policies.TimeSeries
+	default:
+		return config.FlushPolicy{}
+	}
+}
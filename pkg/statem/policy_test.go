@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/metrics"
+)
+
+func TestSinkFlusher_Due(t *testing.T) {
+	now := time.Now()
+	flusher := newSinkFlusher(metrics.SinkState, nil)
+	assert.False(t, flusher.due(config.FlushPolicy{}, now))
+
+	flusher.received(now)
+	assert.True(t, flusher.due(config.FlushPolicy{}, now))
+	assert.True(t, flusher.due(config.FlushPolicy{Mode: FlushEveryMessage}, now))
+
+	// every n, bounded by interval.
+	everyN := config.FlushPolicy{Mode: FlushEveryN, Count: 3, Interval: time.Second}
+	assert.False(t, flusher.due(everyN, now))
+	flusher.received(now)
+	flusher.received(now)
+	assert.True(t, flusher.due(everyN, now))
+	flusher.flushed(now)
+	flusher.received(now)
+	assert.False(t, flusher.due(everyN, now.Add(500*time.Millisecond)))
+	assert.True(t, flusher.due(everyN, now.Add(time.Second)))
+
+	// interval since last flush.
+	interval := config.FlushPolicy{Mode: FlushInterval, Interval: time.Second}
+	assert.False(t, flusher.due(interval, now.Add(500*time.Millisecond)))
+	assert.True(t, flusher.due(interval, now.Add(time.Second)))
+
+	// idle since last message.
+	onIdle := config.FlushPolicy{Mode: FlushOnIdle, Interval: time.Second}
+	flusher.received(now.Add(800 * time.Millisecond))
+	assert.False(t, flusher.due(onIdle, now.Add(time.Second)))
+	assert.True(t, flusher.due(onIdle, now.Add(1800*time.Millisecond)))
+}
+
+func TestFlushPolicy(t *testing.T) {
+	cfg := config.Flush{
+		Default: config.FlushPolicies{
+			State:  config.FlushPolicy{Mode: FlushInterval, Interval: time.Second},
+			Search: config.FlushPolicy{Mode: FlushOnIdle, Interval: time.Second},
+		},
+		Types: map[string]config.FlushPolicies{
+			"device": {State: config.FlushPolicy{Mode: FlushEveryN, Count: 10}},
+		},
+	}
+
+	assert.Equal(t, FlushEveryN, flushPolicy(cfg, "DEVICE", metrics.SinkState).Mode)
+	assert.Equal(t, FlushOnIdle, flushPolicy(cfg, "DEVICE", metrics.SinkSearch).Mode)
+	assert.Equal(t, FlushInterval, flushPolicy(cfg, "GATEWAY", metrics.SinkState).Mode)
+	assert.Equal(t, "", flushPolicy(cfg, "GATEWAY", metrics.SinkTimeSeries).Mode)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+)
+
+// system properties of presence, see docs/entity/entity.md.
+const (
+	PropertyOnline       = "_online"
+	PropertyLastSeen     = "_last_seen"
+	PropertyOfflineSince = "_offline_since"
+
+	// PresenceTimerID is id of the idle timer which marks entity offline.
+	PresenceTimerID = "_presence"
+)
+
+// presenceTimeout returns heartbeat timeout of entity type, presence is not tracked if zero.
+func presenceTimeout(cfg config.Presence, entityType string) time.Duration {
+	if timeout, ok := cfg.Types[strings.ToLower(entityType)]; ok {
+		return timeout
+	}
+	return cfg.Timeout
+}
+
+// IsOnline returns true if entity is online.
+func (b *Base) IsOnline() bool {
+	online, _ := b.KValues[PropertyOnline].(constraint.BoolNode)
+	return bool(online)
+}
+
+// markOnline updates presence of entity which receives properties at now,
+// the idle presence timer is set when entity connected.
+func (s *statem) markOnline(timeout time.Duration, now int64) []WatchKey {
+	if timeout <= 0 {
+		return nil
+	}
+
+	s.KValues[PropertyLastSeen] = constraint.IntNode(now)
+	watchKeys := []WatchKey{{EntityId: s.ID, PropertyKey: PropertyLastSeen}}
+	if s.IsOnline() {
+		return watchKeys
+	}
+
+	timer := Timer{ID: PresenceTimerID, EntityID: s.ID, Owner: s.Owner, Interval: timeout.Milliseconds(), Idle: true}
+	if err := s.stateManager.SetTimer(s.ctx, timer); nil != err {
+		log.Error("set presence timer", logger.EntityID(s.ID), zap.Error(err))
+	}
+
+	log.Info("entity connected", logger.EntityID(s.ID))
+	s.KValues[PropertyOnline] = constraint.BoolNode(true)
+	s.KValues[PropertyOfflineSince] = constraint.IntNode(0)
+	return append(watchKeys,
+		WatchKey{EntityId: s.ID, PropertyKey: PropertyOnline},
+		WatchKey{EntityId: s.ID, PropertyKey: PropertyOfflineSince})
+}
+
+// markOffline marks entity offline when presence timer fired at now.
+func (s *statem) markOffline(now int64) []WatchKey {
+	if !s.IsOnline() {
+		return nil
+	}
+
+	log.Info("entity disconnected", logger.EntityID(s.ID))
+	s.KValues[PropertyOnline] = constraint.BoolNode(false)
+	s.KValues[PropertyOfflineSince] = constraint.IntNode(now)
+	s.Version++
+	s.LastTime = now
+	return []WatchKey{
+		{EntityId: s.ID, PropertyKey: PropertyOnline},
+		{EntityId: s.ID, PropertyKey: PropertyOfflineSince},
+	}
+}
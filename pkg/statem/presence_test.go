@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+type timerManagerMock struct {
+	StateManagerMock
+	timers []Timer
+}
+
+func (m *timerManagerMock) SetTimer(ctx context.Context, timer Timer) error {
+	m.timers = append(m.timers, timer)
+	return nil
+}
+
+func Test_presenceTimeout(t *testing.T) {
+	cfg := config.Presence{Timeout: time.Minute, Types: map[string]time.Duration{"gateway": 5 * time.Minute, "sensor": 0}}
+	assert.Equal(t, time.Minute, presenceTimeout(cfg, "DEVICE"))
+	assert.Equal(t, 5*time.Minute, presenceTimeout(cfg, "GATEWAY"))
+	assert.Equal(t, time.Duration(0), presenceTimeout(cfg, "sensor"))
+	assert.Equal(t, time.Duration(0), presenceTimeout(config.Presence{}, "DEVICE"))
+}
+
+func TestPresence(t *testing.T) {
+	mgr := &timerManagerMock{}
+	base := Base{ID: "device123", Owner: "admin", KValues: map[string]constraint.Node{}}
+	sm, err := NewState(context.Background(), mgr, &base, nil)
+	assert.Nil(t, err)
+	state, _ := sm.(*statem)
+
+	// presence is not tracked without timeout.
+	assert.Empty(t, state.markOnline(0, 1000))
+	assert.False(t, state.IsOnline())
+
+	// connected, presence timer is set.
+	watchKeys := state.markOnline(time.Minute, 1000)
+	assert.Len(t, watchKeys, 3)
+	assert.True(t, state.IsOnline())
+	assert.Equal(t, constraint.IntNode(1000), state.KValues[PropertyLastSeen])
+	assert.Equal(t, constraint.IntNode(0), state.KValues[PropertyOfflineSince])
+	assert.Equal(t, []Timer{{ID: PresenceTimerID, EntityID: "device123", Owner: "admin", Interval: 60000, Idle: true}}, mgr.timers)
+
+	// heartbeat only updates last seen.
+	assert.Equal(t, []WatchKey{{EntityId: "device123", PropertyKey: PropertyLastSeen}}, state.markOnline(time.Minute, 2000))
+	assert.Equal(t, constraint.IntNode(2000), state.KValues[PropertyLastSeen])
+	assert.Len(t, mgr.timers, 1)
+
+	// disconnected when presence timer fired.
+	timer := Timer{ID: PresenceTimerID}
+	watchKeys = state.internelMessageHandler(timer.Message(62000))
+	assert.Equal(t, []WatchKey{
+		{EntityId: "device123", PropertyKey: PropertyOnline},
+		{EntityId: "device123", PropertyKey: PropertyOfflineSince},
+	}, watchKeys)
+	assert.False(t, state.IsOnline())
+	assert.Equal(t, constraint.IntNode(62000), state.KValues[PropertyOfflineSince])
+	assert.Empty(t, state.internelMessageHandler(timer.Message(63000)))
+
+	// reconnected.
+	assert.Len(t, state.markOnline(time.Minute, 70000), 3)
+	assert.Equal(t, constraint.IntNode(0), state.KValues[PropertyOfflineSince])
+	assert.Len(t, mgr.timers, 2)
+}
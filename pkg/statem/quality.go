@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"time"
+
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+// data quality of property values.
+const (
+	QualityGood  = "good"
+	QualityStale = "stale"
+	QualityBad   = "bad"
+)
+
+// PropertyMeta is metadata of property value.
+type PropertyMeta struct {
+	// SourceTime is timestamp of the value given by source, in milliseconds.
+	SourceTime int64 `json:"source_time" msgpack:"source_time" mapstructure:"source_time"`
+	// ReceiveTime is timestamp of the value received by server, in milliseconds.
+	ReceiveTime int64  `json:"receive_time" msgpack:"receive_time" mapstructure:"receive_time"`
+	Quality     string `json:"quality" msgpack:"quality" mapstructure:"quality"`
+}
+
+// ValidQuality returns true if quality is good or bad, stale is detected by server.
+func ValidQuality(quality string) bool {
+	return quality == QualityGood || quality == QualityBad
+}
+
+// GetPropertyMeta returns metadata of property at now, quality of value
+// not updated in expected update interval is stale.
+func (b *Base) GetPropertyMeta(propertyKey string, now int64) (PropertyMeta, bool) {
+	root := propertyRoot(propertyKey)
+	meta, has := b.Metas[root]
+	if !has {
+		return meta, false
+	}
+
+	cfg := b.Configs[root]
+	interval := cfg.UpdateInterval()
+	if meta.Quality == QualityGood && interval > 0 &&
+		time.Duration(now-meta.ReceiveTime)*time.Millisecond > interval {
+		meta.Quality = QualityStale
+	}
+	return meta, true
+}
+
+// updateMetas sets metadata of changed properties.
+func (s *statem) updateMetas(properties map[string]constraint.Node, sourceTime int64, quality string) {
+	receiveTime := time.Now().UnixNano() / 1e6
+	if sourceTime <= 0 {
+		sourceTime = receiveTime
+	}
+	if !ValidQuality(quality) {
+		quality = QualityGood
+	}
+
+	for key := range properties {
+		root := propertyRoot(key)
+		if _, has := s.KValues[root]; !has {
+			delete(s.Metas, root)
+			continue
+		}
+
+		if nil == s.Metas {
+			s.Metas = make(map[string]PropertyMeta)
+		}
+		s.Metas[root] = PropertyMeta{SourceTime: sourceTime, ReceiveTime: receiveTime, Quality: quality}
+	}
+}
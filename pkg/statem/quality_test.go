@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/util"
+)
+
+func TestPropertyMeta(t *testing.T) {
+	base := Base{
+		ID:      "device123",
+		KValues: map[string]constraint.Node{},
+		Configs: map[string]constraint.Config{
+			"temp":     {ID: "temp", Type: constraint.PropertyTypeFloat, Define: map[string]interface{}{constraint.DefineFieldUpdateInterval: "10s"}},
+			"humidity": {ID: "humidity", Type: constraint.PropertyTypeFloat, Define: map[string]interface{}{constraint.DefineFieldUpdateInterval: float64(5)}},
+		},
+	}
+
+	sm, err := NewState(context.Background(), NewStateManagerMock(), &base, nil)
+	assert.Nil(t, err)
+	state, _ := sm.(*statem)
+
+	state.invokePropertyMsg(PropertyMessage{
+		StateID:    "device123",
+		Operator:   "replace",
+		Properties: map[string]constraint.Node{"temp": constraint.NewNode(20), "humidity": constraint.NewNode(60)},
+		Timestamp:  1000,
+	})
+	state.invokePropertyMsg(PropertyMessage{
+		StateID:    "device123",
+		Operator:   "replace",
+		Properties: map[string]constraint.Node{"humidity": constraint.NewNode(-1)},
+		Quality:    QualityBad,
+	})
+
+	now := util.UnixMilli()
+	meta, has := state.GetPropertyMeta("temp", now)
+	assert.True(t, has)
+	assert.Equal(t, int64(1000), meta.SourceTime)
+	assert.GreaterOrEqual(t, now, meta.ReceiveTime)
+	assert.Equal(t, QualityGood, meta.Quality)
+
+	meta, _ = state.GetPropertyMeta("humidity", now)
+	assert.Equal(t, meta.ReceiveTime, meta.SourceTime)
+	assert.Equal(t, QualityBad, meta.Quality)
+
+	// not updated in expected update interval.
+	meta, _ = state.GetPropertyMeta("temp", now+11000)
+	assert.Equal(t, QualityStale, meta.Quality)
+	meta, _ = state.GetPropertyMeta("humidity", now+11000)
+	assert.Equal(t, QualityBad, meta.Quality)
+	_, has = state.GetPropertyMeta("status", now)
+	assert.False(t, has)
+
+	// metas are persisted with entity, removed with property.
+	bytes, err := EncodeBase(&state.Base)
+	assert.Nil(t, err)
+	decoded, err := DecodeBase(bytes)
+	assert.Nil(t, err)
+	assert.Equal(t, state.Metas, decoded.Metas)
+
+	state.invokePropertyMsg(PropertyMessage{StateID: "device123", Operator: "remove", Properties: map[string]constraint.Node{"temp": nil}})
+	_, has = state.GetPropertyMeta("temp", now)
+	assert.False(t, has)
+}
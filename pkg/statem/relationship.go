@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import "sort"
+
+// relationship types, see docs/relationship.
+const (
+	RelationshipContains    = "contains"
+	RelationshipConnectedTo = "connected-to"
+	RelationshipLocatedIn   = "located-in"
+)
+
+// search fields of relationships, documents are filtered by
+// relationships.{type} or related with target entity id.
+const (
+	SearchFieldRelationships = "relationships"
+	SearchFieldRelated       = "related"
+)
+
+// Relationship is a typed, directed edge from the entity to target entity.
+type Relationship struct {
+	Type       string                 `json:"type" msgpack:"type" mapstructure:"type"`
+	Target     string                 `json:"target" msgpack:"target" mapstructure:"target"`
+	Properties map[string]interface{} `json:"properties,omitempty" msgpack:"properties" mapstructure:"properties"`
+}
+
+// SetRelationship adds relationship, or replaces properties of existing one.
+func (b *Base) SetRelationship(rel Relationship) {
+	for index := range b.Relationships {
+		if b.Relationships[index].Type == rel.Type && b.Relationships[index].Target == rel.Target {
+			b.Relationships[index].Properties = rel.Properties
+			return
+		}
+	}
+
+	b.Relationships = append(b.Relationships, rel)
+	sort.SliceStable(b.Relationships, func(i, j int) bool {
+		if b.Relationships[i].Type != b.Relationships[j].Type {
+			return b.Relationships[i].Type < b.Relationships[j].Type
+		}
+		return b.Relationships[i].Target < b.Relationships[j].Target
+	})
+}
+
+// RemoveRelationship removes relationship, returns false if not exists.
+func (b *Base) RemoveRelationship(typ, target string) bool {
+	for index, rel := range b.Relationships {
+		if rel.Type == typ && rel.Target == target {
+			b.Relationships = append(b.Relationships[:index], b.Relationships[index+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetRelationships returns relationships of the type, all relationships if typ empty.
+func (b *Base) GetRelationships(typ string) []Relationship {
+	var rels []Relationship
+	for _, rel := range b.Relationships {
+		if typ == "" || rel.Type == typ {
+			rels = append(rels, rel)
+		}
+	}
+	return rels
+}
+
+// relationshipValues returns search values of relationships.
+func (b *Base) relationshipValues() (map[string]interface{}, []interface{}) {
+	related := make([]interface{}, 0, len(b.Relationships))
+	relationships := make(map[string]interface{})
+	for _, rel := range b.Relationships {
+		targets, _ := relationships[rel.Type].([]interface{})
+		relationships[rel.Type] = append(targets, rel.Target)
+		related = append(related, rel.Target)
+	}
+	return relationships, related
+}
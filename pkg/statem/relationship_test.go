@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+func TestBase_Relationships(t *testing.T) {
+	base := &Base{ID: "room1", Type: "ROOM", Owner: "admin", Configs: map[string]constraint.Config{}}
+	base.SetRelationship(Relationship{Type: RelationshipContains, Target: "device2"})
+	base.SetRelationship(Relationship{Type: RelationshipLocatedIn, Target: "building1"})
+	base.SetRelationship(Relationship{Type: RelationshipContains, Target: "device1", Properties: map[string]interface{}{"slot": "a"}})
+	base.SetRelationship(Relationship{Type: RelationshipContains, Target: "device1", Properties: map[string]interface{}{"slot": "b"}})
+
+	assert.Equal(t, []Relationship{
+		{Type: RelationshipContains, Target: "device1", Properties: map[string]interface{}{"slot": "b"}},
+		{Type: RelationshipContains, Target: "device2"},
+	}, base.GetRelationships(RelationshipContains))
+	assert.Len(t, base.GetRelationships(""), 3)
+
+	values := base.SearchValues()
+	assert.Equal(t, map[string]interface{}{
+		RelationshipContains:  []interface{}{"device1", "device2"},
+		RelationshipLocatedIn: []interface{}{"building1"},
+	}, values[SearchFieldRelationships])
+	assert.Equal(t, []interface{}{"device1", "device2", "building1"}, values[SearchFieldRelated])
+
+	// stored with entity.
+	bytes, err := EncodeBase(base)
+	assert.Nil(t, err)
+	decoded, err := DecodeBase(bytes)
+	assert.Nil(t, err)
+	assert.Equal(t, base.Relationships, decoded.Relationships)
+
+	assert.True(t, base.RemoveRelationship(RelationshipContains, "device2"))
+	assert.False(t, base.RemoveRelationship(RelationshipContains, "device2"))
+	assert.Len(t, base.GetRelationships(RelationshipContains), 1)
+}
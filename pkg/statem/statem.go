@@ -29,12 +29,18 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/config"
 	"github.com/tkeel-io/core/pkg/constraint"
 	"github.com/tkeel-io/core/pkg/environment"
 	"github.com/tkeel-io/core/pkg/logger"
 	"github.com/tkeel-io/core/pkg/mapper"
+	"github.com/tkeel-io/core/pkg/metrics"
+	"github.com/tkeel-io/core/pkg/resource/tseries"
+	"github.com/tkeel-io/core/pkg/tql"
+	"github.com/tkeel-io/core/pkg/tracing"
 	"github.com/tkeel-io/core/pkg/util"
 	"github.com/tkeel-io/kit/log"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -74,17 +80,21 @@ type MapperDesc struct {
 
 // EntityBase statem basic informatinon.
 type Base struct {
-	ID           string                       `json:"id" msgpack:"id" mapstructure:"id"`
-	Type         string                       `json:"type" msgpack:"type" mapstructure:"type"`
-	Owner        string                       `json:"owner" msgpack:"owner" mapstructure:"owner"`
-	Source       string                       `json:"source" msgpack:"source" mapstructure:"source"`
-	Version      int64                        `json:"version" msgpack:"version" mapstructure:"version"`
-	LastTime     int64                        `json:"last_time" msgpack:"last_time" mapstructure:"last_time"`
-	Mappers      []MapperDesc                 `json:"mappers" msgpack:"mappers" mapstructure:"mappers"`
-	KValues      map[string]constraint.Node   `json:"-" msgpack:"properties" mapstructure:"-"`
-	Configs      map[string]constraint.Config `json:"configs" msgpack:"-" mapstructure:"-"`
-	ConfigsBytes []byte                       `json:"-" msgpack:"configs_bytes" mapstructure:"-"`
-	Properties   map[string]interface{}       `json:"properties" msgpack:"-" mapstructure:"-"`
+	ID            string                       `json:"id" msgpack:"id" mapstructure:"id"`
+	Type          string                       `json:"type" msgpack:"type" mapstructure:"type"`
+	Owner         string                       `json:"owner" msgpack:"owner" mapstructure:"owner"`
+	Source        string                       `json:"source" msgpack:"source" mapstructure:"source"`
+	Version       int64                        `json:"version" msgpack:"version" mapstructure:"version"`
+	LastTime      int64                        `json:"last_time" msgpack:"last_time" mapstructure:"last_time"`
+	DeletedAt     int64                        `json:"deleted_at,omitempty" msgpack:"deleted_at" mapstructure:"deleted_at"`
+	Mappers       []MapperDesc                 `json:"mappers" msgpack:"mappers" mapstructure:"mappers"`
+	Relationships []Relationship               `json:"relationships,omitempty" msgpack:"relationships" mapstructure:"relationships"`
+	History       map[string]*HistoryRing      `json:"history,omitempty" msgpack:"history" mapstructure:"history"`
+	Metas         map[string]PropertyMeta      `json:"metas,omitempty" msgpack:"metas" mapstructure:"metas"`
+	KValues       map[string]constraint.Node   `json:"-" msgpack:"properties" mapstructure:"-"`
+	Configs       map[string]constraint.Config `json:"configs" msgpack:"-" mapstructure:"-"`
+	ConfigsBytes  []byte                       `json:"-" msgpack:"configs_bytes" mapstructure:"-"`
+	Properties    map[string]interface{}       `json:"properties" msgpack:"-" mapstructure:"-"`
 }
 
 func (b *Base) Copy() Base {
@@ -100,6 +110,28 @@ func (b *Base) CopyPropertiesForJSON() {
 	}
 }
 
+// SearchValues returns values of entity flushed into search engine.
+func (b *Base) SearchValues() map[string]interface{} {
+	values := make(map[string]interface{})
+	for key, val := range b.KValues {
+		values[key] = val.Value()
+	}
+
+	// basic fields.
+	values["id"] = b.ID
+	values["type"] = b.Type
+	values["owner"] = b.Owner
+	values["source"] = b.Source
+	values["version"] = b.Version
+	values["last_time"] = b.LastTime
+
+	// relationships.
+	if len(b.Relationships) > 0 {
+		values[SearchFieldRelationships], values[SearchFieldRelated] = b.relationshipValues()
+	}
+	return values
+}
+
 func (b *Base) DuplicateExpectValue() Base {
 	cp := Base{
 		ID:       b.ID,
@@ -113,6 +145,7 @@ func (b *Base) DuplicateExpectValue() Base {
 	}
 
 	cp.Mappers = append(cp.Mappers, b.Mappers...)
+	cp.Relationships = append(cp.Relationships, b.Relationships...)
 	return cp
 }
 
@@ -164,6 +197,7 @@ type statem struct {
 	mappers        map[string]mapper.Mapper              // key=mapperId
 	tentacles      map[string][]mapper.Tentacler         // key=Sid#propertyKey
 	cacheProps     map[string]map[string]constraint.Node // cache other property.
+	prevProps      map[string]map[string]constraint.Node // previous values of properties.
 	indexTentacles map[string][]mapper.Tentacler         // key=targetId(mapperId/Sid)
 
 	constraints        map[string]*constraint.Constraint
@@ -171,12 +205,15 @@ type statem struct {
 	tseriesConstraints sort.StringSlice
 
 	// mailbox & state runtime status.
-	mailBox      *mailbox
-	attached     int32
-	disposing    int32
-	nextFlushNum int32
-	stateManager StateManager
-	msgHandler   MessageHandler
+	mailBox       *mailbox
+	attached      int32
+	disposing     int32
+	lastFlushTime int64
+	flushers      []*sinkFlusher
+	// tseriesSamples buffers time-series samples not flushed.
+	tseriesSamples []tseries.TSeriesData
+	stateManager   StateManager
+	msgHandler     MessageHandler
 
 	status Status
 
@@ -202,9 +239,9 @@ func NewState(ctx context.Context, stateMgr StateManager, in *Base, msgHandler M
 		mailBox:        newMailbox(10),
 		status:         SMStatusActive,
 		disposing:      StateDisposingIdle,
-		nextFlushNum:   StateFlushPeried,
 		mappers:        make(map[string]mapper.Mapper),
 		cacheProps:     make(map[string]map[string]constraint.Node),
+		prevProps:      make(map[string]map[string]constraint.Node),
 		indexTentacles: make(map[string][]mapper.Tentacler),
 		constraints:    make(map[string]*constraint.Constraint),
 	}
@@ -219,6 +256,13 @@ func NewState(ctx context.Context, stateMgr StateManager, in *Base, msgHandler M
 		state.Configs = make(map[string]constraint.Config)
 	}
 
+	// flush search, state and time-series in order.
+	state.flushers = []*sinkFlusher{
+		newSinkFlusher(metrics.SinkSearch, state.flushSearch),
+		newSinkFlusher(metrics.SinkState, state.flushState),
+		newSinkFlusher(metrics.SinkTimeSeries, state.flushTimeSeries),
+	}
+
 	// set KValues into cacheProps.
 	state.cacheProps[in.ID] = state.KValues
 
@@ -253,7 +297,8 @@ func (s *statem) SetStatus(status Status) {
 func (s *statem) LoadEnvironments(env environment.ActorEnv) {
 	s.tentacles = make(map[string][]mapper.Tentacler)
 
-	// load actor mappers.
+	// load actor mappers, mappers removed from environment are dropped.
+	s.mappers = make(map[string]mapper.Mapper)
 	for _, m := range env.Mappers {
 		s.mappers[m.ID()] = m
 		log.Debug("load environments, mapper ", logger.EntityID(s.ID), zap.String("TQL", m.String()))
@@ -270,8 +315,30 @@ func (s *statem) LoadEnvironments(env environment.ActorEnv) {
 		log.Debug("load environments, tentacle ", logger.EntityID(s.ID), zap.String("tid", t.ID()), zap.String("target", t.TargetID()), zap.String("type", t.Type()), zap.Any("items", t.Items()))
 	}
 
-	s.flushState(context.Background())
-	s.activeTentacle(watchKeys)
+	s.flushState(s.ctx)
+	s.activeTentacle(s.ctx, watchKeys)
+}
+
+// Inspect returns runtime snapshot of state machine.
+func (s *statem) Inspect() Inspection {
+	inspection := Inspection{
+		Status:        s.status,
+		RuntimeStatus: stateRuntimeStatusString(atomic.LoadInt32(&s.attached)),
+		LastFlushTime: atomic.LoadInt64(&s.lastFlushTime),
+	}
+
+	for watchKey := range s.tentacles {
+		inspection.Tentacles = append(inspection.Tentacles, watchKey)
+	}
+	sort.Strings(inspection.Tentacles)
+
+	for _, m := range s.mappers {
+		inspection.Mappers = append(inspection.Mappers, MapperDesc{Name: m.Name(), TQLString: m.String()})
+	}
+	sort.Slice(inspection.Mappers, func(i, j int) bool {
+		return inspection.Mappers[i].Name < inspection.Mappers[j].Name
+	})
+	return inspection
 }
 
 func (s *statem) GetManager() StateManager {
@@ -499,7 +566,7 @@ func (s *statem) SetMessageHandler(msgHandler MessageHandler) {
 }
 
 // OnMessage recive statem input messages.
-func (s *statem) OnMessage(message Message) bool {
+func (s *statem) OnMessage(ctx context.Context, message Message) bool {
 	var (
 		reqID     = uuid()
 		attaching = false
@@ -510,6 +577,7 @@ func (s *statem) OnMessage(message Message) bool {
 	}
 
 	log.Debug("statem.OnMessage", logger.EntityID(s.ID), logger.RequestID(reqID))
+	defer observeMessage(message, time.Now())
 
 	switch msg := message.(type) {
 	case TentacleMsg:
@@ -518,15 +586,33 @@ func (s *statem) OnMessage(message Message) bool {
 		// dispose message.
 		watchKeys := s.msgHandler(message)
 		// active tentacles.
-		s.activeTentacle(watchKeys)
+		s.activeTentacle(ctx, watchKeys)
 	}
 
 	message.Promised(s)
-	s.flush(context.Background())
+	s.onFlushMessage(ctx)
 
 	return attaching
 }
 
+// observeMessage records processing latency of the message.
+func observeMessage(message Message, start time.Time) {
+	var msgType string
+	switch message.(type) {
+	case PropertyMessage:
+		msgType = "property"
+	case TentacleMsg:
+		msgType = "tentacle"
+	case MapperMessage:
+		msgType = "mapper"
+	case TimerMessage:
+		msgType = "timer"
+	default:
+		msgType = "unknown"
+	}
+	metrics.MessageDuration.WithLabelValues(msgType).Observe(time.Since(start).Seconds())
+}
+
 // InvokeMsg run loopHandler.
 func (s *statem) HandleLoop() {
 	var (
@@ -535,13 +621,6 @@ func (s *statem) HandleLoop() {
 	)
 
 	for {
-		if s.nextFlushNum == 0 {
-			// flush properties.
-			if err := s.flush(s.ctx); nil != err {
-				log.Error("flush state properties", logger.EntityID(s.ID), zap.Error(err))
-			}
-		}
-
 		// consume message from mailbox.
 		if message = s.mailBox.Get(); nil == message {
 			if Ensure > 0 {
@@ -566,7 +645,7 @@ func (s *statem) HandleLoop() {
 
 		// reset be surs.
 		Ensure = 3
-		s.nextFlushNum = (s.nextFlushNum + StateFlushPeried - 1) % StateFlushPeried
+		s.OnMessage(s.ctx, message)
 	}
 
 	log.Info("detached statem.", logger.EntityID(s.ID))
@@ -576,7 +655,14 @@ func (s *statem) HandleLoop() {
 func (s *statem) internelMessageHandler(message Message) []WatchKey {
 	switch msg := message.(type) {
 	case PropertyMessage:
-		return s.invokePropertyMsg(msg)
+		watchKeys := s.invokePropertyMsg(msg)
+		if msg.StateID == "" || msg.StateID == s.ID {
+			timeout := presenceTimeout(config.Get().Presence, s.Type)
+			watchKeys = append(watchKeys, s.markOnline(timeout, s.LastTime)...)
+		}
+		return watchKeys
+	case TimerMessage:
+		return s.invokeTimerMsg(msg)
 	default:
 		// invalid msg typs.
 		log.Error("undefine message type", logger.EntityID(s.ID), logger.MessageInst(msg))
@@ -598,6 +684,7 @@ func (s *statem) invokePropertyMsg(msg PropertyMessage) []WatchKey {
 	}
 
 	stateProps := s.cacheProps[setStateID]
+	s.keepPrevious(setStateID, msg.Properties)
 	for key, value := range msg.Properties {
 		if s.ID == msg.StateID {
 			if err := s.setProperty(constraint.NewPatchOperator(msg.Operator), key, value); nil != err {
@@ -611,6 +698,8 @@ func (s *statem) invokePropertyMsg(msg PropertyMessage) []WatchKey {
 
 	// set last active tims.
 	if setStateID == s.ID {
+		s.recordHistory(msg.Properties)
+		s.updateMetas(msg.Properties, msg.Timestamp, msg.Quality)
 		s.Version++
 		s.LastTime = time.Now().UnixNano() / 1e6
 	}
@@ -618,12 +707,32 @@ func (s *statem) invokePropertyMsg(msg PropertyMessage) []WatchKey {
 	return watchKeys
 }
 
+// invokeTimerMsg sets properties of fired timer into entity.
+func (s *statem) invokeTimerMsg(msg TimerMessage) []WatchKey {
+	log.Debug("timer fired", logger.EntityID(s.ID), zap.String("timer", msg.TimerID))
+	if msg.TimerID == PresenceTimerID {
+		return s.markOffline(msg.FireTime)
+	} else if len(msg.Properties) == 0 {
+		return nil
+	}
+
+	return s.invokePropertyMsg(PropertyMessage{
+		StateID:    s.ID,
+		Operator:   constraint.PatchOpReplace.String(),
+		Properties: msg.Properties,
+		Timestamp:  msg.FireTime,
+	})
+}
+
 // activeTentacle active tentacles.
-func (s *statem) activeTentacle(actives []mapper.WatchKey) { //nolint
+func (s *statem) activeTentacle(ctx context.Context, actives []mapper.WatchKey) { //nolint
 	if len(actives) == 0 {
 		return
 	}
 
+	ctx, span := tracing.Start(ctx, "statem.activeTentacle", attribute.String("entity.id", s.ID))
+	defer span.End()
+
 	var (
 		messages        = make(map[string]map[string]constraint.Node)
 		activeTentacles = make(map[string][]mapper.Tentacler)
@@ -695,11 +804,15 @@ func (s *statem) activeTentacle(actives []mapper.WatchKey) { //nolint
 			log.Warn("send message to self", logger.EntityID(s.ID))
 			continue
 		}
+		headers := Header{
+			MessageCtxHeaderOwner:    s.Owner,
+			MessageCtxHeaderSourceID: s.ID,
+			MessageCtxHeaderTargetID: stateID,
+		}
+		// propagate trace context to target entity.
+		tracing.Inject(ctx, headers)
 		s.stateManager.SendMsg(MessageContext{
-			Headers: Header{
-				MessageCtxHeaderSourceID: s.ID,
-				MessageCtxHeaderTargetID: stateID,
-			},
+			Headers: headers,
 			Message: PropertyMessage{
 				StateID:    s.ID,
 				Properties: msg,
@@ -708,11 +821,11 @@ func (s *statem) activeTentacle(actives []mapper.WatchKey) { //nolint
 	}
 
 	// active mapper.
-	s.activeMapper(activeTentacles)
+	s.activeMapper(ctx, activeTentacles)
 }
 
 // activeMapper active mappers.
-func (s *statem) activeMapper(actives map[string][]mapper.Tentacler) {
+func (s *statem) activeMapper(ctx context.Context, actives map[string][]mapper.Tentacler) {
 	if len(actives) == 0 {
 		return
 	}
@@ -720,8 +833,14 @@ func (s *statem) activeMapper(actives map[string][]mapper.Tentacler) {
 	var err error
 	var activeKeys []mapper.WatchKey
 	for mapperID := range actives {
+		mapperInst, ok := s.mappers[mapperID]
+		if !ok {
+			log.Warn("mapper not loaded", logger.EntityID(s.ID), logger.MapperID(mapperID))
+			continue
+		}
+
 		input := make(map[string]constraint.Node)
-		for _, tentacle := range s.mappers[mapperID].Tentacles() {
+		for _, tentacle := range mapperInst.Tentacles() {
 			if tentacle.Type() == mapper.TentacleTypeMapper {
 				for _, item := range tentacle.Items() {
 					var val constraint.Node
@@ -737,6 +856,9 @@ func (s *statem) activeMapper(actives map[string][]mapper.Tentacler) {
 					}
 					if nil != val {
 						input[item.String()] = val
+						if prev := s.getPrevious(item.EntityId, item.PropertyKey); nil != prev {
+							input[item.String()+tql.SuffixPrev] = prev
+						}
 					}
 				}
 			}
@@ -750,13 +872,19 @@ func (s *statem) activeMapper(actives map[string][]mapper.Tentacler) {
 		var properties map[string]constraint.Node
 
 		// excute mapper.
-		if properties, err = s.mappers[mapperID].Exec(input); nil != err {
+		_, span := tracing.Start(ctx, "mapper.Exec", attribute.String("entity.id", s.ID), attribute.String("mapper.id", mapperID))
+		metrics.MapperExecutions.Inc()
+		if properties, err = mapperInst.Exec(input); nil != err {
+			metrics.MapperFailures.Inc()
+			tracing.RecordError(span, err)
 			log.Error("exec statem mapper failed ", zap.Error(err))
 		}
+		span.End()
 
 		log.Debug("exec mapper", logger.MapperID(mapperID), zap.Any("input", input), zap.Any("output", properties))
 
 		if len(properties) > 0 {
+			s.keepPrevious(s.ID, properties)
 			for propertyKey, value := range properties {
 				if err = s.setProperty(constraint.PatchOpReplace, propertyKey, value); nil != err {
 					log.Error("set property failed", logger.EntityID(s.ID),
@@ -766,10 +894,12 @@ func (s *statem) activeMapper(actives map[string][]mapper.Tentacler) {
 				s.LastTime = time.Now().UnixNano() / 1e6
 				activeKeys = append(activeKeys, mapper.WatchKey{EntityId: s.ID, PropertyKey: propertyKey})
 			}
+			s.recordHistory(properties)
+			s.updateMetas(properties, 0, QualityGood)
 		}
 	}
 
-	s.activeTentacle(unique(activeKeys))
+	s.activeTentacle(ctx, unique(activeKeys))
 }
 
 func unique(actives []mapper.WatchKey) []mapper.WatchKey {
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+// MinTimerInterval is the minimum interval of periodic and idle timers,
+// timers are fired by ticker of runtime, see config flush.tick.
+const MinTimerInterval = time.Second
+
+// Timer delivers properties into entity at due time, repeatedly every interval
+// if interval set. Idle timer fires once entity receives no properties for interval.
+type Timer struct {
+	ID       string `json:"id"`
+	EntityID string `json:"entity_id"`
+	Owner    string `json:"owner"`
+	// DueTime is time of next firing in milliseconds, disarmed idle timer if zero.
+	DueTime int64 `json:"due_time"`
+	// Interval in milliseconds, one-shot timer if zero.
+	Interval   int64                  `json:"interval"`
+	Idle       bool                   `json:"idle"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Validate checks timer before registered.
+func (t *Timer) Validate() error {
+	minInterval := MinTimerInterval.Milliseconds()
+	switch {
+	case t.ID == "" || strings.ContainsAny(t.ID, ". "):
+		return errors.Wrapf(ErrTimerInvalid, "id %q", t.ID)
+	case t.EntityID == "":
+		return errors.Wrap(ErrTimerInvalid, "entity id empty")
+	case t.Interval < 0 || (t.Interval > 0 && t.Interval < minInterval):
+		return errors.Wrapf(ErrTimerInvalid, "interval less than %s", MinTimerInterval)
+	case t.Interval == 0 && (t.Idle || t.DueTime <= 0):
+		return errors.Wrap(ErrTimerInvalid, "due time or interval required")
+	}
+	return nil
+}
+
+// Arm schedules the timer registered or loaded at now, idle timer is
+// armed from now, others fire after interval if due time not set.
+func (t *Timer) Arm(now int64) {
+	if t.Idle || t.DueTime <= 0 {
+		t.DueTime = now + t.Interval
+	}
+}
+
+// Touch rearms idle timer when entity receives properties.
+func (t *Timer) Touch(now int64) {
+	if t.Idle {
+		t.DueTime = now + t.Interval
+	}
+}
+
+// Due returns true if timer should fire at now.
+func (t *Timer) Due(now int64) bool {
+	return t.DueTime > 0 && t.DueTime <= now
+}
+
+// Fire advances timer after fired at now, returns false if timer is done.
+// firings missed (e.g. while core is down) are fired only once.
+func (t *Timer) Fire(now int64) bool {
+	switch {
+	case t.Idle:
+		// disarmed until entity receives properties.
+		t.DueTime = 0
+	case t.Interval > 0:
+		t.DueTime += ((now-t.DueTime)/t.Interval + 1) * t.Interval
+	default:
+		return false
+	}
+	return true
+}
+
+// Message returns message delivered into entity when timer fires.
+func (t *Timer) Message(now int64) TimerMessage {
+	props := make(map[string]constraint.Node, len(t.Properties))
+	for key, val := range t.Properties {
+		props[key] = constraint.NewNode(val)
+	}
+	return TimerMessage{TimerID: t.ID, FireTime: now, Properties: props}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+func TestTimer_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		timer Timer
+		err   error
+	}{
+		{"one-shot", Timer{ID: "t1", EntityID: "device123", DueTime: 1000}, nil},
+		{"periodic", Timer{ID: "t1", EntityID: "device123", Interval: 60000}, nil},
+		{"idle", Timer{ID: "t1", EntityID: "device123", Interval: 60000, Idle: true}, nil},
+		{"empty id", Timer{EntityID: "device123", DueTime: 1000}, ErrTimerInvalid},
+		{"invalid id", Timer{ID: "t.1", EntityID: "device123", DueTime: 1000}, ErrTimerInvalid},
+		{"short interval", Timer{ID: "t1", EntityID: "device123", Interval: 10}, ErrTimerInvalid},
+		{"no due time", Timer{ID: "t1", EntityID: "device123"}, ErrTimerInvalid},
+		{"idle without interval", Timer{ID: "t1", EntityID: "device123", DueTime: 1000, Idle: true}, ErrTimerInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ErrorIs(t, test.timer.Validate(), test.err)
+		})
+	}
+}
+
+func TestTimer_Fire(t *testing.T) {
+	// one-shot timer is done after fired.
+	timer := Timer{ID: "t1", DueTime: 1000}
+	timer.Arm(500)
+	assert.False(t, timer.Due(999))
+	assert.True(t, timer.Due(1000))
+	assert.False(t, timer.Fire(1000))
+
+	// periodic timer fires once for missed firings.
+	timer = Timer{ID: "t2", Interval: 1000}
+	timer.Arm(500)
+	assert.Equal(t, int64(1500), timer.DueTime)
+	assert.True(t, timer.Fire(4700))
+	assert.Equal(t, int64(5500), timer.DueTime)
+
+	// idle timer is rearmed by properties, disarmed after fired.
+	timer = Timer{ID: "t3", Interval: 1000, Idle: true, DueTime: 100}
+	timer.Arm(500)
+	assert.Equal(t, int64(1500), timer.DueTime)
+	timer.Touch(1200)
+	assert.False(t, timer.Due(1500))
+	assert.True(t, timer.Fire(2200))
+	assert.False(t, timer.Due(5000))
+}
+
+func TestTimerMessage(t *testing.T) {
+	base := Base{ID: "device123", KValues: map[string]constraint.Node{}}
+	sm, err := NewState(context.Background(), NewStateManagerMock(), &base, nil)
+	assert.Nil(t, err)
+	state, _ := sm.(*statem)
+
+	timer := Timer{ID: "offline", Properties: map[string]interface{}{"status": "offline"}}
+	watchKeys := state.internelMessageHandler(timer.Message(1000))
+	assert.Equal(t, []WatchKey{{EntityId: "device123", PropertyKey: "status"}}, watchKeys)
+	assert.Equal(t, constraint.StringNode("offline"), state.KValues["status"])
+	assert.Equal(t, int64(1), state.Version)
+
+	// timer without properties changes nothing.
+	timer.Properties = nil
+	assert.Empty(t, state.internelMessageHandler(timer.Message(2000)))
+	assert.Equal(t, int64(1), state.Version)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"time"
 
 	dapr "github.com/dapr/go-sdk/client"
 	"github.com/tkeel-io/core/pkg/constraint"
@@ -29,14 +30,12 @@ import (
 )
 
 const (
-	StateFlushPeried = 10
-
 	MessageCtxHeaderOwner     = "x-owner"
 	MessageCtxHeaderType      = "x-type"
 	MessageCtxHeaderSourceID  = "x-source"
 	MessageCtxHeaderTargetID  = "x-target"
 	MessageCtxHeaderStateType = "x-state-type"
-	MessageCtxHeaderRequestID = "x-reqsuest-id"
+	MessageCtxHeaderRequestID = "x-request-id"
 	MessageCtxHeaderChannelID = "x-channel-id"
 
 	MapperOperatorAppend   = "append"
@@ -49,6 +48,8 @@ var (
 	errInvalidJSONPath   = errors.New("invalid JSONPath")
 	ErrInvalidProperties = errors.New("statem invalid properties")
 	ErrPropertyNotFound  = errors.New("property not found")
+	ErrTimerInvalid      = errors.New("invalid timer")
+	ErrTimerNotFound     = errors.New("timer not found")
 )
 
 type StateManager interface {
@@ -63,6 +64,8 @@ type StateManager interface {
 	PatchConfigs(context.Context, *Base, []*PatchData) error
 	AppendConfigs(context.Context, *Base) error
 	RemoveConfigs(context.Context, *Base, []string) error
+	SetTimer(context.Context, Timer) error
+	CancelTimer(ctx context.Context, entityID, timerID string) error
 }
 
 type StateMachiner interface {
@@ -87,13 +90,26 @@ type StateMachiner interface {
 	// LoadEnvironments load environments.
 	LoadEnvironments(environment.ActorEnv)
 	// OnMessage recv message from pubsub.
-	OnMessage(ctx Message) bool
+	OnMessage(ctx context.Context, msg Message) bool
 	// InvokeMsg dispose entity message.
 	HandleLoop()
 	// StateManager returns state manager.
 	GetManager() StateManager
 	// Flush flush entity data.
 	Flush(ctx context.Context) error
+	// FlushDue flushes sinks whose flush policy is due.
+	FlushDue(ctx context.Context, now time.Time) error
+	// Inspect returns runtime snapshot of state machine.
+	Inspect() Inspection
+}
+
+// Inspection is runtime snapshot of a state machine.
+type Inspection struct {
+	Status        Status
+	RuntimeStatus string
+	Tentacles     []string
+	Mappers       []MapperDesc
+	LastFlushTime int64
 }
 
 type Flusher interface {
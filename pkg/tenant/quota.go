@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/config"
+	"github.com/tkeel-io/core/pkg/util"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"golang.org/x/time/rate"
+)
+
+// Quotas enforces entity count and message rate quotas of tenants.
+type Quotas struct {
+	config   config.Tenant
+	counter  EntityCounter
+	limiters map[string]*rate.Limiter
+
+	lock sync.Mutex
+}
+
+func NewQuotas(cfg config.Tenant, counter EntityCounter) *Quotas {
+	return &Quotas{
+		config:   cfg,
+		counter:  counter,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Quota returns quota of the tenant.
+func (q *Quotas) Quota(owner string) config.Quota {
+	if quota, ok := q.config.Quotas[owner]; ok {
+		return quota
+	}
+	return q.config.Default
+}
+
+// CheckEntities returns ErrEntityQuotaExceeded if tenant can not create more entities.
+func (q *Quotas) CheckEntities(ctx context.Context, owner string) error {
+	quota := q.Quota(owner)
+	if quota.MaxEntities <= 0 {
+		return nil
+	}
+
+	count, err := q.counter.Count(ctx, owner)
+	if nil != err {
+		return errors.Wrap(err, "check entity quota")
+	} else if count >= quota.MaxEntities {
+		return ErrEntityQuotaExceeded
+	}
+	return nil
+}
+
+// AllowMessage returns ErrMessageRateLimited if message rate of tenant exceeded.
+func (q *Quotas) AllowMessage(owner string) error {
+	quota := q.Quota(owner)
+	if quota.MessageRate <= 0 {
+		return nil
+	}
+
+	q.lock.Lock()
+	limiter, ok := q.limiters[owner]
+	if !ok {
+		burst := quota.MessageBurst
+		if burst <= 0 {
+			burst = int(quota.MessageRate)
+		}
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(quota.MessageRate), burst)
+		q.limiters[owner] = limiter
+	}
+	q.lock.Unlock()
+
+	if !limiter.Allow() {
+		return ErrMessageRateLimited
+	}
+	return nil
+}
+
+// etcdCounter counts entities registered in etcd.
+type etcdCounter struct {
+	client clientv3.KV
+}
+
+func NewEtcdCounter(client clientv3.KV) EntityCounter {
+	return &etcdCounter{client: client}
+}
+
+func (c *etcdCounter) Count(ctx context.Context, owner string) (int64, error) {
+	res, err := c.client.Get(ctx, util.FormatTenantEntity(owner, ""), clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if nil != err {
+		return 0, errors.Wrap(err, "count tenant entities")
+	}
+	return res.Count, nil
+}
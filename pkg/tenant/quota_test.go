@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/config"
+)
+
+type fakeCounter map[string]int64
+
+func (f fakeCounter) Count(ctx context.Context, owner string) (int64, error) {
+	return f[owner], nil
+}
+
+func TestQuotas(t *testing.T) {
+	quotas := NewQuotas(config.Tenant{
+		Default: config.Quota{MaxEntities: 2},
+		Quotas: map[string]config.Quota{
+			"vip": {MessageRate: 1, MessageBurst: 2},
+		},
+	}, fakeCounter{"admin": 2, "tomas": 1, "vip": 100})
+
+	// entity quota.
+	assert.Equal(t, ErrEntityQuotaExceeded, quotas.CheckEntities(context.Background(), "admin"))
+	assert.Nil(t, quotas.CheckEntities(context.Background(), "tomas"))
+	assert.Nil(t, quotas.CheckEntities(context.Background(), "vip"))
+
+	// message rate.
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, quotas.AllowMessage("admin"))
+	}
+	assert.Nil(t, quotas.AllowMessage("vip"))
+	assert.Nil(t, quotas.AllowMessage("vip"))
+	assert.Equal(t, ErrMessageRateLimited, quotas.AllowMessage("vip"))
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrEntityQuotaExceeded = errors.New("tenant entity quota exceeded")
+	ErrMessageRateLimited  = errors.New("tenant message rate limited")
+)
+
+// EntityCounter counts entities of tenant.
+type EntityCounter interface {
+	Count(ctx context.Context, owner string) (int64, error)
+}
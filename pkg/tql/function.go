@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tql
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+/*
+   函数：prev 与 delta 引用源属性的上一个值，在解析前改写为带后缀的源属性.
+
+   insert into dev1 select prev(dev2.temp) as last_temp, delta(dev2.counter) as increment
+
+   prev(entity.property)  => entity.property@prev
+   delta(entity.property) => entity.property@delta
+*/
+
+// suffixes of source properties rewritten from functions.
+const (
+	SuffixPrev  = "@prev"
+	SuffixDelta = "@delta"
+)
+
+// functions of source properties.
+const (
+	FunctionPrev  = "prev"
+	FunctionDelta = "delta"
+)
+
+var functionRegexp = regexp.MustCompile(`\b(` + FunctionPrev + `|` + FunctionDelta + `)\s*\(\s*([^()\s]*)\s*\)`)
+
+// rewriteFunctions rewrites prev and delta functions into suffixed source properties.
+func rewriteFunctions(text string) (string, error) {
+	var err error
+	text = functionRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		groups := functionRegexp.FindStringSubmatch(match)
+		function, source := groups[1], groups[2]
+		if index := strings.Index(source, "."); index <= 0 || index == len(source)-1 {
+			err = errors.Wrapf(ErrTQLSyntax, "%s requires entity.property, got %q", function, source)
+		} else if strings.HasPrefix(source, ReferencePrefix) {
+			err = errors.Wrapf(ErrTQLSyntax, "%s of reference %q not supported", function, source)
+		} else if strings.HasSuffix(source, ".*") {
+			err = errors.Wrapf(ErrTQLSyntax, "%s of wildcard %q not supported", function, source)
+		}
+
+		if function == FunctionDelta {
+			return source + SuffixDelta
+		}
+		return source + SuffixPrev
+	})
+	return text, err
+}
+
+// trimSuffixes returns source property key without function suffixes.
+func trimSuffixes(propertyKey string) string {
+	propertyKey = strings.TrimSuffix(propertyKey, SuffixPrev)
+	return strings.TrimSuffix(propertyKey, SuffixDelta)
+}
+
+// deltaValues returns values with delta of source properties whose previous values are given.
+func deltaValues(in map[string]constraint.Node) map[string]constraint.Node {
+	out := make(map[string]constraint.Node, len(in))
+	for key, val := range in {
+		out[key] = val
+	}
+
+	for key, prev := range in {
+		if !strings.HasSuffix(key, SuffixPrev) {
+			continue
+		}
+
+		sourceKey := strings.TrimSuffix(key, SuffixPrev)
+		if val := delta(in[sourceKey], prev); nil != val {
+			out[sourceKey+SuffixDelta] = val
+		}
+	}
+	return out
+}
+
+// delta returns difference of numeric values, nil if not numeric.
+func delta(val, prev constraint.Node) constraint.Node {
+	if nil == val || nil == prev {
+		return nil
+	}
+
+	current, ok1 := val.Value().(int64)
+	last, ok2 := prev.Value().(int64)
+	if ok1 && ok2 {
+		return constraint.IntNode(current - last)
+	} else if isNumeric(val) && isNumeric(prev) {
+		return constraint.FloatNode(toFloat(val) - toFloat(prev))
+	}
+	return nil
+}
+
+func isNumeric(node constraint.Node) bool {
+	return node.Type() == constraint.Integer || node.Type() == constraint.Float
+}
+
+func toFloat(node constraint.Node) float64 {
+	switch val := node.Value().(type) {
+	case int64:
+		return float64(val)
+	case float64:
+		return val
+	}
+	return 0
+}
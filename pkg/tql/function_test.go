@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+func TestNewTQL_Functions(t *testing.T) {
+	tqlInst, err := NewTQL(`insert into dev1 select prev(dev2.temp) as last_temp, delta( dev2.counter ) as increment, dev2.temp - prev(dev2.temp) as change`)
+	assert.Nil(t, err)
+	assert.Equal(t, []TentacleConfig{{SourceEntity: "dev2", PropertyKeys: []string{"temp", "counter"}}}, tqlInst.Tentacles())
+
+	// previous values are not known yet.
+	out, err := tqlInst.Exec(map[string]constraint.Node{
+		"dev2.temp":    constraint.NewNode(21.5),
+		"dev2.counter": constraint.NewNode(10),
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, out)
+
+	out, err = tqlInst.Exec(map[string]constraint.Node{
+		"dev2.temp":          constraint.NewNode(21.5),
+		"dev2.temp@prev":     constraint.NewNode(20),
+		"dev2.counter":       constraint.NewNode(10),
+		"dev2.counter@prev":  constraint.NewNode(7),
+		"dev2.counter@delta": constraint.NewNode(100),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20), out["last_temp"].Value())
+	assert.Equal(t, int64(3), out["increment"].Value())
+	assert.Equal(t, 1.5, out["change"].Value())
+
+	for _, text := range []string{
+		"insert into dev1 select prev(temp) as temp",
+		"insert into dev1 select delta(dev2.) as temp",
+		"insert into dev1 select prev(dev2.*)",
+		"insert into room1 select prev(#sensors.temp) as temp from #sensors = related('contains')",
+	} {
+		_, err = NewTQL(text)
+		assert.ErrorIs(t, err, ErrTQLSyntax, text)
+		assert.ErrorIs(t, Validate(text), ErrTQLSyntax, text)
+	}
+	assert.Nil(t, Validate("insert into dev1 select delta(dev2.metrics.count) as count"))
+}
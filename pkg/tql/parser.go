@@ -18,11 +18,13 @@ package tql
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/antlr/antlr4/runtime/Go/antlr"
 	"github.com/dop251/goja"
+	"github.com/pkg/errors"
 	"github.com/tkeel-io/core/pkg/constraint"
 	"github.com/tkeel-io/core/pkg/tql/parser"
 	"github.com/tkeel-io/kit/log"
@@ -120,7 +122,7 @@ func (l *Listener) ExitSource(c *parser.SourceContext) {
 	SourceEntity := c.SourceEntity().GetText()
 	PropertyEntity := c.PropertyEntity().GetText()
 	l.pushS(SourceEntity)
-	l.AddTentacle(SourceEntity, strings.TrimPrefix(PropertyEntity, Sep))
+	l.AddTentacle(SourceEntity, trimSuffixes(strings.TrimPrefix(PropertyEntity, Sep)))
 }
 
 // ExitSourceEntity is called when production entity is exited.
@@ -285,10 +287,17 @@ func (l *Listener) GetComputeResults(in map[string][]byte) map[string]constraint
 		}
 
 		if len(params) == len(evalCtx.ParamKeys) {
+			// replace longer keys first, keys may be prefix of suffixed keys.
+			keys := make([]string, 0, len(params))
+			for key := range params {
+				keys = append(keys, key)
+			}
+			sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+
 			evalExpr := evalCtx.Field
-			for key, val := range params {
+			for _, key := range keys {
 				evalExpr = strings.ReplaceAll(
-					evalExpr, key, " "+string(val)+" ")
+					evalExpr, key, " "+string(params[key])+" ")
 			}
 			val, _ := goja.New().RunString(evalExpr)
 			out[evalCtx.TargetPropertyKey] = constraint.NewNode(val.Export())
@@ -319,3 +328,42 @@ func Parse(input string) (*Listener, error) {
 	antlr.ParseTreeWalkerDefault.Walk(listener, p.Root())
 	return listener, nil
 }
+
+// syntaxErrorListener collects syntax errors of lexer and parser.
+type syntaxErrorListener struct {
+	*antlr.DefaultErrorListener
+	errs []string
+}
+
+func (l *syntaxErrorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
+	l.errs = append(l.errs, fmt.Sprintf("line %d:%d %s", line, column, msg))
+}
+
+// Validate checks syntax of a tql string expression.
+func Validate(input string) error {
+	selectText, refs, err := splitReferences(input)
+	if nil != err {
+		return err
+	} else if selectText, err = rewriteFunctions(selectText); nil != err {
+		return err
+	}
+
+	listener := &syntaxErrorListener{DefaultErrorListener: antlr.NewDefaultErrorListener()}
+	lexer := parser.NewTQLLexer(antlr.NewInputStream(selectText))
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(listener)
+
+	p := parser.NewTQLParser(antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel))
+	p.RemoveErrorListeners()
+	p.AddErrorListener(listener)
+	p.Root()
+
+	if len(listener.errs) > 0 {
+		return errors.Wrap(ErrTQLSyntax, strings.Join(listener.errs, "; "))
+	} else if len(refs) > 0 {
+		// check references are used.
+		_, err = NewTQL(input)
+		return err
+	}
+	return nil
+}
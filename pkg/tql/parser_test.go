@@ -81,6 +81,12 @@ func TestParser(t *testing.T) {
 	}
 }
 
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate("insert into device123 select device234.temp as temp"))
+	assert.Nil(t, Validate("insert into device123 select device234.*"))
+	assert.ErrorIs(t, Validate("insert into select"), ErrTQLSyntax)
+}
+
 func TestGetParseConfigs(t *testing.T) {
 	tqlString := "insert into device123 select device234.*"
 
@@ -0,0 +1,370 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tql
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/relation"
+)
+
+/*
+   引用：通过关系或者搜索条件动态解析源实体，在 select 之后声明.
+
+   insert into room1 select #sensors.temp as temp
+   from #sensors = avg(search(type = 'thermometer', room = this))
+
+   from      := binding (',' binding)*
+   binding   := REF '=' AGG '(' selector ')' | REF '=' selector
+   selector  := related '(' STRING [',' STRING] ')'
+              | search '(' cond ((',' | and) cond)* ')'
+   cond      := IDENT '=' (STRING | NUMBER | true | false | this)
+*/
+
+// ReferencePrefix is prefix of reference names.
+const ReferencePrefix = "#"
+
+// reference kinds.
+const (
+	ReferenceRelated = "related"
+	ReferenceSearch  = "search"
+)
+
+// aggregations of reference values, values are collected into an array if not aggregated.
+const (
+	AggregateAvg   = "avg"
+	AggregateSum   = "sum"
+	AggregateMin   = "min"
+	AggregateMax   = "max"
+	AggregateCount = "count"
+)
+
+// ReferenceThis stands for the target entity in conditions.
+const ReferenceThis = "this"
+
+const (
+	keywordFrom = "from"
+	keywordAnd  = "and"
+)
+
+// Condition matches entities whose Field equals Value, or the target entity if This.
+type Condition struct {
+	Field string
+	Value interface{}
+	This  bool
+}
+
+// Reference is a source reference resolved to entities by relationships or search.
+type Reference struct {
+	Name string
+	Kind string
+	// RelationshipType and Direction of related entities, all types if empty.
+	RelationshipType string
+	Direction        string
+	// Conditions of searched entities.
+	Conditions []Condition
+	Aggregate  string
+}
+
+// bindReferences moves referenced sources out of source entities,
+// a reference must be used with property keys.
+func (cfg *TQLConfig) bindReferences(refs []Reference) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	used := make(map[string]bool)
+	for _, ref := range refs {
+		used[ref.Name] = false
+	}
+
+	var entities []string
+	for _, entityID := range cfg.SourceEntities {
+		if _, has := used[entityID]; !has {
+			entities = append(entities, entityID)
+		}
+	}
+
+	for _, tentacle := range cfg.Tentacles {
+		if _, has := used[tentacle.SourceEntity]; !has {
+			continue
+		}
+		for _, key := range tentacle.PropertyKeys {
+			if key == "*" {
+				return errors.Wrapf(ErrTQLSyntax, "reference %s selects all properties", tentacle.SourceEntity)
+			}
+		}
+		used[tentacle.SourceEntity] = true
+	}
+
+	for _, ref := range refs {
+		if !used[ref.Name] {
+			return errors.Wrapf(ErrTQLSyntax, "reference %s not used", ref.Name)
+		}
+	}
+
+	cfg.SourceEntities = entities
+	cfg.References = refs
+	return nil
+}
+
+// splitReferences splits references declared after from out of TQL text.
+func splitReferences(text string) (string, []Reference, error) {
+	index := indexFrom(text)
+	if index < 0 {
+		return text, nil, nil
+	}
+
+	refs, err := parseReferences(text[index+len(keywordFrom):])
+	if nil != err {
+		return "", nil, errors.Wrap(err, "parse references")
+	}
+	return text[:index], refs, nil
+}
+
+// indexFrom returns index of the from keyword out of strings, -1 if not found.
+func indexFrom(text string) int {
+	quoted := false
+	for index := 0; index < len(text); index++ {
+		if text[index] == '\'' {
+			quoted = !quoted
+			continue
+		} else if quoted || index == 0 || !unicode.IsSpace(rune(text[index-1])) {
+			continue
+		}
+
+		end := index + len(keywordFrom)
+		if end < len(text) && strings.EqualFold(text[index:end], keywordFrom) &&
+			(unicode.IsSpace(rune(text[end])) || strings.HasPrefix(text[end:], ReferencePrefix)) {
+			return index
+		}
+	}
+	return -1
+}
+
+func parseReferences(text string) ([]Reference, error) {
+	tokens, err := tokenize(text)
+	if nil != err {
+		return nil, err
+	}
+
+	p := &refParser{tokens: tokens}
+	var refs []Reference
+	names := make(map[string]struct{})
+	for {
+		ref, err := p.binding()
+		if nil != err {
+			return nil, err
+		} else if _, has := names[ref.Name]; has {
+			return nil, errors.Wrapf(ErrTQLSyntax, "reference %s declared twice", ref.Name)
+		}
+		names[ref.Name] = struct{}{}
+		refs = append(refs, ref)
+
+		if p.done() {
+			return refs, nil
+		} else if err = p.expect(","); nil != err {
+			return nil, err
+		}
+	}
+}
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	for index := 0; index < len(text); {
+		ch := text[index]
+		switch {
+		case unicode.IsSpace(rune(ch)):
+			index++
+		case strings.IndexByte("(),=", ch) >= 0:
+			tokens = append(tokens, token{text: string(ch)})
+			index++
+		case ch == '\'':
+			var value strings.Builder
+			for index++; ; index++ {
+				if index >= len(text) {
+					return nil, errors.Wrap(ErrTQLSyntax, "unterminated string")
+				} else if text[index] == '\'' {
+					if index+1 < len(text) && text[index+1] == '\'' {
+						value.WriteByte('\'')
+						index++
+						continue
+					}
+					index++
+					break
+				}
+				value.WriteByte(text[index])
+			}
+			tokens = append(tokens, token{text: value.String(), quoted: true})
+		default:
+			start := index
+			for index < len(text) && !unicode.IsSpace(rune(text[index])) && strings.IndexByte("(),='", text[index]) < 0 {
+				index++
+			}
+			tokens = append(tokens, token{text: text[start:index]})
+		}
+	}
+	return tokens, nil
+}
+
+// refParser is a recursive descent parser of references.
+type refParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *refParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *refParser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *refParser) next() (token, error) {
+	if p.done() {
+		return token{}, errors.Wrap(ErrTQLSyntax, "unexpected end of references")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *refParser) expect(text string) error {
+	tok, err := p.next()
+	if nil != err {
+		return err
+	} else if tok.quoted || !strings.EqualFold(tok.text, text) {
+		return errors.Wrapf(ErrTQLSyntax, "expect %q, got %q", text, tok.text)
+	}
+	return nil
+}
+
+func (p *refParser) binding() (Reference, error) {
+	name, err := p.next()
+	if nil != err {
+		return Reference{}, err
+	} else if name.quoted || !strings.HasPrefix(name.text, ReferencePrefix) || len(name.text) == len(ReferencePrefix) {
+		return Reference{}, errors.Wrapf(ErrTQLSyntax, "invalid reference name %q", name.text)
+	} else if err = p.expect("="); nil != err {
+		return Reference{}, err
+	}
+
+	ref := Reference{Name: name.text}
+	switch kind := strings.ToLower(p.peek().text); kind {
+	case AggregateAvg, AggregateSum, AggregateMin, AggregateMax, AggregateCount:
+		p.pos++
+		ref.Aggregate = kind
+		if err = p.expect("("); nil != err {
+			return ref, err
+		} else if err = p.selector(&ref); nil != err {
+			return ref, err
+		}
+		return ref, p.expect(")")
+	default:
+		return ref, p.selector(&ref)
+	}
+}
+
+func (p *refParser) selector(ref *Reference) error {
+	kind, err := p.next()
+	if nil != err {
+		return err
+	} else if err = p.expect("("); nil != err {
+		return err
+	}
+
+	switch ref.Kind = strings.ToLower(kind.text); ref.Kind {
+	case ReferenceRelated:
+		return p.related(ref)
+	case ReferenceSearch:
+		return p.search(ref)
+	default:
+		return errors.Wrapf(ErrTQLSyntax, "unknown reference %q", kind.text)
+	}
+}
+
+func (p *refParser) related(ref *Reference) error {
+	typ, err := p.next()
+	if nil != err {
+		return err
+	} else if !typ.quoted {
+		return errors.Wrapf(ErrTQLSyntax, "relationship type %q should be quoted", typ.text)
+	}
+
+	ref.RelationshipType, ref.Direction = typ.text, relation.DirectionOut
+	if p.peek().text == "," && !p.peek().quoted {
+		p.pos++
+		direction, err := p.next()
+		if nil != err {
+			return err
+		} else if !direction.quoted || (direction.text != relation.DirectionOut && direction.text != relation.DirectionIn) {
+			return errors.Wrapf(ErrTQLSyntax, "relationship direction %q, one of 'out'|'in'", direction.text)
+		}
+		ref.Direction = direction.text
+	}
+	return p.expect(")")
+}
+
+func (p *refParser) search(ref *Reference) error {
+	for {
+		field, err := p.next()
+		if nil != err {
+			return err
+		} else if field.quoted || field.text == "" {
+			return errors.Wrapf(ErrTQLSyntax, "invalid condition field %q", field.text)
+		} else if err = p.expect("="); nil != err {
+			return err
+		}
+
+		value, err := p.next()
+		if nil != err {
+			return err
+		}
+		cond := Condition{Field: field.text}
+		switch {
+		case value.quoted:
+			cond.Value = value.text
+		case strings.EqualFold(value.text, ReferenceThis):
+			cond.This = true
+		case strings.EqualFold(value.text, "true"), strings.EqualFold(value.text, "false"):
+			cond.Value = strings.EqualFold(value.text, "true")
+		default:
+			if cond.Value, err = strconv.ParseFloat(value.text, 64); nil != err {
+				return errors.Wrapf(ErrTQLSyntax, "invalid condition value %q", value.text)
+			}
+		}
+		ref.Conditions = append(ref.Conditions, cond)
+
+		switch next := p.peek(); {
+		case !next.quoted && (next.text == "," || strings.EqualFold(next.text, keywordAnd)):
+			p.pos++
+		default:
+			return p.expect(")")
+		}
+	}
+}
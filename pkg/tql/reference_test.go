@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+func TestNewTQL_References(t *testing.T) {
+	tqlInst, err := NewTQL(`insert into room1 select #sensors.temp as temp, #devices.status as status, room1.name as name
+		FROM #sensors = avg(search(type = 'thermometer' and room = this, floor = 3)),
+		#devices = related('contains')`)
+	assert.Nil(t, err)
+	assert.Equal(t, "room1", tqlInst.Target())
+	assert.Equal(t, []string{"room1"}, tqlInst.Entities())
+	assert.Equal(t, []Reference{
+		{
+			Name: "#sensors", Kind: ReferenceSearch, Aggregate: AggregateAvg,
+			Conditions: []Condition{{Field: "type", Value: "thermometer"}, {Field: "room", This: true}, {Field: "floor", Value: float64(3)}},
+		},
+		{Name: "#devices", Kind: ReferenceRelated, RelationshipType: "contains", Direction: "out"},
+	}, tqlInst.References())
+	assert.Len(t, tqlInst.Tentacles(), 3)
+
+	// references are evaluated like entities.
+	out, err := tqlInst.Exec(map[string]constraint.Node{
+		"#sensors.temp":   constraint.NewNode(21.5),
+		"#devices.status": constraint.NewNode([]interface{}{"on", "off"}),
+		"room1.name":      constraint.NewNode("room"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 21.5, out["temp"].Value())
+	assert.Equal(t, []interface{}{"on", "off"}, out["status"].Value())
+
+	// from in strings is not a keyword.
+	tqlInst, err = NewTQL("insert into device1 select device2.name + ' from ' as name")
+	assert.Nil(t, err)
+	assert.Empty(t, tqlInst.References())
+
+	for _, text := range []string{
+		"insert into room1 select #sensors.temp as temp from sensors = related('contains')",
+		"insert into room1 select #sensors.temp as temp from #sensors = related(contains)",
+		"insert into room1 select #sensors.temp as temp from #sensors = related('contains', 'up')",
+		"insert into room1 select #sensors.temp as temp from #sensors = median(related('contains'))",
+		"insert into room1 select #sensors.temp as temp from #sensors = search(type = thermometer)",
+		"insert into room1 select #sensors.* from #sensors = related('contains')",
+		"insert into room1 select device1.temp as temp from #sensors = related('contains')",
+		"insert into room1 select #sensors.temp as temp from #sensors = related('contains'), #sensors = related('')",
+	} {
+		_, err = NewTQL(text)
+		assert.ErrorIs(t, err, ErrTQLSyntax, text)
+		assert.ErrorIs(t, Validate(text), ErrTQLSyntax, text)
+	}
+	assert.Nil(t, Validate("insert into room1 select #sensors.temp as temp from #sensors = related('located-in', 'in')"))
+}
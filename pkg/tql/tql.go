@@ -29,11 +29,20 @@ type tql struct {
 
 func NewTQL(tqlString string) (TQL, error) {
 	var cfg TQLConfig
-	listener, err := Parse(tqlString)
+	selectText, refs, err := splitReferences(tqlString)
+	if nil != err {
+		return nil, errors.Wrap(err, "parse TQL")
+	} else if selectText, err = rewriteFunctions(selectText); nil != err {
+		return nil, errors.Wrap(err, "parse TQL functions")
+	}
+
+	listener, err := Parse(selectText)
 	if nil != err {
 		return nil, errors.Wrap(err, "parse TQL")
 	} else if cfg, err = listener.GetParseConfigs(); nil != err {
 		return nil, errors.Wrap(err, "parse TQL and get configurations")
+	} else if err = cfg.bindReferences(refs); nil != err {
+		return nil, errors.Wrap(err, "parse TQL references")
 	}
 	return &tql{text: tqlString, listener: listener, config: cfg}, nil
 }
@@ -43,11 +52,16 @@ func (t *tql) Target() string {
 	return t.config.TargetEntity
 }
 
-// Entities returns source entities.
+// Entities returns source entities, references are not included.
 func (t *tql) Entities() []string {
 	return t.config.SourceEntities
 }
 
+// References returns source references.
+func (t *tql) References() []Reference {
+	return t.config.References
+}
+
 // Tentacles returns tentacles.
 func (t *tql) Tentacles() []TentacleConfig {
 	return t.config.Tentacles
@@ -56,7 +70,7 @@ func (t *tql) Tentacles() []TentacleConfig {
 // Exec execute MQL.
 func (t *tql) Exec(in map[string]constraint.Node) (map[string]constraint.Node, error) {
 	input := make(map[string][]byte)
-	for key, val := range in {
+	for key, val := range deltaValues(in) {
 		switch val.Type() {
 		case constraint.String:
 			input[key] = []byte("'" + val.String() + "'")
@@ -16,7 +16,12 @@ limitations under the License.
 
 package tql
 
-import "github.com/tkeel-io/core/pkg/constraint"
+import (
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/constraint"
+)
+
+var ErrTQLSyntax = errors.New("TQL syntax error")
 
 /*
    1. 对MQL的静态分析
@@ -38,11 +43,13 @@ type TQLConfig struct { // nolint
 	TargetEntity   string
 	SourceEntities []string
 	Tentacles      []TentacleConfig
+	References     []Reference
 }
 
 type TQL interface {
 	Target() string
 	Entities() []string
 	Tentacles() []TentacleConfig
+	References() []Reference
 	Exec(map[string]constraint.Node) (map[string]constraint.Node, error)
 }
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/emicklei/go-restful"
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+
+	instrumentationName = "github.com/tkeel-io/core"
+)
+
+var ErrExporterInvalid = errors.New("invalid tracing exporter")
+
+// Init setup global tracer provider and W3C trace context propagator,
+// returns func to flush and stop exporting spans.
+func Init(ctx context.Context, serviceName string, cfg config.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		err      error
+		exporter sdktrace.SpanExporter
+	)
+
+	switch cfg.Exporter {
+	case "":
+		// tracing disabled.
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, errors.Wrap(ErrExporterInvalid, cfg.Exporter)
+	}
+
+	if nil != err {
+		return nil, errors.Wrap(err, "create tracing exporter")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of core.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Inject injects trace context of ctx into message headers.
+func Inject(ctx context.Context, headers map[string]string) {
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+}
+
+// Extract returns ctx with trace context extracted from message headers.
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, headerCarrier(headers))
+}
+
+// HTTPFilter starts span for every http request, remote trace context in headers is continued.
+func HTTPFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	ctx := otel.GetTextMapPropagator().Extract(req.Request.Context(), propagation.HeaderCarrier(req.Request.Header))
+	ctx, span := Start(ctx, req.Request.Method+" "+req.Request.URL.Path,
+		semconv.HTTPMethodKey.String(req.Request.Method),
+		semconv.HTTPTargetKey.String(req.Request.URL.Path))
+	defer span.End()
+
+	req.Request = req.Request.WithContext(ctx)
+	chain.ProcessFilter(req, resp)
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode()))
+	if resp.StatusCode() >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode()))
+	}
+}
+
+// RecordError records err on span.
+func RecordError(span trace.Span, err error) {
+	if nil != err {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// headerCarrier adapts message headers to propagation.TextMapCarrier.
+type headerCarrier map[string]string
+
+func (c headerCarrier) Get(key string) string { return c[key] }
+
+func (c headerCarrier) Set(key, value string) { c[key] = value }
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
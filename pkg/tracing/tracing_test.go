@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/config"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInit(t *testing.T) {
+	shutdown, err := Init(context.Background(), "core", config.Tracing{})
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+
+	_, err = Init(context.Background(), "core", config.Tracing{Exporter: "zipkin"})
+	assert.ErrorIs(t, err, ErrExporterInvalid)
+}
+
+func TestPropagation(t *testing.T) {
+	_, err := Init(context.Background(), "core", config.Tracing{})
+	assert.Nil(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	// span of sender.
+	ctx, span := Start(context.Background(), "sender")
+	headers := map[string]string{"x-owner": "admin"}
+	Inject(ctx, headers)
+	span.End()
+	assert.NotEmpty(t, headers["traceparent"])
+
+	// span of receiver, continues trace of sender.
+	_, child := Start(Extract(context.Background(), headers), "receiver")
+	child.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), spans[1].Parent().SpanID())
+	assert.Equal(t, trace.SpanKindInternal, spans[1].SpanKind())
+}
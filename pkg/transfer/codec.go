@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const tarEntityDir = "entities"
+
+type recordWriter interface {
+	Write(record *Record) error
+	Close() error
+}
+
+type recordReader interface {
+	// Read returns next record, io.EOF if no more.
+	Read() (*Record, error)
+}
+
+func newRecordWriter(w io.Writer, format string) (recordWriter, error) {
+	switch format {
+	case FormatNDJSON, "":
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatTar:
+		return &tarWriter{writer: tar.NewWriter(w), modTime: time.Now()}, nil
+	default:
+		return nil, errors.Wrap(ErrFormatInvalid, format)
+	}
+}
+
+// newRecordReader detects format of r, tar archives are recognized by the ustar magic.
+func newRecordReader(r io.Reader) recordReader {
+	buffered := bufio.NewReader(r)
+	header, _ := buffered.Peek(262)
+	if len(header) == 262 && bytes.HasPrefix(header[257:], []byte("ustar")) {
+		return &tarReader{reader: tar.NewReader(buffered)}
+	}
+	return &ndjsonReader{decoder: json.NewDecoder(buffered)}
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(record *Record) error {
+	return errors.Wrap(w.encoder.Encode(record), "write record")
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+type ndjsonReader struct {
+	decoder *json.Decoder
+	line    int
+}
+
+func (r *ndjsonReader) Read() (*Record, error) {
+	var record Record
+	r.line++
+	if err := r.decoder.Decode(&record); errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	} else if nil != err {
+		return nil, errors.Wrapf(ErrRecordInvalid, "record %d: %s", r.line, err.Error())
+	}
+	return &record, nil
+}
+
+type tarWriter struct {
+	writer  *tar.Writer
+	modTime time.Time
+}
+
+func (w *tarWriter) Write(record *Record) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if nil != err {
+		return errors.Wrap(err, "write record")
+	}
+
+	header := &tar.Header{
+		Name:    path.Join(tarEntityDir, url.PathEscape(record.ID)+".json"),
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: w.modTime,
+	}
+	if err = w.writer.WriteHeader(header); nil != err {
+		return errors.Wrap(err, "write record")
+	}
+	_, err = w.writer.Write(data)
+	return errors.Wrap(err, "write record")
+}
+
+func (w *tarWriter) Close() error {
+	return errors.Wrap(w.writer.Close(), "close tar archive")
+}
+
+type tarReader struct {
+	reader *tar.Reader
+}
+
+func (r *tarReader) Read() (*Record, error) {
+	for {
+		header, err := r.reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		} else if nil != err {
+			return nil, errors.Wrap(err, "read tar archive")
+		}
+
+		// skip directories and files out of entities/.
+		if header.Typeflag != tar.TypeReg || !strings.HasPrefix(header.Name, tarEntityDir+"/") {
+			continue
+		}
+
+		var record Record
+		if err = json.NewDecoder(r.reader).Decode(&record); nil != err {
+			return nil, errors.Wrapf(ErrRecordInvalid, "%s: %s", header.Name, err.Error())
+		}
+		return &record, nil
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/util"
+	"github.com/tkeel-io/kit/log"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// Exporter writes entities with their configs, properties, mappers and relationships.
+type Exporter struct {
+	stateClient StateClient
+	kv          clientv3.KV
+	flusher     Flusher
+}
+
+// NewExporter returns a new Exporter, resident entities are flushed before exporting unless flusher is nil.
+func NewExporter(stateClient StateClient, kv clientv3.KV, flusher Flusher) *Exporter {
+	return &Exporter{stateClient: stateClient, kv: kv, flusher: flusher}
+}
+
+// Export writes entities sorted by id into w, returns the number of exported entities.
+// registered entities and mappers are read from the same etcd revision, soft-deleted entities are skipped.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, opts ExportOptions) (int, error) {
+	writer, err := newRecordWriter(w, opts.Format)
+	if nil != err {
+		return 0, err
+	}
+
+	entityPrefix := util.FormatEntity("")
+	res, err := e.kv.Get(ctx, entityPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if nil != err {
+		return 0, errors.Wrap(err, "list registered entities")
+	}
+
+	mappers, err := e.mappers(ctx, res.Header.GetRevision())
+	if nil != err {
+		return 0, err
+	}
+
+	ids := make([]string, 0, len(res.Kvs))
+	for _, kv := range res.Kvs {
+		ids = append(ids, strings.TrimPrefix(string(kv.Key), entityPrefix))
+	}
+	sort.Strings(ids)
+
+	count := 0
+	for _, id := range ids {
+		record, err := e.record(ctx, id, opts)
+		if nil != err {
+			return count, err
+		} else if nil == record {
+			continue
+		}
+
+		record.Mappers = mappers[id]
+		if err = writer.Write(record); nil != err {
+			return count, err
+		}
+		count++
+	}
+
+	log.Info("export entities", zap.Int("count", count),
+		zap.String("format", opts.Format), zap.String("owner", opts.Owner), zap.String("type", opts.Type))
+	return count, writer.Close()
+}
+
+// mappers returns mappers of entities at revision, the latest if revision is zero.
+func (e *Exporter) mappers(ctx context.Context, revision int64) (map[string][]statem.MapperDesc, error) {
+	opts := []clientv3.OpOption{clientv3.WithPrefix()}
+	if revision > 0 {
+		opts = append(opts, clientv3.WithRev(revision))
+	}
+
+	res, err := e.kv.Get(ctx, util.EtcdMapperPrefix+".", opts...)
+	if nil != err {
+		return nil, errors.Wrap(err, "list mappers")
+	}
+
+	mappers := make(map[string][]statem.MapperDesc)
+	for _, kv := range res.Kvs {
+		// core.mapper.{type}.{entityID}.{name} .
+		segments := strings.Split(string(kv.Key), ".")
+		if len(segments) != 5 {
+			log.Warn("export mappers, invalid key", zap.String("key", string(kv.Key)))
+			continue
+		}
+		mappers[segments[3]] = append(mappers[segments[3]],
+			statem.MapperDesc{Name: segments[4], TQLString: string(kv.Value)})
+	}
+	return mappers, nil
+}
+
+// record returns record of entity, nil if entity not exists, deleted or filtered out.
+func (e *Exporter) record(ctx context.Context, id string, opts ExportOptions) (*Record, error) {
+	// flush the entity if resident, state store is up to date.
+	if nil != e.flusher {
+		if err := e.flusher.FlushResident(ctx, id); nil != err {
+			return nil, errors.Wrap(err, "flush entity "+id)
+		}
+	}
+
+	item, err := e.stateClient.GetState(ctx, EntityStateName, id)
+	if nil != err {
+		return nil, errors.Wrap(err, "get entity state "+id)
+	} else if nil == item || len(item.Value) == 0 {
+		log.Warn("export entities, state not found", logger.EntityID(id))
+		return nil, nil
+	}
+
+	base, err := statem.DecodeBase(item.Value)
+	if nil != err {
+		return nil, errors.Wrap(err, "decode entity state "+id)
+	} else if base.DeletedAt > 0 ||
+		(opts.Owner != "" && base.Owner != opts.Owner) ||
+		(opts.Type != "" && base.Type != opts.Type) {
+		return nil, nil
+	}
+
+	record := &Record{
+		ID:            base.ID,
+		Type:          base.Type,
+		Owner:         base.Owner,
+		Source:        base.Source,
+		Version:       base.Version,
+		Configs:       base.Configs,
+		Relationships: base.Relationships,
+		Properties:    make(map[string]interface{}, len(base.KValues)),
+	}
+	for key, val := range base.KValues {
+		record.Properties[key] = val.Value()
+	}
+	return record, nil
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/entities"
+	"github.com/tkeel-io/core/pkg/logger"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/tql"
+	"github.com/tkeel-io/kit/log"
+	"go.uber.org/zap"
+)
+
+// Importer creates or updates entities from exported records through entity manager,
+// so that quotas, tenant checks and audit apply.
+type Importer struct {
+	entityManager entities.EntityManager
+}
+
+func NewImporter(entityManager entities.EntityManager) *Importer {
+	return &Importer{entityManager: entityManager}
+}
+
+// Import reads records from r, ndjson or tar archive. entities are imported first, then mappers
+// and relationships, which may refer to entities imported later.
+// existing entities are skipped in create mode, in upsert mode their properties are merged,
+// configs replaced, mappers and relationships added or replaced.
+func (i *Importer) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*Report, error) {
+	if opts.Mode != ModeCreate && opts.Mode != ModeUpsert {
+		return nil, errors.Wrap(ErrModeInvalid, opts.Mode)
+	}
+
+	var records []*Record
+	report := &Report{DryRun: opts.DryRun}
+	reader := newRecordReader(r)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if nil != err {
+			return nil, err
+		}
+
+		result := Result{ID: record.ID}
+		if record.Owner == "" {
+			record.Owner = opts.Owner
+		}
+
+		if opts.Owner != "" && record.Owner != opts.Owner {
+			result.Status, result.Error = StatusFailed, ErrRecordOwner.Error()
+			log.Warn("import entity", logger.EntityID(record.ID), zap.String("owner", record.Owner), zap.Error(ErrRecordOwner))
+		} else if result.Status, err = i.importEntity(ctx, record, opts); nil != err {
+			result.Status, result.Error = StatusFailed, err.Error()
+			log.Warn("import entity", logger.EntityID(record.ID), zap.Error(err))
+		}
+		report.Results = append(report.Results, result)
+		records = append(records, record)
+	}
+
+	for index, record := range records {
+		result := &report.Results[index]
+		if result.Status == StatusCreated || result.Status == StatusUpdated {
+			if err := i.importLinks(ctx, record, opts); nil != err {
+				result.Status, result.Error = StatusFailed, err.Error()
+				log.Warn("import entity", logger.EntityID(record.ID), zap.Error(err))
+			}
+		}
+
+		switch result.Status {
+		case StatusCreated:
+			report.Created++
+		case StatusUpdated:
+			report.Updated++
+		case StatusSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+	}
+
+	log.Info("import entities", zap.String("mode", opts.Mode), zap.Bool("dry_run", opts.DryRun),
+		zap.Int("created", report.Created), zap.Int("updated", report.Updated),
+		zap.Int("skipped", report.Skipped), zap.Int("failed", report.Failed))
+	return report, nil
+}
+
+// importEntity creates or updates entity with properties and configs of record.
+func (i *Importer) importEntity(ctx context.Context, record *Record, opts ImportOptions) (string, error) {
+	base, err := recordBase(record)
+	if nil != err {
+		return StatusFailed, err
+	}
+
+	existing, err := i.entityManager.GetProperties(ctx, &statem.Base{ID: record.ID})
+	switch {
+	case errors.Is(err, entities.ErrEntityNotFound):
+		if opts.DryRun {
+			return StatusCreated, nil
+		}
+		_, err = i.entityManager.CreateEntity(ctx, base)
+		return StatusCreated, errors.Wrap(err, "create entity")
+	case nil != err:
+		return StatusFailed, errors.Wrap(err, "get entity")
+	case existing.Owner != record.Owner:
+		return StatusFailed, ErrOwnerMismatch
+	case opts.Mode == ModeCreate:
+		return StatusSkipped, nil
+	case opts.DryRun:
+		return StatusUpdated, nil
+	}
+
+	if len(base.KValues) > 0 {
+		if _, err = i.entityManager.SetProperties(ctx, base); nil != err {
+			return StatusFailed, errors.Wrap(err, "set properties")
+		}
+	}
+	if len(base.Configs) > 0 {
+		if _, err = i.entityManager.SetConfigs(ctx, base); nil != err {
+			return StatusFailed, errors.Wrap(err, "set configs")
+		}
+	}
+	return StatusUpdated, nil
+}
+
+// importLinks appends mappers and adds relationships of record.
+func (i *Importer) importLinks(ctx context.Context, record *Record, opts ImportOptions) error {
+	base := &statem.Base{ID: record.ID, Type: record.Type, Owner: record.Owner, Source: record.Source}
+	if opts.DryRun {
+		for _, mp := range record.Mappers {
+			if _, err := tql.NewTQL(mp.TQLString); nil != err {
+				return errors.Wrap(err, "mapper "+mp.Name)
+			}
+		}
+		return nil
+	}
+
+	if len(record.Mappers) > 0 {
+		base.Mappers = record.Mappers
+		if _, err := i.entityManager.AppendMapper(ctx, base); nil != err {
+			return errors.Wrap(err, "append mappers")
+		}
+	}
+
+	for _, rel := range record.Relationships {
+		if _, err := i.entityManager.AddRelationship(ctx, base, rel); nil != err {
+			return errors.Wrapf(err, "add relationship %s/%s", rel.Type, rel.Target)
+		}
+	}
+	return nil
+}
+
+// recordBase returns entity of record, configs are normalized as configs set by API.
+func recordBase(record *Record) (*statem.Base, error) {
+	if record.ID == "" {
+		return nil, errors.Wrap(ErrRecordInvalid, "empty entity id")
+	}
+
+	base := &statem.Base{
+		ID:      record.ID,
+		Type:    record.Type,
+		Owner:   record.Owner,
+		Source:  record.Source,
+		KValues: make(map[string]constraint.Node, len(record.Properties)),
+		Configs: make(map[string]constraint.Config, len(record.Configs)),
+	}
+	for key, val := range record.Properties {
+		base.KValues[key] = constraint.NewNode(val)
+	}
+	for key, cfg := range record.Configs {
+		parsed, err := constraint.ParseConfigsFrom(cfg)
+		if nil != err {
+			return nil, errors.Wrapf(ErrRecordInvalid, "config %s: %s", key, err.Error())
+		}
+		base.Configs[key] = parsed
+	}
+	return base, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/entities"
+	"github.com/tkeel-io/core/pkg/internal/fake"
+	"github.com/tkeel-io/core/pkg/statem"
+	"github.com/tkeel-io/core/pkg/util"
+)
+
+type fakeFlusher struct {
+	flushed []string
+}
+
+func (f *fakeFlusher) FlushResident(ctx context.Context, id string) error {
+	f.flushed = append(f.flushed, id)
+	return nil
+}
+
+// fakeManager keeps imported entities in memory.
+type fakeManager struct {
+	entities.EntityManager
+	entities map[string]*statem.Base
+	writes   int
+}
+
+func (m *fakeManager) GetProperties(ctx context.Context, en *statem.Base) (*statem.Base, error) {
+	if base, has := m.entities[en.ID]; has {
+		return base, nil
+	}
+	return nil, entities.ErrEntityNotFound
+}
+
+func (m *fakeManager) CreateEntity(ctx context.Context, en *statem.Base) (*statem.Base, error) {
+	m.writes++
+	m.entities[en.ID] = en
+	return en, nil
+}
+
+func (m *fakeManager) SetProperties(ctx context.Context, en *statem.Base) (*statem.Base, error) {
+	m.writes++
+	for key, val := range en.KValues {
+		m.entities[en.ID].KValues[key] = val
+	}
+	return m.entities[en.ID], nil
+}
+
+func (m *fakeManager) SetConfigs(ctx context.Context, en *statem.Base) (*statem.Base, error) {
+	m.writes++
+	m.entities[en.ID].Configs = en.Configs
+	return m.entities[en.ID], nil
+}
+
+func (m *fakeManager) AppendMapper(ctx context.Context, en *statem.Base) (*statem.Base, error) {
+	m.writes++
+	m.entities[en.ID].Mappers = append(m.entities[en.ID].Mappers, en.Mappers...)
+	return m.entities[en.ID], nil
+}
+
+func (m *fakeManager) AddRelationship(ctx context.Context, en *statem.Base, rel statem.Relationship) (*statem.Base, error) {
+	m.writes++
+	if _, has := m.entities[rel.Target]; !has {
+		return nil, entities.ErrEntityNotFound
+	}
+	m.entities[en.ID].SetRelationship(rel)
+	return m.entities[en.ID], nil
+}
+
+func newExporter(t *testing.T) (*Exporter, *fakeFlusher) {
+	device := &statem.Base{
+		ID: "device1", Type: "DEVICE", Owner: "admin", Source: "dm", Version: 3,
+		KValues: map[string]constraint.Node{"temp": constraint.NewNode(20)},
+		Configs: map[string]constraint.Config{"temp": {ID: "temp", Type: "int", Enabled: true, Define: map[string]interface{}{}}},
+	}
+	room := &statem.Base{ID: "room1", Type: "ROOM", Owner: "admin", KValues: map[string]constraint.Node{}}
+	room.SetRelationship(statem.Relationship{Type: "contains", Target: "device1"})
+	other := &statem.Base{ID: "device2", Type: "DEVICE", Owner: "other", KValues: map[string]constraint.Node{}}
+	deleted := &statem.Base{ID: "device3", Type: "DEVICE", Owner: "admin", DeletedAt: 1000, KValues: map[string]constraint.Node{}}
+
+	state, kv := fake.NewState(), fake.NewKV()
+	for _, base := range []*statem.Base{device, room, other, deleted} {
+		bytes, err := statem.EncodeBase(base)
+		assert.Nil(t, err)
+		state.Set(base.ID, bytes)
+		kv.Set(util.FormatEntity(base.ID), base.Type)
+	}
+	kv.Set(util.FormatMapper("ROOM", "room1", "temp"), "insert into room1 select device1.temp as temp")
+
+	flusher := &fakeFlusher{}
+	return NewExporter(state, kv, flusher), flusher
+}
+
+func TestExportImport(t *testing.T) {
+	exporter, flusher := newExporter(t)
+
+	for _, format := range []string{FormatNDJSON, FormatTar} {
+		var buf bytes.Buffer
+		count, err := exporter.Export(context.Background(), &buf, ExportOptions{Format: format, Owner: "admin"})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, count)
+
+		// create.
+		manager := &fakeManager{entities: map[string]*statem.Base{}}
+		importer := NewImporter(manager)
+		report, err := importer.Import(context.Background(), bytes.NewReader(buf.Bytes()), ImportOptions{Mode: ModeCreate})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, []Result{{ID: "device1", Status: StatusCreated}, {ID: "room1", Status: StatusCreated}}, report.Results)
+
+		device := manager.entities["device1"]
+		assert.Equal(t, "admin", device.Owner)
+		assert.Equal(t, float64(20), device.KValues["temp"].Value())
+		assert.Equal(t, "int", device.Configs["temp"].Type)
+		room := manager.entities["room1"]
+		assert.Equal(t, []statem.MapperDesc{{Name: "temp", TQLString: "insert into room1 select device1.temp as temp"}}, room.Mappers)
+		assert.Len(t, room.GetRelationships("contains"), 1)
+
+		// existing entities skipped.
+		writes := manager.writes
+		report, err = importer.Import(context.Background(), bytes.NewReader(buf.Bytes()), ImportOptions{Mode: ModeCreate})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, report.Skipped)
+		assert.Equal(t, writes, manager.writes)
+
+		// dry run.
+		report, err = importer.Import(context.Background(), bytes.NewReader(buf.Bytes()), ImportOptions{Mode: ModeUpsert, DryRun: true})
+		assert.Nil(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 2, report.Updated)
+		assert.Equal(t, writes, manager.writes)
+
+		// upsert, another owner is rejected.
+		manager.entities["room1"].Owner = "other"
+		report, err = importer.Import(context.Background(), bytes.NewReader(buf.Bytes()), ImportOptions{Mode: ModeUpsert})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, ErrOwnerMismatch.Error(), report.Results[1].Error)
+
+		// records owned by others are rejected when importing as an owner.
+		manager = &fakeManager{entities: map[string]*statem.Base{}}
+		report, err = NewImporter(manager).Import(context.Background(), bytes.NewReader(buf.Bytes()), ImportOptions{Mode: ModeCreate, Owner: "other"})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, report.Failed)
+		assert.Equal(t, ErrRecordOwner.Error(), report.Results[0].Error)
+		assert.Empty(t, manager.entities)
+	}
+	// entities flushed one by one before read.
+	assert.Equal(t, []string{"device1", "device2", "device3", "room1"}, flusher.flushed[:4])
+	assert.Len(t, flusher.flushed, 8)
+
+	_, err := exporter.Export(context.Background(), &bytes.Buffer{}, ExportOptions{Format: "xml"})
+	assert.ErrorIs(t, err, ErrFormatInvalid)
+	_, err = NewImporter(&fakeManager{}).Import(context.Background(), &bytes.Buffer{}, ImportOptions{Mode: "merge"})
+	assert.ErrorIs(t, err, ErrModeInvalid)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The tKeel Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer
+
+import (
+	"context"
+	"errors"
+
+	dapr "github.com/dapr/go-sdk/client"
+	"github.com/tkeel-io/core/pkg/constraint"
+	"github.com/tkeel-io/core/pkg/statem"
+)
+
+const EntityStateName = "core-state"
+
+// formats of exported entities.
+const (
+	// FormatNDJSON is one json record per line.
+	FormatNDJSON = "ndjson"
+	// FormatTar is a tar archive, one json file per entity under entities/.
+	FormatTar = "tar"
+)
+
+// import modes.
+const (
+	// ModeCreate creates absent entities, existing entities are skipped.
+	ModeCreate = "create"
+	// ModeUpsert creates absent entities and updates existing ones.
+	ModeUpsert = "upsert"
+)
+
+// status of imported records.
+const (
+	StatusCreated = "created"
+	StatusUpdated = "updated"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+var (
+	ErrFormatInvalid = errors.New("invalid format, one of ndjson|tar")
+	ErrModeInvalid   = errors.New("invalid import mode, one of create|upsert")
+	ErrRecordInvalid = errors.New("invalid record")
+	ErrOwnerMismatch = errors.New("entity exists with another owner")
+	ErrRecordOwner   = errors.New("record owned by another owner")
+)
+
+type StateClient interface {
+	// GetState returns entity state.
+	GetState(ctx context.Context, storeName, key string) (*dapr.StateItem, error)
+}
+
+// Flusher flushes resident entities into state store.
+type Flusher interface {
+	// FlushResident flushes the entity if resident, nothing to flush otherwise.
+	FlushResident(ctx context.Context, id string) error
+}
+
+// Record is an exported entity, mappers are read from etcd and the rest from state store.
+type Record struct {
+	ID            string                       `json:"id"`
+	Type          string                       `json:"type"`
+	Owner         string                       `json:"owner"`
+	Source        string                       `json:"source"`
+	Version       int64                        `json:"version"`
+	Properties    map[string]interface{}       `json:"properties,omitempty"`
+	Configs       map[string]constraint.Config `json:"configs,omitempty"`
+	Mappers       []statem.MapperDesc          `json:"mappers,omitempty"`
+	Relationships []statem.Relationship        `json:"relationships,omitempty"`
+}
+
+type ExportOptions struct {
+	Format string
+	// Owner and Type filter exported entities if not empty.
+	Owner string
+	Type  string
+}
+
+type ImportOptions struct {
+	Mode string
+	// DryRun reports what would be imported without writing.
+	DryRun bool
+	// Owner of imported entities if not empty, records owned by others are rejected,
+	// records without owner are imported as the owner.
+	Owner string
+}
+
+type Result struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report of an import, results are in the order of records.
+type Report struct {
+	DryRun  bool
+	Created int
+	Updated int
+	Skipped int
+	Failed  int
+	Results []Result
+}
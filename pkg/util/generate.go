@@ -6,8 +6,62 @@ const (
 	EtcdMapperPrefix = "core.mapper"
 	// core.mapper.{type}.{entityID}.{name}.
 	fmtMapperString = "core.mapper.%s.%s.%s"
+
+	EtcdEntityPrefix = "core.entity"
+	// core.entity.{entityID}.
+	fmtEntityString = "core.entity.%s"
+
+	EtcdTenantPrefix = "core.tenant"
+	// core.tenant.{owner}.entity.{entityID}.
+	fmtTenantEntityString = "core.tenant.%s.entity.%s"
+
+	EtcdAuditPrefix = "core.audit"
+	// core.audit.{entityID}.{timestamp}, timestamp in nanoseconds padded to sort by time.
+	fmtAuditString = "core.audit.%s.%019d"
+
+	EtcdTimelinePrefix = "core.timeline"
+	// core.timeline.{timestamp}.{entityID}, audit events of all entities by time.
+	fmtTimelineString = "core.timeline.%019d.%s"
+
+	EtcdDeletedPrefix = "core.deleted"
+	// core.deleted.{entityID}.
+	fmtDeletedString = "core.deleted.%s"
+
+	EtcdTimerPrefix = "core.timer"
+	// core.timer.{entityID}.{timerID}.
+	fmtTimerString = "core.timer.%s.%s"
 )
 
 func FormatMapper(typ, id, name string) string {
 	return fmt.Sprintf(fmtMapperString, typ, id, name)
 }
+
+// FormatEntity returns registry key of the entity.
+func FormatEntity(id string) string {
+	return fmt.Sprintf(fmtEntityString, id)
+}
+
+// FormatTenantEntity returns key of the entity in tenant, used for counting entities of tenant.
+func FormatTenantEntity(owner, id string) string {
+	return fmt.Sprintf(fmtTenantEntityString, owner, id)
+}
+
+// FormatAudit returns key of the audit event of entity.
+func FormatAudit(id string, timestamp int64) string {
+	return fmt.Sprintf(fmtAuditString, id, timestamp)
+}
+
+// FormatTimeline returns key of the audit event in timeline of all entities.
+func FormatTimeline(timestamp int64, id string) string {
+	return fmt.Sprintf(fmtTimelineString, timestamp, id)
+}
+
+// FormatDeleted returns key of the tombstone of soft-deleted entity.
+func FormatDeleted(id string) string {
+	return fmt.Sprintf(fmtDeletedString, id)
+}
+
+// FormatTimer returns key of the timer of entity, prefix of timers of entity if timerID empty.
+func FormatTimer(entityID, timerID string) string {
+	return fmt.Sprintf(fmtTimerString, entityID, timerID)
+}
@@ -10,3 +10,28 @@ func Test_FormatMapper(t *testing.T) {
 	// format print.
 	assert.Equal(t, "core.mapper.BASIC.device123.mapper123", FormatMapper("BASIC", "device123", "mapper123"))
 }
+
+func Test_FormatEntity(t *testing.T) {
+	assert.Equal(t, "core.entity.device123", FormatEntity("device123"))
+}
+
+func Test_FormatTenantEntity(t *testing.T) {
+	assert.Equal(t, "core.tenant.admin.entity.device123", FormatTenantEntity("admin", "device123"))
+}
+
+func Test_FormatAudit(t *testing.T) {
+	assert.Equal(t, "core.audit.device123.0000000001640995200", FormatAudit("device123", 1640995200))
+}
+
+func Test_FormatTimeline(t *testing.T) {
+	assert.Equal(t, "core.timeline.0000000001640995200.device123", FormatTimeline(1640995200, "device123"))
+}
+
+func Test_FormatDeleted(t *testing.T) {
+	assert.Equal(t, "core.deleted.device123", FormatDeleted("device123"))
+}
+
+func Test_FormatTimer(t *testing.T) {
+	assert.Equal(t, "core.timer.device123.offline", FormatTimer("device123", "offline"))
+	assert.Equal(t, "core.timer.device123.", FormatTimer("device123", ""))
+}
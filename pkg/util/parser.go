@@ -13,6 +13,7 @@ const _splitter = ","
 var _searchDriverMap = map[string]driver.Type{
 	"es":            driver.ElasticsearchDriver,
 	"elasticsearch": driver.ElasticsearchDriver,
+	"embedded":      driver.EmbeddedDriver,
 }
 
 func ParseSearchEngine(dsn string) (drive driver.Type, username, password string, urls []string, err error) {
@@ -25,5 +26,9 @@ func ParseSearchEngine(dsn string) (drive driver.Type, username, password string
 	username = u.User.Username()
 	password, _ = u.User.Password()
 	drive = _searchDriverMap[strings.ToLower(u.Scheme)]
+	if drive == driver.EmbeddedDriver {
+		// embedded://path/to/index.
+		urls[0] = u.Host + u.Path
+	}
 	return drive, username, password, urls, nil
 }
@@ -34,6 +34,14 @@ func TestParseSearchEngine(t *testing.T) {
 			err      error
 		}{drive: driver.ElasticsearchDriver, username: "username", password: "password", urls: []string{"localhost", "otherhost"}, err: nil},
 		},
+		{"embedded://data/search", struct {
+			drive    driver.Type
+			username string
+			password string
+			urls     []string
+			err      error
+		}{drive: driver.EmbeddedDriver, username: "", password: "", urls: []string{"data/search"}, err: nil},
+		},
 	}
 
 	for _, test := range tests {